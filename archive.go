@@ -0,0 +1,264 @@
+package gosu
+
+import (
+	"archive/zip"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/hndada/gosu/db"
+	"github.com/hndada/gosu/format/osu"
+)
+
+// DeleteArchive determines whether to delete an archive after importing.
+var DeleteArchive = false
+
+// ImportArchives extracts .osz and .zip archives in music root to folders.
+// A folder is named as "<SetId> <Artist> - <Title>", which is same as osu!'s.
+// Archives already imported are skipped. Archives failed to import are left as they are.
+func ImportArchives(musicRoot string) (dirs []string) {
+	records := make(map[string]archiveRecord)
+	_ = db.LoadData(archiveRecordsFilename(), &records)
+	dirs = importArchives(musicRoot, records)
+	db.SaveData(archiveRecordsFilename(), &records)
+	return
+}
+
+// archiveRecord is of an archive already imported.
+// An archive with the same size and modified time is regarded as the same.
+type archiveRecord struct {
+	Size    int64
+	ModTime int64 // In Unix nanoseconds.
+	Dir     string
+}
+
+func archiveRecordsFilename() string {
+	if db.MarshalType == "json" {
+		return "archives.json"
+	}
+	return "archives.db"
+}
+
+// importArchives compares contents of an archive with folders
+// only when the archive has no record.
+func importArchives(musicRoot string, records map[string]archiveRecord) (dirs []string) {
+	fs, err := os.ReadDir(musicRoot)
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	for _, f := range fs {
+		switch strings.ToLower(filepath.Ext(f.Name())) {
+		case ".osz", ".zip":
+		default:
+			continue
+		}
+		if f.IsDir() {
+			continue
+		}
+		info, err := f.Info()
+		if err != nil {
+			fmt.Println(err)
+			continue
+		}
+		apath := filepath.Join(musicRoot, f.Name())
+		r, ok := records[apath]
+		if !ok || r.Size != info.Size() || r.ModTime != info.ModTime().UnixNano() || !isDir(r.Dir) {
+			dir, err := importArchive(musicRoot, apath)
+			if err != nil {
+				fmt.Printf("error at importing %s: %s\n", f.Name(), err)
+				continue
+			}
+			r = archiveRecord{info.Size(), info.ModTime().UnixNano(), dir}
+			records[apath] = r
+		}
+		dirs = append(dirs, r.Dir)
+		if DeleteArchive {
+			if err := os.Remove(apath); err != nil {
+				fmt.Println(err)
+			}
+		}
+	}
+	for apath := range records { // Drop records of archives removed.
+		if _, err := os.Stat(apath); err != nil {
+			delete(records, apath)
+		}
+	}
+	return
+}
+
+func isDir(path string) bool {
+	info, err := os.Stat(path)
+	return err == nil && info.IsDir()
+}
+
+func importArchive(musicRoot, apath string) (string, error) {
+	r, err := zip.OpenReader(apath)
+	if err != nil {
+		return "", err
+	}
+	defer r.Close()
+
+	name := strings.TrimSuffix(filepath.Base(apath), filepath.Ext(apath))
+	if n, ok := archiveDirName(r.File); ok {
+		name = n
+	}
+	dir := filepath.Join(musicRoot, sanitizeFilename(name))
+	if d, ok := importedDir(dir, r.File); ok {
+		return d, nil
+	}
+	dir = uniquePath(dir, "")
+	if err := os.Mkdir(dir, os.ModePerm); err != nil {
+		return "", err
+	}
+	for _, f := range r.File {
+		if err := extractFile(dir, f); err != nil {
+			os.RemoveAll(dir) // Partially extracted folder is not wanted.
+			return "", err
+		}
+	}
+	return dir, nil
+}
+
+// archiveDirName reads the first .osu file in the archive.
+func archiveDirName(fs []*zip.File) (string, bool) {
+	for _, f := range fs {
+		if strings.ToLower(filepath.Ext(f.Name)) != ".osu" {
+			continue
+		}
+		rc, err := f.Open()
+		if err != nil {
+			continue
+		}
+		dat, err := io.ReadAll(rc)
+		rc.Close()
+		if err != nil {
+			continue
+		}
+		o, err := osu.Parse(dat)
+		if err != nil {
+			continue
+		}
+		m := o.Metadata
+		if m.BeatmapSetID <= 0 { // Not submitted.
+			return fmt.Sprintf("%s - %s", m.Artist, m.Title), true
+		}
+		return fmt.Sprintf("%d %s - %s", m.BeatmapSetID, m.Artist, m.Title), true
+	}
+	return "", false
+}
+
+// extractFile guards against zip slip: a file escaping from the folder
+// with a name such as "../../evil.exe".
+func extractFile(dir string, f *zip.File) error {
+	path, ok := archiveFilePath(dir, f)
+	if !ok {
+		return fmt.Errorf("illegal file path: %s", f.Name)
+	}
+	if f.FileInfo().IsDir() {
+		return os.MkdirAll(path, os.ModePerm)
+	}
+	if !f.Mode().IsRegular() { // Symbolic links may point to outside.
+		return nil
+	}
+	if err := os.MkdirAll(filepath.Dir(path), os.ModePerm); err != nil {
+		return err
+	}
+	rc, err := f.Open()
+	if err != nil {
+		return err
+	}
+	defer rc.Close()
+	// Extracted files have current time as modified time,
+	// so that new charts are loaded at LoadNewChartInfos.
+	w, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0644)
+	if err != nil {
+		return err
+	}
+	defer w.Close()
+	_, err = io.Copy(w, rc)
+	return err
+}
+
+// archiveFilePath returns the path of the file extracted to the folder.
+// It reports false when the file escapes from the folder.
+func archiveFilePath(dir string, f *zip.File) (string, bool) {
+	path := filepath.Join(dir, filepath.FromSlash(strings.ReplaceAll(f.Name, `\`, "/")))
+	return path, strings.HasPrefix(path, filepath.Clean(dir)+string(os.PathSeparator))
+}
+
+// importedDir returns the folder which the archive has been extracted to.
+// Folders with a number added by uniquePath are also looked for.
+func importedDir(dir string, fs []*zip.File) (string, bool) {
+	p := dir
+	for i := 2; ; i++ {
+		info, err := os.Stat(p)
+		if err != nil {
+			return "", false
+		}
+		if info.IsDir() && hasArchiveFiles(p, fs) {
+			return p, true
+		}
+		p = fmt.Sprintf("%s (%d)", dir, i)
+	}
+}
+
+// hasArchiveFiles reports whether the folder has all files of the archive
+// with the same contents. Contents are compared by size and CRC-32.
+func hasArchiveFiles(dir string, fs []*zip.File) bool {
+	for _, f := range fs {
+		if !f.Mode().IsRegular() {
+			continue
+		}
+		path, ok := archiveFilePath(dir, f)
+		if !ok {
+			return false
+		}
+		dat, err := os.ReadFile(path)
+		if err != nil || uint64(len(dat)) != f.UncompressedSize64 || crc32.ChecksumIEEE(dat) != f.CRC32 {
+			return false
+		}
+	}
+	return true
+}
+
+// uniquePath returns the path with the extension. A number is added
+// before the extension when the path already exists.
+func uniquePath(path, ext string) string {
+	p := path + ext
+	for i := 2; ; i++ {
+		if _, err := os.Stat(p); os.IsNotExist(err) {
+			return p
+		}
+		p = fmt.Sprintf("%s (%d)%s", path, i, ext)
+	}
+}
+
+func sanitizeFilename(name string) string {
+	for _, letter := range []string{"<", ">", ":", "\"", "/", "\\", "|", "?", "*"} {
+		name = strings.ReplaceAll(name, letter, "-")
+	}
+	return strings.TrimRight(name, ". ") // Not allowed at the end on Windows.
+}
+
+// Rescan imports archives, then loads charts newly added to music root.
+func Rescan() {
+	ImportArchives(MusicRoot)
+	TidyChartInfosSet(modeProps)
+	for i, prop := range modeProps {
+		now := time.Now()
+		infos := prop.ChartInfos
+		sort.Slice(infos, func(a, b int) bool { return infos[a].Path < infos[b].Path }) // View may have sorted it.
+		for _, info := range prop.LoadNewChartInfos(MusicRoot) {
+			infos = PutChartInfo(infos, info)
+		}
+		modeProps[i].ChartInfos = infos
+		modeProps[i].LastUpdateTime = now
+	}
+	SaveChartInfosSet(modeProps)
+}
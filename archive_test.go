@@ -0,0 +1,102 @@
+package gosu
+
+import (
+	"archive/zip"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func writeArchive(t *testing.T, path string, files map[string]string) {
+	w, err := os.Create(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer w.Close()
+	zw := zip.NewWriter(w)
+	for name, content := range files {
+		fw, err := zw.Create(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := fw.Write([]byte(content)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := zw.Close(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// An archive is skipped only when a folder has the same contents,
+// even if the folder has the same name.
+func TestImportArchive(t *testing.T) {
+	root := t.TempDir()
+	apath := filepath.Join(root, "song.zip")
+	writeArchive(t, apath, map[string]string{"a.txt": "a", "sub/b.txt": "b"})
+	dir, err := importArchive(root, apath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := filepath.Join(root, "song"); dir != want {
+		t.Fatalf("dir = %s; want %s", dir, want)
+	}
+	if dir2, err := importArchive(root, apath); err != nil || dir2 != dir {
+		t.Errorf("importing again: %s, %v; want %s", dir2, err, dir)
+	}
+
+	writeArchive(t, apath, map[string]string{"a.txt": "another"})
+	dir3, err := importArchive(root, apath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := filepath.Join(root, "song (2)"); dir3 != want {
+		t.Errorf("different archive: dir = %s; want %s", dir3, want)
+	}
+	if dir4, err := importArchive(root, apath); err != nil || dir4 != dir3 {
+		t.Errorf("importing different archive again: %s, %v; want %s", dir4, err, dir3)
+	}
+}
+
+// Contents are compared only when an archive has no record.
+func TestImportArchives(t *testing.T) {
+	root := t.TempDir()
+	apath := filepath.Join(root, "song.zip")
+	writeArchive(t, apath, map[string]string{"a.txt": "a"})
+	records := make(map[string]archiveRecord)
+	dir := filepath.Join(root, "song")
+	if dirs := importArchives(root, records); len(dirs) != 1 || dirs[0] != dir {
+		t.Fatalf("dirs = %v; want [%s]", dirs, dir)
+	}
+	if r, ok := records[apath]; !ok || r.Dir != dir {
+		t.Fatalf("record = %+v, %v", r, ok)
+	}
+
+	// The folder no longer has the same contents, yet the record is kept.
+	if err := os.WriteFile(filepath.Join(dir, "a.txt"), []byte("edited"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if dirs := importArchives(root, records); len(dirs) != 1 || dirs[0] != dir {
+		t.Errorf("with record: dirs = %v; want [%s]", dirs, dir)
+	}
+	delete(records, apath)
+	dir2 := filepath.Join(root, "song (2)")
+	if dirs := importArchives(root, records); len(dirs) != 1 || dirs[0] != dir2 {
+		t.Errorf("without record: dirs = %v; want [%s]", dirs, dir2)
+	}
+
+	// An archive changed in size is imported again.
+	writeArchive(t, apath, map[string]string{"a.txt": "another"})
+	dir3 := filepath.Join(root, "song (3)")
+	if dirs := importArchives(root, records); len(dirs) != 1 || dirs[0] != dir3 {
+		t.Errorf("changed archive: dirs = %v; want [%s]", dirs, dir3)
+	}
+
+	if err := os.Remove(apath); err != nil {
+		t.Fatal(err)
+	}
+	importArchives(root, records)
+	if len(records) != 0 {
+		t.Errorf("records of removed archive: %v", records)
+	}
+}
@@ -18,27 +18,26 @@ var Context *audio.Context = audio.NewContext(SampleRate)
 
 // decode returns streamer, closer, and error.
 func decode(apath string) (io.ReadSeeker, func() error, error) { // apath stands for audio path.
-	var s io.ReadSeeker
 	f, err := os.Open(apath)
 	if err != nil {
 		return nil, nil, err
 	}
-	switch strings.ToLower(filepath.Ext(apath)) {
+	s, err := decodeReader(f, filepath.Ext(apath))
+	if err != nil {
+		return nil, f.Close, err
+	}
+	return s, f.Close, nil
+}
+
+// decodeReader decodes audio data by its extension.
+func decodeReader(r io.ReadSeeker, ext string) (s io.ReadSeeker, err error) {
+	switch strings.ToLower(ext) {
 	case ".mp3":
-		s, err = mp3.DecodeWithSampleRate(SampleRate, io.ReadSeekCloser(f))
-		if err != nil {
-			return nil, f.Close, err
-		}
+		s, err = mp3.DecodeWithSampleRate(SampleRate, r)
 	case ".wav":
-		s, err = wav.DecodeWithSampleRate(SampleRate, io.ReadSeekCloser(f))
-		if err != nil {
-			return nil, f.Close, err
-		}
+		s, err = wav.DecodeWithSampleRate(SampleRate, r)
 	case ".ogg":
-		s, err = vorbis.DecodeWithSampleRate(SampleRate, io.ReadSeekCloser(f))
-		if err != nil {
-			return nil, f.Close, err
-		}
+		s, err = vorbis.DecodeWithSampleRate(SampleRate, r)
 	}
-	return s, f.Close, nil
+	return
 }
@@ -18,6 +18,19 @@ func NewPlayer(path string) (*audio.Player, func() error, error) {
 	// p.SetBufferSize(bufferSize)
 	return p, closer, err
 }
+
+// NewPlayerWithRate plays music at the rate. Pitch is kept unless nightcore is set.
+func NewPlayerWithRate(path string, rate float64, nightcore bool) (*audio.Player, func() error, error) {
+	s, closer, err := decode(path)
+	if err != nil {
+		return nil, nil, err
+	}
+	p, err := Context.NewPlayer(NewRateStreamer(s, rate, nightcore))
+	if err != nil {
+		return nil, closer, err
+	}
+	return p, closer, err
+}
 func PlayEffect(src []byte, vol float64) {
 	p := Context.NewPlayerFromBytes(src)
 	p.SetVolume(vol)
@@ -1,9 +1,13 @@
 package audios
 
 import (
+	"bytes"
 	"io"
 	"path/filepath"
 	"strings"
+	"time"
+
+	"github.com/hajimehoshi/ebiten/v2/audio"
 )
 
 // type SoundMap struct {
@@ -12,8 +16,12 @@ import (
 // }
 
 type SoundMap struct {
-	bytes map[string][]byte
-	vol   *float64
+	bytes   map[string][]byte
+	players *[]*audio.Player // Players which may be playing, for stopping.
+	vol     *float64
+	rate    float64 // Sounds are stretched at registering when rate is not 1.
+	// Nightcore changes pitch along with the rate.
+	nightcore bool
 }
 
 // NewBytes is for short sounds: long audio file will make the game stutter.
@@ -31,10 +39,27 @@ func NewBytes(path string) ([]byte, error) {
 }
 func NewSoundMap(vol *float64) SoundMap {
 	return SoundMap{
-		bytes: make(map[string][]byte),
-		vol:   vol,
+		bytes:   make(map[string][]byte),
+		players: new([]*audio.Player),
+		vol:     vol,
+		rate:    1,
 	}
 }
+
+// WithRate returns a sound map which registers sounds played at the rate.
+// Pitch is kept unless nightcore is set. Registered sounds are shared.
+func (s SoundMap) WithRate(rate float64, nightcore bool) SoundMap {
+	s.rate = rate
+	s.nightcore = nightcore
+	return s
+}
+
+// WithVolume returns a sound map which plays at the volume.
+// Registered sounds are shared.
+func (s SoundMap) WithVolume(vol *float64) SoundMap {
+	s.vol = vol
+	return s
+}
 func (s SoundMap) Register(path string) error {
 	b, err := NewBytes(path)
 	if err != nil {
@@ -44,7 +69,21 @@ func (s SoundMap) Register(path string) error {
 	if pos := strings.LastIndexByte(name, '.'); pos != -1 {
 		name = name[:pos]
 	}
-	s.bytes[name] = b
+	s.bytes[name] = ChangeRate(b, s.rate, s.nightcore)
+	return nil
+}
+
+// RegisterBytes is for audio data not from a file, e.g., samples in an archive.
+func (s SoundMap) RegisterBytes(name string, data []byte, ext string) error {
+	r, err := decodeReader(bytes.NewReader(data), ext)
+	if err != nil {
+		return err
+	}
+	b, err := io.ReadAll(r)
+	if err != nil {
+		return err
+	}
+	s.bytes[name] = ChangeRate(b, s.rate, s.nightcore)
 	return nil
 }
 
@@ -60,15 +99,59 @@ func (s SoundMap) Register(path string) error {
 //		// s.Closers = append(s.Closers, closer)
 //		return nil
 //	}
-func (s SoundMap) Play(name string) {
-	p := Context.NewPlayerFromBytes(s.bytes[name])
-	p.SetVolume(*s.vol)
-	p.Play()
+
+// Has reports whether a sound is registered with the name.
+func (s SoundMap) Has(name string) bool {
+	_, ok := s.bytes[name]
+	return ok
 }
+func (s SoundMap) Play(name string) { s.PlayFrom(name, 1, 0) }
 func (s SoundMap) PlayWithVolume(name string, vol2 float64) {
-	p := Context.NewPlayerFromBytes(s.bytes[name])
+	s.PlayFrom(name, vol2, 0)
+}
+
+// PlayFrom plays the sound from the offset. It returns false
+// when the sound is not registered or has ended at the offset.
+func (s SoundMap) PlayFrom(name string, vol2 float64, offset time.Duration) bool {
+	b, ok := s.bytes[name]
+	if !ok {
+		return false
+	}
+	if offset < 0 {
+		offset = 0
+	}
+	if int64(offset.Seconds()*float64(SampleRate))*bytesPerFrame >= int64(len(b)) {
+		return false
+	}
+	p := Context.NewPlayerFromBytes(b)
 	p.SetVolume(*s.vol * vol2)
+	if offset > 0 {
+		if err := p.Seek(offset); err != nil {
+			return false
+		}
+	}
 	p.Play()
+	if s.players != nil {
+		ps := (*s.players)[:0]
+		for _, p2 := range *s.players {
+			if p2.IsPlaying() {
+				ps = append(ps, p2)
+			}
+		}
+		*s.players = append(ps, p)
+	}
+	return true
+}
+
+// Stop stops all sounds which are playing.
+func (s SoundMap) Stop() {
+	if s.players == nil {
+		return
+	}
+	for _, p := range *s.players {
+		p.Pause()
+	}
+	*s.players = (*s.players)[:0]
 }
 
 // func (s *SoundPad) Close() {
@@ -0,0 +1,340 @@
+package audios
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"io"
+	"math"
+)
+
+// Decoded streams are 16-bit little-endian stereo PCM.
+const (
+	channelNum     = 2
+	bytesPerSample = 2
+	bytesPerFrame  = channelNum * bytesPerSample
+)
+
+// Parameters of WSOLA in frames. Segment lasts around 23ms at 44.1kHz.
+const (
+	segmentSize = 1024
+	hopSize     = segmentSize / 2 // Synthesis hop: segments overlap by half.
+	tolerance   = 256             // Max shift of segment for finding the most similar one.
+)
+
+// NewRateStreamer returns a stream which plays src at the rate.
+// Pitch is kept with time stretching unless nightcore is set;
+// nightcore changes pitch along with the rate as a tape does.
+// Positions of the returned stream are of output, hence Seek takes
+// a position at the rate: seeking to 1s at rate 2 goes to 2s of src.
+func NewRateStreamer(src io.ReadSeeker, rate float64, nightcore bool) io.ReadSeeker {
+	if rate == 1 || rate <= 0 {
+		return src
+	}
+	if nightcore {
+		return NewResampler(src, rate)
+	}
+	return NewTimeStretcher(src, rate)
+}
+
+// frameReader reads frames of src into a float buffer.
+// Buffer starts from frame index start.
+type frameReader struct {
+	src   io.ReadSeeker
+	buf   []float64 // Interleaved samples.
+	start int64
+	eof   bool
+	raw   []byte
+}
+
+func (r *frameReader) end() int64 { return r.start + int64(len(r.buf)/channelNum) }
+
+// fill reads src until the buffer covers frames up to the index.
+func (r *frameReader) fill(until int64) error {
+	for !r.eof && r.end() < until {
+		n := int(until-r.end()) * bytesPerFrame
+		if n < 4096 {
+			n = 4096
+		}
+		if cap(r.raw) < n {
+			r.raw = make([]byte, n)
+		}
+		raw := r.raw[:n]
+		m, err := io.ReadFull(r.src, raw)
+		m -= m % bytesPerFrame
+		for i := 0; i < m; i += bytesPerSample {
+			v := int16(binary.LittleEndian.Uint16(raw[i:]))
+			r.buf = append(r.buf, float64(v)/(1<<15))
+		}
+		if err == io.EOF || err == io.ErrUnexpectedEOF {
+			r.eof = true
+		} else if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// at returns a sample at the frame and the channel. Out of range is silent.
+func (r *frameReader) at(frame int64, ch int) float64 {
+	i := (frame-r.start)*channelNum + int64(ch)
+	if frame < r.start || i >= int64(len(r.buf)) {
+		return 0
+	}
+	return r.buf[i]
+}
+
+// discard drops frames before the index.
+func (r *frameReader) discard(before int64) {
+	if before <= r.start {
+		return
+	}
+	n := (before - r.start) * channelNum
+	if n > int64(len(r.buf)) {
+		n = int64(len(r.buf))
+	}
+	r.buf = append(r.buf[:0], r.buf[n:]...)
+	r.start += n / channelNum
+}
+
+func (r *frameReader) seek(frame int64) error {
+	if _, err := r.src.Seek(frame*bytesPerFrame, io.SeekStart); err != nil {
+		return err
+	}
+	r.buf = r.buf[:0]
+	r.start = frame
+	r.eof = false
+	return nil
+}
+
+func appendSample(out []byte, v float64) []byte {
+	v = math.Max(-1, math.Min(1, v)) * (1<<15 - 1)
+	u := uint16(int16(math.Round(v)))
+	return append(out, byte(u), byte(u>>8))
+}
+
+// TimeStretcher changes tempo without changing pitch by WSOLA:
+// waveform similarity overlap-add. Each segment of output is taken
+// from around the corresponding position of input, shifted to
+// continue the previous segment most similarly.
+type TimeStretcher struct {
+	r      frameReader
+	rate   float64
+	window []float64
+	acc    []float64 // Overlap-add accumulator of a segment length.
+	next   int64     // Natural continuation of the last segment at input. -1 at start.
+	k      int64     // Index of the next segment.
+	out    []byte    // Pending output.
+	skip   int       // Bytes to skip after seeking in the middle of a segment.
+	pos    int64     // Position of output in bytes.
+	done   bool
+}
+
+func NewTimeStretcher(src io.ReadSeeker, rate float64) *TimeStretcher {
+	w := make([]float64, segmentSize)
+	for i := range w { // Periodic Hann window sums to 1 at half overlap.
+		w[i] = 0.5 - 0.5*math.Cos(2*math.Pi*float64(i)/segmentSize)
+	}
+	return &TimeStretcher{
+		r:      frameReader{src: src},
+		rate:   rate,
+		window: w,
+		acc:    make([]float64, segmentSize*channelNum),
+		next:   -1,
+	}
+}
+
+func (s *TimeStretcher) Read(p []byte) (int, error) {
+	for len(s.out) < len(p) && !s.done {
+		if err := s.segment(); err != nil {
+			return 0, err
+		}
+	}
+	n := copy(p, s.out)
+	s.out = s.out[n:]
+	s.pos += int64(n)
+	if n == 0 && s.done {
+		return 0, io.EOF
+	}
+	return n, nil
+}
+
+// segment adds a segment to the accumulator and outputs a hop.
+func (s *TimeStretcher) segment() error {
+	ideal := int64(float64(s.k*hopSize) * s.rate)
+	if err := s.r.fill(ideal + tolerance + segmentSize); err != nil {
+		return err
+	}
+	if s.r.eof && ideal >= s.r.end() {
+		s.done = true
+		s.emit() // Tail of the last segment.
+		return nil
+	}
+	start := ideal
+	if s.next >= 0 {
+		start = s.similar(ideal)
+	}
+	for i := 0; i < segmentSize; i++ {
+		w := s.window[i]
+		if s.k == 0 && i < hopSize { // No fade-in at the start: attacks are kept.
+			w = 1
+		}
+		for ch := 0; ch < channelNum; ch++ {
+			s.acc[i*channelNum+ch] += w * s.r.at(start+int64(i), ch)
+		}
+	}
+	s.emit()
+	s.next = start + hopSize
+	s.k++
+
+	min := s.next
+	if ideal2 := int64(float64(s.k*hopSize)*s.rate) - tolerance; ideal2 < min {
+		min = ideal2
+	}
+	s.r.discard(min)
+	return nil
+}
+
+// emit outputs a hop of the accumulator and shifts it.
+func (s *TimeStretcher) emit() {
+	for _, v := range s.acc[:hopSize*channelNum] {
+		s.out = appendSample(s.out, v)
+	}
+	if s.skip > 0 { // Only right after seeking, when out has been empty.
+		n := s.skip
+		if n > len(s.out) {
+			n = len(s.out)
+		}
+		s.out = s.out[n:]
+		s.skip -= n
+	}
+	copy(s.acc, s.acc[hopSize*channelNum:])
+	for i := (segmentSize - hopSize) * channelNum; i < len(s.acc); i++ {
+		s.acc[i] = 0
+	}
+}
+
+// similar returns a start of segment around the ideal position whose
+// overlapping part is the most similar to the natural continuation.
+// Samples are compared in mono and every other frame for speed.
+func (s *TimeStretcher) similar(ideal int64) int64 {
+	const overlap = segmentSize - hopSize
+	best, bestScore := ideal, math.Inf(-1)
+	for d := int64(-tolerance); d <= tolerance; d++ {
+		start := ideal + d
+		if start < s.r.start {
+			continue
+		}
+		var score float64
+		for i := int64(0); i < overlap; i += 2 {
+			a := s.r.at(start+i, 0) + s.r.at(start+i, 1)
+			b := s.r.at(s.next+i, 0) + s.r.at(s.next+i, 1)
+			score += a * b
+		}
+		if score > bestScore {
+			best, bestScore = start, score
+		}
+	}
+	return best
+}
+
+func (s *TimeStretcher) Seek(offset int64, whence int) (int64, error) {
+	switch whence {
+	case io.SeekStart:
+	case io.SeekCurrent:
+		offset += s.pos
+	default:
+		return 0, errors.New("audios: unsupported whence")
+	}
+	if offset < 0 {
+		return 0, errors.New("audios: negative position")
+	}
+	offset -= offset % bytesPerFrame
+	frame := offset / bytesPerFrame
+	s.k = frame / hopSize
+	s.skip = int(frame%hopSize) * bytesPerFrame
+	if s.k > 0 { // First hop fades in; a hop ahead fills the accumulator.
+		s.k--
+		s.skip += hopSize * bytesPerFrame
+	}
+	ideal := int64(float64(s.k*hopSize) * s.rate)
+	from := ideal - tolerance
+	if from < 0 {
+		from = 0
+	}
+	if err := s.r.seek(from); err != nil {
+		return 0, err
+	}
+	for i := range s.acc {
+		s.acc[i] = 0
+	}
+	s.next = -1
+	s.out = s.out[:0]
+	s.pos = offset
+	s.done = false
+	return offset, nil
+}
+
+// Resampler changes tempo and pitch together by linear interpolation.
+type Resampler struct {
+	r    frameReader
+	rate float64
+	n    int64 // Index of the next output frame.
+}
+
+func NewResampler(src io.ReadSeeker, rate float64) *Resampler {
+	return &Resampler{r: frameReader{src: src}, rate: rate}
+}
+
+func (s *Resampler) Read(p []byte) (int, error) {
+	out := p[:0]
+	for len(out)+bytesPerFrame <= len(p) {
+		x := float64(s.n) * s.rate
+		i := int64(x)
+		if err := s.r.fill(i + 2); err != nil {
+			return 0, err
+		}
+		if s.r.eof && i >= s.r.end() {
+			break
+		}
+		t := x - float64(i)
+		for ch := 0; ch < channelNum; ch++ {
+			v := (1-t)*s.r.at(i, ch) + t*s.r.at(i+1, ch)
+			out = appendSample(out, v)
+		}
+		s.n++
+		s.r.discard(i)
+	}
+	if len(out) == 0 && len(p) >= bytesPerFrame {
+		return 0, io.EOF
+	}
+	return len(out), nil
+}
+
+func (s *Resampler) Seek(offset int64, whence int) (int64, error) {
+	switch whence {
+	case io.SeekStart:
+	case io.SeekCurrent:
+		offset += s.n * bytesPerFrame
+	default:
+		return 0, errors.New("audios: unsupported whence")
+	}
+	if offset < 0 {
+		return 0, errors.New("audios: negative position")
+	}
+	s.n = offset / bytesPerFrame
+	if err := s.r.seek(int64(float64(s.n) * s.rate)); err != nil {
+		return 0, err
+	}
+	return s.n * bytesPerFrame, nil
+}
+
+// ChangeRate returns PCM bytes played at the rate.
+// Pitch is kept unless nightcore is set.
+func ChangeRate(b []byte, rate float64, nightcore bool) []byte {
+	if rate == 1 || rate <= 0 {
+		return b
+	}
+	out, _ := io.ReadAll(NewRateStreamer(bytes.NewReader(b), rate, nightcore))
+	return out
+}
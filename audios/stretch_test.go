@@ -0,0 +1,133 @@
+package audios
+
+import (
+	"bytes"
+	"encoding/binary"
+	"io"
+	"math"
+	"testing"
+)
+
+const testSampleRate = 44100
+
+// sine returns stereo PCM of a sine wave lasting the frames.
+func sine(freq float64, frames int) []byte {
+	b := make([]byte, 0, frames*bytesPerFrame)
+	for i := 0; i < frames; i++ {
+		v := 0.5 * math.Sin(2*math.Pi*freq*float64(i)/testSampleRate)
+		for ch := 0; ch < channelNum; ch++ {
+			b = appendSample(b, v)
+		}
+	}
+	return b
+}
+
+// crossings counts rising zero crossings of the left channel.
+func crossings(b []byte) int {
+	var n int
+	var prev int16
+	for i := 0; i+bytesPerFrame <= len(b); i += bytesPerFrame {
+		v := int16(binary.LittleEndian.Uint16(b[i:]))
+		if prev < 0 && v >= 0 {
+			n++
+		}
+		prev = v
+	}
+	return n
+}
+
+func TestChangeRate(t *testing.T) {
+	const frames = testSampleRate // 1 second.
+	src := sine(440, frames)
+	for _, tc := range []struct {
+		rate      float64
+		nightcore bool
+		freq      float64 // Expected frequency of output.
+	}{
+		{1, false, 440},
+		{1.5, false, 440},
+		{0.75, false, 440},
+		{1.5, true, 660},
+		{0.75, true, 330},
+	} {
+		out := ChangeRate(src, tc.rate, tc.nightcore)
+		wantFrames := float64(frames) / tc.rate
+		gotFrames := float64(len(out) / bytesPerFrame)
+		if math.Abs(gotFrames-wantFrames) > segmentSize {
+			t.Errorf("rate %v nightcore %v: %.0f frames, want %.0f", tc.rate, tc.nightcore, gotFrames, wantFrames)
+		}
+		freq := float64(crossings(out)) / (gotFrames / testSampleRate)
+		if math.Abs(freq-tc.freq) > tc.freq*0.03 {
+			t.Errorf("rate %v nightcore %v: frequency %.1f, want %.1f", tc.rate, tc.nightcore, freq, tc.freq)
+		}
+	}
+}
+
+// An attack at the start should not fade in.
+func TestTimeStretcherStart(t *testing.T) {
+	src := sine(440, testSampleRate/10)
+	out := ChangeRate(src, 1.5, false)
+	for i := 0; i < 40*bytesPerFrame; i += bytesPerSample {
+		got := int16(binary.LittleEndian.Uint16(out[i:]))
+		want := int16(binary.LittleEndian.Uint16(src[i:]))
+		if d := int(got) - int(want); d < -2 || d > 2 {
+			t.Fatalf("byte %d: %d, want %d", i, got, want)
+		}
+	}
+}
+
+// Seeking should go to the same output as reading through.
+func TestResamplerSeek(t *testing.T) {
+	src := sine(440, testSampleRate/2)
+	s := NewResampler(bytes.NewReader(src), 1.25)
+	all, err := io.ReadAll(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	const offset = 5000 * bytesPerFrame
+	if pos, err := s.Seek(offset, io.SeekStart); err != nil || pos != offset {
+		t.Fatalf("seek: %d, %v", pos, err)
+	}
+	got, err := io.ReadAll(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(got, all[offset:]) {
+		t.Errorf("output after seek differs from reading through")
+	}
+}
+
+// Segments after seeking may differ from reading through since WSOLA
+// depends on the previous segment, but the length left should match.
+func TestTimeStretcherSeek(t *testing.T) {
+	src := sine(440, testSampleRate/2)
+	s := NewTimeStretcher(bytes.NewReader(src), 1.25)
+	all, err := io.ReadAll(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	const offset = 5001*bytesPerFrame + 1 // Aligned down to a frame.
+	if pos, err := s.Seek(offset, io.SeekStart); err != nil || pos != offset-1 {
+		t.Fatalf("seek: %d, %v", pos, err)
+	}
+	if pos, _ := s.Seek(0, io.SeekCurrent); pos != offset-1 {
+		t.Errorf("current position: %d, want %d", pos, offset-1)
+	}
+	got, err := io.ReadAll(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if d := len(got) - len(all[offset-1:]); d < -hopSize*bytesPerFrame || d > hopSize*bytesPerFrame {
+		t.Errorf("%d bytes after seek, want %d", len(got), len(all[offset-1:]))
+	}
+}
+
+func TestSeekNegative(t *testing.T) {
+	src := sine(440, 1000)
+	for _, nightcore := range []bool{false, true} {
+		s := NewRateStreamer(bytes.NewReader(src), 2, nightcore)
+		if _, err := s.Seek(-bytesPerFrame, io.SeekStart); err == nil {
+			t.Errorf("nightcore %v: no error for negative position", nightcore)
+		}
+	}
+}
@@ -0,0 +1,97 @@
+package gosu
+
+import (
+	"math"
+	"math/rand"
+)
+
+// KeyIntervals are spans of pressing for each key.
+// Key is pressed from the start to the end of a span, both inclusive.
+type KeyIntervals [][][2]int64
+
+// Add appends a span to the key. Spans of a key keep in order:
+// the last span is released at least a tick before the new one starts,
+// so that the new one is a hit.
+func (ivs KeyIntervals) Add(k int, start, end int64) {
+	spans := ivs[k]
+	if last := len(spans) - 1; last >= 0 {
+		if start < spans[last][0]+2 {
+			start = spans[last][0] + 2
+		}
+		if spans[last][1] >= start-1 {
+			spans[last][1] = start - 2
+		}
+	}
+	if end < start {
+		end = start
+	}
+	ivs[k] = append(spans, [2]int64{start, end})
+}
+
+// Listener presses keys along the intervals.
+func (ivs KeyIntervals) Listener(timer *Timer) func() []bool {
+	cursors := make([]int, len(ivs))
+	return func() []bool {
+		pressed := make([]bool, len(ivs))
+		for k, spans := range ivs {
+			// Cursors go back when the timer has moved backward.
+			for cursors[k] > 0 && spans[cursors[k]-1][1] >= timer.Now {
+				cursors[k]--
+			}
+			for cursors[k] < len(spans) && spans[cursors[k]][1] < timer.Now {
+				cursors[k]++
+			}
+			if i := cursors[k]; i < len(spans) && spans[i][0] <= timer.Now {
+				pressed[k] = true
+			}
+		}
+		return pressed
+	}
+}
+
+// Humanizer makes Auto play like a human. Nil Humanizer plays perfectly.
+type Humanizer struct {
+	Deviation   float64 // Standard deviation of timing error in milliseconds.
+	MissRate    float64 // Probability of missing a note.
+	ReleaseRate float64 // Probability of releasing a long note early.
+	r           *rand.Rand
+}
+
+// NewHumanizer returns a Humanizer with the settings.
+// It returns nil when the settings are all zero.
+func NewHumanizer(seed int64) *Humanizer {
+	if AutoDeviation == 0 && AutoMissRate == 0 && AutoReleaseRate == 0 {
+		return nil
+	}
+	return &Humanizer{
+		Deviation:   AutoDeviation,
+		MissRate:    AutoMissRate,
+		ReleaseRate: AutoReleaseRate,
+		r:           rand.New(rand.NewSource(seed)),
+	}
+}
+
+// Error returns a timing error of a hit, which follows Gaussian distribution.
+// A negative value infers an early hit.
+func (h *Humanizer) Error() int64 {
+	if h == nil || h.Deviation == 0 {
+		return 0
+	}
+	return int64(math.Round(h.r.NormFloat64() * h.Deviation))
+}
+
+func (h *Humanizer) Miss() bool {
+	if h == nil || h.MissRate == 0 {
+		return false
+	}
+	return h.r.Float64() < h.MissRate
+}
+
+// Release returns when a long note lasting the duration is released,
+// relative to its start. Early release is at a random point of the body.
+func (h *Humanizer) Release(duration int64) int64 {
+	if h == nil || h.ReleaseRate == 0 || h.r.Float64() >= h.ReleaseRate {
+		return duration
+	}
+	return int64(h.r.Float64() * float64(duration))
+}
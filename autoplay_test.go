@@ -0,0 +1,116 @@
+package gosu
+
+import (
+	"math"
+	"reflect"
+	"testing"
+)
+
+func TestKeyIntervalsAdd(t *testing.T) {
+	for _, tc := range []struct {
+		name  string
+		spans [][2]int64 // Start and end to add.
+		want  [][2]int64
+	}{
+		{"apart", [][2]int64{{0, 10}, {100, 150}}, [][2]int64{{0, 10}, {100, 150}}},
+		{"overlapped", [][2]int64{{0, 100}, {50, 60}}, [][2]int64{{0, 48}, {50, 60}}},
+		{"adjacent", [][2]int64{{0, 10}, {11, 20}}, [][2]int64{{0, 9}, {11, 20}}},
+		{"same start", [][2]int64{{0, 10}, {0, 10}}, [][2]int64{{0, 0}, {2, 10}}},
+		{"earlier start", [][2]int64{{100, 110}, {50, 60}}, [][2]int64{{100, 100}, {102, 102}}},
+		{"end before start", [][2]int64{{0, -5}}, [][2]int64{{0, 0}}},
+	} {
+		ivs := make(KeyIntervals, 2)
+		for _, span := range tc.spans {
+			ivs.Add(1, span[0], span[1])
+		}
+		if !reflect.DeepEqual(ivs[1], tc.want) {
+			t.Errorf("%s: got %v, want %v", tc.name, ivs[1], tc.want)
+		}
+		if len(ivs[0]) != 0 {
+			t.Errorf("%s: other key has spans: %v", tc.name, ivs[0])
+		}
+	}
+}
+
+// Each added span is pressed, and released before the next one.
+func TestKeyIntervalsListener(t *testing.T) {
+	ivs := make(KeyIntervals, 1)
+	ivs.Add(0, 0, 0)
+	ivs.Add(0, 1, 5)
+	timer := &Timer{}
+	listen := ivs.Listener(timer)
+	var got []bool
+	for timer.Now = -1; timer.Now <= 6; timer.Now++ {
+		got = append(got, listen()[0])
+	}
+	want := []bool{false, true, false, true, true, true, true, false}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+	timer.Now = 3 // Going backward.
+	if !listen()[0] {
+		t.Errorf("not pressed after going backward")
+	}
+}
+
+func setAutoSettings(deviation, missRate, releaseRate float64) func() {
+	d, m, r := AutoDeviation, AutoMissRate, AutoReleaseRate
+	AutoDeviation, AutoMissRate, AutoReleaseRate = deviation, missRate, releaseRate
+	return func() { AutoDeviation, AutoMissRate, AutoReleaseRate = d, m, r }
+}
+
+func TestHumanizerPerfect(t *testing.T) {
+	defer setAutoSettings(0, 0, 0)()
+	h := NewHumanizer(1)
+	if h != nil {
+		t.Fatalf("humanizer with zero settings: %+v", h)
+	}
+	if h.Error() != 0 || h.Miss() || h.Release(100) != 100 {
+		t.Errorf("nil humanizer is not perfect")
+	}
+}
+
+func TestHumanizer(t *testing.T) {
+	defer setAutoSettings(20, 0.1, 0.2)()
+	const n = 20000
+	h := NewHumanizer(1)
+	var sum, sum2 float64
+	var misses, releases int
+	for i := 0; i < n; i++ {
+		e := float64(h.Error())
+		sum += e
+		sum2 += e * e
+		if h.Miss() {
+			misses++
+		}
+		r := h.Release(1000)
+		if r < 0 || r > 1000 {
+			t.Fatalf("release out of duration: %d", r)
+		}
+		if r < 1000 {
+			releases++
+		}
+	}
+	mean := sum / n
+	sd := math.Sqrt(sum2/n - mean*mean)
+	if math.Abs(mean) > 1 || math.Abs(sd-20) > 1 {
+		t.Errorf("error: mean %.2f, deviation %.2f; want 0, 20", mean, sd)
+	}
+	if rate := float64(misses) / n; math.Abs(rate-0.1) > 0.01 {
+		t.Errorf("miss rate: %.3f, want 0.1", rate)
+	}
+	if rate := float64(releases) / n; math.Abs(rate-0.2) > 0.01 {
+		t.Errorf("release rate: %.3f, want 0.2", rate)
+	}
+}
+
+// The same seed makes the same play, so that a play can be replayed.
+func TestHumanizerSeed(t *testing.T) {
+	defer setAutoSettings(20, 0.1, 0.2)()
+	h1, h2 := NewHumanizer(42), NewHumanizer(42)
+	for i := 0; i < 100; i++ {
+		if h1.Error() != h2.Error() || h1.Miss() != h2.Miss() || h1.Release(500) != h2.Release(500) {
+			t.Fatalf("different at %d with the same seed", i)
+		}
+	}
+}
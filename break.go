@@ -0,0 +1,168 @@
+package gosu
+
+import (
+	"image/color"
+	"time"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/inpututil"
+	"github.com/hndada/gosu/draws"
+)
+
+// Break is an interval with no notes.
+type Break struct {
+	StartTime int64
+	EndTime   int64
+}
+
+var (
+	// Gaps between notes longer than the duration are regarded as breaks
+	// when a chart has no break data.
+	MinBreakDuration int64 = 5000
+	// Skipping seeks to the time earlier than the next note by the margin.
+	SkipMargin int64 = 1500
+	SkipKey          = ebiten.KeyEnter
+
+	BreakBarWidth  float64 = 400
+	BreakBarHeight float64 = 8
+	LetterboxRatio float64 = 0.1 // Height of letterbox to screen.
+)
+
+// NewBreaks finds breaks from intervals of notes, which are sorted by start time.
+// An interval is a pair of time and end time of a note.
+func NewBreaks(intervals [][2]int64) (bs []Break) {
+	if len(intervals) == 0 {
+		return
+	}
+	end := intervals[0][1]
+	for _, iv := range intervals[1:] {
+		if iv[0]-end >= MinBreakDuration {
+			bs = append(bs, Break{end, iv[0]})
+		}
+		if end < iv[1] {
+			end = iv[1]
+		}
+	}
+	return
+}
+
+// PlayBreaks returns breaks with intro, which lasts until the first note.
+// Intro is included only when it is long enough.
+func PlayBreaks(breaks []Break, firstTime int64) []Break {
+	bs := make([]Break, 0, len(breaks)+1)
+	if firstTime+Wait >= MinBreakDuration {
+		bs = append(bs, Break{-Wait, firstTime})
+	}
+	for _, b := range breaks {
+		if b.StartTime >= firstTime {
+			bs = append(bs, b)
+		}
+	}
+	return bs
+}
+
+// SkipTime returns the time to seek to when the time is in a break.
+func SkipTime(breaks []Break, now int64) (int64, bool) {
+	for _, b := range breaks {
+		if now < b.StartTime || now >= b.EndTime {
+			continue
+		}
+		if t := b.EndTime - SkipMargin; t > now {
+			return t, true
+		}
+	}
+	return 0, false
+}
+
+// Skip seeks both timer and music when skip key is pressed in a break.
+// It returns true when skipped.
+func Skip(breaks []Break, timer *Timer, mp *MusicPlayer) bool {
+	if !inpututil.IsKeyJustPressed(SkipKey) || timer.Pause {
+		return false
+	}
+	t, ok := SkipTime(breaks, timer.Now)
+	if !ok {
+		return false
+	}
+	timer.SeekTo(t)
+	mp.SeekTo(t)
+	return true
+}
+
+// SeekTo moves the timer to given time.
+func (t *Timer) SeekTo(now int64) {
+	t.Tick = TimeToTick(now)
+	t.Now = TickToTime(t.Tick)
+	t.StartTime = time.Now().Add(-time.Duration(t.Now) * time.Millisecond)
+}
+
+// SeekTo moves the music to given time.
+// Music starts playing when the time has passed music's start.
+// Music waits at the start when the time is before music's start.
+func (p *MusicPlayer) SeekTo(now int64) {
+	if p.Player == nil {
+		return
+	}
+	t := now - p.Offset
+	if t < 0 {
+		p.Player.Pause()
+		p.Player.Seek(0)
+		return
+	}
+	if err := p.Player.Seek(time.Duration(t) * time.Millisecond); err != nil {
+		return
+	}
+	if !p.Player.IsPlaying() && !p.pause {
+		p.Player.Play()
+	}
+}
+
+// BreakDrawer draws remaining time of current break as a bar.
+// Letterbox covers top and bottom of screen during breaks when enabled.
+type BreakDrawer struct {
+	Time      int64
+	Breaks    []Break
+	Letterbox bool
+	Bar       draws.Sprite
+	Box       draws.Sprite
+}
+
+func NewBreakDrawer(breaks []Break, letterbox bool) (d BreakDrawer) {
+	d.Breaks = breaks
+	d.Letterbox = letterbox
+	{
+		src := ebiten.NewImage(int(BreakBarWidth), int(BreakBarHeight))
+		src.Fill(color.NRGBA{255, 255, 255, 192})
+		s := draws.NewSpriteFromImage(src)
+		s.SetPosition(screenSizeX/2-BreakBarWidth/2, screenSizeY/2, draws.OriginLeftMiddle)
+		d.Bar = s
+	}
+	{
+		src := ebiten.NewImage(screenSizeX, int(screenSizeY*LetterboxRatio))
+		src.Fill(color.Black)
+		d.Box = draws.NewSpriteFromImage(src)
+	}
+	return
+}
+
+func (d *BreakDrawer) Update(time int64) { d.Time = time }
+
+func (d BreakDrawer) Draw(screen *ebiten.Image) {
+	for _, b := range d.Breaks {
+		if d.Time < b.StartTime || d.Time >= b.EndTime {
+			continue
+		}
+		if d.Letterbox && b.StartTime >= 0 { // Intro has no letterbox.
+			top, bottom := d.Box, d.Box
+			top.SetPosition(0, 0, draws.OriginLeftTop)
+			bottom.SetPosition(0, screenSizeY, draws.OriginLeftBottom)
+			top.Draw(screen, nil)
+			bottom.Draw(screen, nil)
+		}
+		remained := float64(b.EndTime-d.Time) / float64(b.EndTime-b.StartTime)
+		bar := d.Bar
+		bar.SetScaleXY(remained, 1, ebiten.FilterLinear)
+		bar.Draw(screen, nil)
+		return
+	}
+}
@@ -0,0 +1,54 @@
+package gosu
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestNewBreaks(t *testing.T) {
+	ivs := [][2]int64{
+		{0, 0},
+		{1000, 8000}, // A long note covers the gap after it.
+		{12000, 12000},
+		{20000, 20000},
+		{21000, 21000},
+	}
+	want := []Break{{12000, 20000}}
+	if got := NewBreaks(ivs); !reflect.DeepEqual(got, want) {
+		t.Errorf("NewBreaks() = %v; want %v", got, want)
+	}
+	if got := NewBreaks(nil); got != nil {
+		t.Errorf("NewBreaks(nil) = %v; want nil", got)
+	}
+}
+
+func TestSkipTime(t *testing.T) {
+	breaks := []Break{{-Wait, 3000}, {10000, 20000}}
+	for _, tc := range []struct {
+		now  int64
+		want int64
+		ok   bool
+	}{
+		{-Wait, 3000 - SkipMargin, true},
+		{0, 3000 - SkipMargin, true},
+		{2000, 0, false}, // Already within the margin.
+		{5000, 0, false},
+		{10000, 20000 - SkipMargin, true},
+		{20000, 0, false},
+	} {
+		got, ok := SkipTime(breaks, tc.now)
+		if got != tc.want || ok != tc.ok {
+			t.Errorf("SkipTime(%d) = %d, %v; want %d, %v", tc.now, got, ok, tc.want, tc.ok)
+		}
+	}
+}
+
+func TestTimerSeekTo(t *testing.T) {
+	timer := NewTimer(10000, 1)
+	for _, now := range []int64{5000, -Wait, 0} {
+		timer.SeekTo(now)
+		if timer.Now != now || timer.Tick != TimeToTick(now) {
+			t.Errorf("SeekTo(%d): now %d, tick %d", now, timer.Now, timer.Tick)
+		}
+	}
+}
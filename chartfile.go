@@ -0,0 +1,223 @@
+package gosu
+
+import (
+	"crypto/md5"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/hndada/gosu/db"
+)
+
+// ChartFileExt is an extension of native chart file.
+// Native chart file is encoded in the same way as db: JSON in development, msgpack in release.
+const ChartFileExt = ".gosu"
+
+// ChartFileVersion goes up when ChartFile changes incompatibly.
+const ChartFileVersion = 2
+
+// Scratch lane is placed at the side of lanes.
+// Double play has scratch lanes at both sides.
+const (
+	ScratchNone = iota
+	ScratchLeft
+	ScratchRight
+	ScratchBoth = ScratchLeft | ScratchRight
+)
+
+// ChartFile is an intermediate representation of charts.
+// Every chart format is converted to ChartFile, and modes make charts only from it.
+// Hence a new format only needs a converter to ChartFile.
+type ChartFile struct {
+	Version int
+	Mode    int
+	ChartHeader
+	KeyCount     int // Piano only. Scratch lane is counted.
+	Scratch      int // Side of scratch lane.
+	TimingPoints []TimingPoint
+	HitObjects   []HitObject // Sorted by time.
+	BGMs         []BGM
+
+	MD5 [16]byte `json:"-" msgpack:"-"` // MD5 of source file. Not saved.
+}
+
+// chartFormat converts charts of a format to ChartFile.
+// Each format has its converter at chartfile_<format>.go.
+type chartFormat struct {
+	// convert parses the file and converts the chart at the index.
+	// File path is for formats which need it, such as MIDI.
+	convert func(dat []byte, fpath string, index int) (*ChartFile, error)
+	// mode reads a mode of the chart without converting it.
+	mode func(fpath string, index int) int
+}
+
+// chartFormats are supported chart formats by extension.
+var chartFormats = map[string]chartFormat{
+	ChartFileExt: nativeChartFormat,
+	".osu":       osuChartFormat,
+	".mc":        mcChartFormat,
+	".qua":       quaChartFormat,
+	".mid":       midiChartFormat,
+	".midi":      midiChartFormat,
+	".bms":       bmsChartFormat,
+	".bme":       bmsChartFormat,
+	".bml":       bmsChartFormat,
+	".ojn":       ojnChartFormat,
+	".sm":        smChartFormat,
+	".ssc":       smChartFormat,
+	".tja":       tjaChartFormat,
+	".lrc":       lrcChartFormat,
+	".kara":      karaChartFormat,
+}
+
+// LoadChartFile parses a chart of any supported format and converts it to ChartFile.
+// MIDI has no lanes. Index of its chart path stands for key count.
+func LoadChartFile(cpath string) (*ChartFile, error) {
+	fpath, index := SplitChartPath(cpath)
+	format, ok := chartFormats[strings.ToLower(filepath.Ext(fpath))]
+	if !ok {
+		return nil, fmt.Errorf("unsupported chart format: %s", filepath.Ext(fpath))
+	}
+	dat, err := os.ReadFile(fpath)
+	if err != nil {
+		return nil, err
+	}
+	f, err := format.convert(dat, fpath, index)
+	if err != nil {
+		return nil, err
+	}
+	// Order of notes at the same time might be intentional for gimmicks.
+	sort.SliceStable(f.HitObjects, func(i, j int) bool { return f.HitObjects[i].Time < f.HitObjects[j].Time })
+	sort.SliceStable(f.BGMs, func(i, j int) bool { return f.BGMs[i].Time < f.BGMs[j].Time })
+	f.MD5 = md5.Sum(dat)
+	if index >= 0 { // Charts in the same file are distinguished by index.
+		f.MD5 = md5.Sum(append(dat, byte(index)))
+	}
+	return f, nil
+}
+
+// pianoMode returns a mode of piano chart by its key count.
+// Scratch lane is counted.
+func pianoMode(keyCount int) int {
+	if keyCount <= 4 {
+		return ModePiano4
+	}
+	return ModePiano7
+}
+
+// maniaMode is pianoMode of osu!mania and Malody key charts,
+// whose 6 key charts are listed at Piano4.
+func maniaMode(keyCount int) int {
+	if keyCount == 6 {
+		return ModePiano4
+	}
+	return pianoMode(keyCount)
+}
+
+// checkChartIndex is for formats which have multiple charts in a file.
+func checkChartIndex(index, count int) error {
+	if index < 0 || index >= count {
+		return fmt.Errorf("invalid chart index: %d", index)
+	}
+	return nil
+}
+
+var nativeChartFormat = chartFormat{
+	convert: func(dat []byte, _ string, _ int) (*ChartFile, error) {
+		f := &ChartFile{}
+		if err := db.Unmarshal(dat, f); err != nil {
+			return nil, err
+		}
+		if f.Version > ChartFileVersion {
+			return nil, fmt.Errorf("unsupported chart file version: %d", f.Version)
+		}
+		return f, nil
+	},
+	// Only the version and the mode are decoded.
+	mode: func(fpath string, _ int) int {
+		dat, err := os.ReadFile(fpath)
+		if err != nil {
+			return ModeNone
+		}
+		var h struct {
+			Version int
+			Mode    int
+		}
+		if err := db.Unmarshal(dat, &h); err != nil || h.Version > ChartFileVersion {
+			return ModeNone
+		}
+		return h.Mode
+	},
+}
+
+// SetRate scales times of the chart for playing at the rate.
+// Times go shorter and BPMs go higher with the rate above 1.
+func (f *ChartFile) SetRate(rate float64) {
+	if rate == 0 || rate == 1 {
+		return
+	}
+	scale := func(t int64) int64 { return int64(float64(t) / rate) }
+	for i := range f.TimingPoints {
+		f.TimingPoints[i].Time = scale(f.TimingPoints[i].Time)
+		f.TimingPoints[i].BPM *= rate
+	}
+	for i := range f.HitObjects {
+		f.HitObjects[i].Time = scale(f.HitObjects[i].Time)
+		f.HitObjects[i].Duration = scale(f.HitObjects[i].Duration)
+	}
+	for i := range f.BGMs {
+		f.BGMs[i].Time = scale(f.BGMs[i].Time)
+	}
+	for i := range f.Breaks {
+		f.Breaks[i].StartTime = scale(f.Breaks[i].StartTime)
+		f.Breaks[i].EndTime = scale(f.Breaks[i].EndTime)
+	}
+	for i := range f.Bookmarks {
+		f.Bookmarks[i] = scale(f.Bookmarks[i])
+	}
+	f.PreviewTime = scale(f.PreviewTime)
+	f.VideoTimeOffset = scale(f.VideoTimeOffset)
+}
+
+// Save encodes ChartFile to the path.
+func (f ChartFile) Save(fpath string) error {
+	f.Version = ChartFileVersion
+	b, err := db.Marshal(&f)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(fpath, b, 0644)
+}
+
+// ConvertChartFile saves a chart as a native chart file at the same folder.
+// It returns the path of saved file.
+func ConvertChartFile(cpath string) (string, error) {
+	f, err := LoadChartFile(cpath)
+	if err != nil {
+		return "", err
+	}
+	fpath, index := SplitChartPath(cpath)
+	name := strings.TrimSuffix(fpath, filepath.Ext(fpath))
+	if index >= 0 {
+		name = fmt.Sprintf("%s [%d]", name, index)
+	}
+	if err := f.Save(name + ChartFileExt); err != nil {
+		return "", err
+	}
+	return name + ChartFileExt, nil
+}
+
+// sameNameMusicFilename returns the name of audio file which has
+// the same name with the chart file, e.g., MIDI and LRC file.
+// It returns empty string when not found.
+func sameNameMusicFilename(fpath string) string {
+	base := strings.TrimSuffix(fpath, filepath.Ext(fpath))
+	for _, ext := range []string{".ogg", ".mp3", ".wav"} {
+		if _, err := os.Stat(base + ext); err == nil {
+			return filepath.Base(base + ext)
+		}
+	}
+	return ""
+}
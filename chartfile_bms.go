@@ -0,0 +1,97 @@
+package gosu
+
+import (
+	"fmt"
+
+	"github.com/hndada/gosu/format/bms"
+)
+
+// BMS charts are listed at Piano7 regardless of key count.
+var bmsChartFormat = chartFormat{
+	convert: func(dat []byte, _ string, _ int) (*ChartFile, error) {
+		f, err := bms.Parse(dat)
+		if err != nil {
+			return nil, err
+		}
+		return newChartFileFromBMS(f), nil
+	},
+	mode: func(string, int) int { return ModePiano7 },
+}
+
+func newChartFileFromBMS(f *bms.Format) *ChartFile {
+	c := &ChartFile{
+		Version: ChartFileVersion,
+		Mode:    ModePiano7,
+		ChartHeader: ChartHeader{
+			MusicName: f.Title,
+			Artist:    f.Artist,
+			ChartName: f.SubTitle,
+			Charter:   f.Charter(),
+			// BMS has no music file; all sounds are keysounds.
+			ImageFilename:  f.StageFile,
+			BannerFilename: f.Banner,
+		},
+	}
+	if c.ChartName == "" && f.Difficulty >= 0 && f.Difficulty < len(bms.Difficulties) {
+		c.ChartName = bms.Difficulties[f.Difficulty]
+	}
+	if c.ChartName == "" {
+		c.ChartName = fmt.Sprintf("Level %d", f.PlayLevel)
+	}
+	if c.ImageFilename == "" {
+		c.ImageFilename = f.BackBMP
+	}
+	keys, scratch := f.Keys()
+	c.KeyCount = keys
+	if scratch {
+		c.KeyCount++
+		c.Scratch = ScratchLeft
+		if keys > 7 { // Double play.
+			c.KeyCount++
+			c.Scratch = ScratchBoth
+		}
+	}
+
+	points := make([]measurePoint, 0, len(f.Measures)+len(f.BPMs)+2*len(f.Stops))
+	for _, m := range f.Measures {
+		points = append(points, measurePoint{m.Time, kindMeasure, m.Length})
+	}
+	for _, b := range f.BPMs {
+		points = append(points, measurePoint{b.Time, kindBPM, b.BPM})
+	}
+	for _, s := range f.Stops {
+		points = append(points, measurePoint{s.Time, kindStopStart, 0})
+		points = append(points, measurePoint{s.Time + s.Duration, kindStopEnd, 0})
+	}
+	c.TimingPoints = newMeasureTimingPoints(f.BPM, points)
+
+	c.HitObjects = make([]HitObject, 0, len(f.Notes))
+	for _, bn := range f.Notes {
+		col := bms.Column(bn.Channel, keys, scratch)
+		if col < 0 {
+			continue
+		}
+		h := HitObject{
+			Time:   int64(bn.Time),
+			Type:   HitObjectNote,
+			Column: col,
+		}
+		if name := f.WAVs[bn.WAV]; name != "" {
+			h.Samples = []Sample{{Name: name}}
+		}
+		if bn.Duration > 0 {
+			h.Type = HitObjectLongNote
+			h.Duration = int64(bn.Time+bn.Duration) - h.Time
+		}
+		c.HitObjects = append(c.HitObjects, h)
+	}
+
+	c.BGMs = make([]BGM, 0, len(f.BGMs))
+	for _, n := range f.BGMs {
+		c.BGMs = append(c.BGMs, BGM{
+			Time:   int64(n.Time),
+			Sample: Sample{Name: f.WAVs[n.WAV]},
+		})
+	}
+	return c
+}
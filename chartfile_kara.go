@@ -0,0 +1,57 @@
+package gosu
+
+import "github.com/hndada/gosu/format/kara"
+
+var karaChartFormat = chartFormat{
+	convert: func(dat []byte, _ string, _ int) (*ChartFile, error) {
+		f, err := kara.Parse(dat)
+		if err != nil {
+			return nil, err
+		}
+		return newChartFileFromKara(f), nil
+	},
+	mode: func(string, int) int { return ModeKaraoke },
+}
+
+func newChartFileFromKara(f *kara.Format) *ChartFile {
+	c := &ChartFile{
+		Version: ChartFileVersion,
+		Mode:    ModeKaraoke,
+		ChartHeader: ChartHeader{
+			MusicName:     f.Title,
+			MusicUnicode:  f.TitleUnicode,
+			Artist:        f.Artist,
+			ArtistUnicode: f.ArtistUnicode,
+			MusicSource:   f.Source,
+			ChartName:     f.Version,
+			Charter:       f.Creator,
+			PreviewTime:   int64(f.PreviewTime),
+			MusicFilename: f.AudioFilename,
+			ImageFilename: f.BackgroundFilename,
+		},
+		TimingPoints: newTimingPointsFromKara(f),
+	}
+	c.HitObjects = make([]HitObject, 0, len(f.Syllables))
+	for _, s := range f.Syllables {
+		c.HitObjects = append(c.HitObjects, HitObject{
+			Time:     int64(s.Time),
+			Type:     HitObjectNote,
+			Duration: int64(s.Duration),
+			Text:     s.Text,
+			Line:     s.Line,
+		})
+	}
+	return c
+}
+
+func newTimingPointsFromKara(f *kara.Format) []TimingPoint {
+	if len(f.TimingPoints) == 0 {
+		return newMeasureTimingPoints(120, []measurePoint{{0, kindMeasure, 1}})
+	}
+	points := make([]measurePoint, 0, 2*len(f.TimingPoints))
+	for _, tp := range f.TimingPoints {
+		points = append(points, measurePoint{tp.Time, kindBPM, tp.BPM()})
+		points = append(points, measurePoint{tp.Time, kindMeasure, float64(tp.Meter) / 4})
+	}
+	return newMeasureTimingPoints(f.TimingPoints[0].BPM(), points)
+}
@@ -0,0 +1,53 @@
+package gosu
+
+import (
+	"path/filepath"
+	"strings"
+
+	"github.com/hndada/gosu/format/lrc"
+)
+
+var lrcChartFormat = chartFormat{
+	convert: func(dat []byte, fpath string, _ int) (*ChartFile, error) {
+		f, err := lrc.Parse(dat)
+		if err != nil {
+			return nil, err
+		}
+		return newChartFileFromLRC(f, fpath), nil
+	},
+	mode: func(string, int) int { return ModeKaraoke },
+}
+
+func newChartFileFromLRC(f *lrc.Format, fpath string) *ChartFile {
+	// LRC has no music file. Music goes silent unless
+	// an audio file with the same name is found.
+	c := &ChartFile{
+		Version: ChartFileVersion,
+		Mode:    ModeKaraoke,
+		ChartHeader: ChartHeader{
+			MusicName:     f.Title,
+			Artist:        f.Artist,
+			MusicSource:   f.Album,
+			ChartName:     "Lyrics",
+			Charter:       f.Creator,
+			MusicFilename: sameNameMusicFilename(fpath),
+		},
+		// LRC has no timing. Bars go every 2 seconds.
+		TimingPoints: newMeasureTimingPoints(120, []measurePoint{{0, kindMeasure, 1}}),
+	}
+	if c.MusicName == "" {
+		c.MusicName = strings.TrimSuffix(filepath.Base(fpath), filepath.Ext(fpath))
+	}
+	for i, l := range f.Lines {
+		for _, w := range l.Words {
+			c.HitObjects = append(c.HitObjects, HitObject{
+				Time:     w.Time,
+				Type:     HitObjectNote,
+				Duration: w.Duration,
+				Text:     w.Text,
+				Line:     i,
+			})
+		}
+	}
+	return c
+}
@@ -0,0 +1,108 @@
+package gosu
+
+import "github.com/hndada/gosu/format/mc"
+
+var mcChartFormat = chartFormat{
+	convert: func(dat []byte, _ string, _ int) (*ChartFile, error) {
+		f, err := mc.Parse(dat)
+		if err != nil {
+			return nil, err
+		}
+		return newChartFileFromMc(f), nil
+	},
+	mode: func(fpath string, _ int) int {
+		mode, keyCount := mc.Mode(fpath)
+		switch mode {
+		case mc.ModeKey:
+			return maniaMode(keyCount)
+		case mc.ModeTaiko:
+			return ModeDrum
+		}
+		return ModeNone
+	},
+}
+
+func newChartFileFromMc(f *mc.Format) *ChartFile {
+	m := f.Meta
+	c := &ChartFile{
+		Version: ChartFileVersion,
+		Mode:    ModeNone,
+		ChartHeader: ChartHeader{
+			ChartSetID:    int64(m.Song.ID),
+			ChartID:       int64(m.ID),
+			MusicName:     m.Song.Title,
+			MusicUnicode:  m.Song.TitleOrg,
+			Artist:        m.Song.Artist,
+			ArtistUnicode: m.Song.ArtistOrg,
+			ChartName:     m.Version,
+			Charter:       m.Creator,
+			PreviewTime:   int64(m.Preview),
+			ImageFilename: m.Background,
+		},
+	}
+	if music, ok := f.Music(); ok {
+		c.MusicFilename = music.Sound
+	}
+	switch m.Mode {
+	case mc.ModeKey:
+		c.KeyCount = m.ModeExt.Column
+		c.Mode = maniaMode(c.KeyCount)
+	case mc.ModeTaiko:
+		c.Mode = ModeDrum
+	}
+
+	if len(f.Time) > 0 {
+		// Bars are drawn from each BPM point.
+		points := make([]measurePoint, 0, 2*len(f.Time)+len(f.Effect))
+		for _, b := range f.Time {
+			time := f.BeatTime(b.Beat.Value())
+			points = append(points, measurePoint{time, kindBPM, b.BPM})
+			points = append(points, measurePoint{time, kindMeasure, 1})
+		}
+		for _, e := range f.Effect {
+			points = append(points, measurePoint{f.BeatTime(e.Beat.Value()), kindScroll, e.Scroll})
+		}
+		c.TimingPoints = newMeasureTimingPoints(f.Time[0].BPM, points)
+	}
+
+	c.HitObjects = make([]HitObject, 0, len(f.Note))
+	for _, mn := range f.Note {
+		if mn.Type == mc.NoteTypeMusic {
+			continue
+		}
+		h := HitObject{
+			Time:   int64(f.BeatTime(mn.Beat.Value())),
+			Type:   HitObjectNote,
+			Column: mn.Column,
+		}
+		if mn.EndBeat != nil {
+			h.Duration = int64(f.BeatTime(mn.EndBeat.Value())) - h.Time
+		}
+		if c.Mode == ModeDrum {
+			// Style follows Taiko Jiro's.
+			switch mn.Style {
+			case 1, 3:
+			case 2, 4:
+				h.Kat = true
+			case 5, 6:
+				h.Type = HitObjectRoll
+			case 7:
+				h.Type = HitObjectShake
+				h.Hits = mn.Hits
+			default:
+				continue
+			}
+			switch mn.Style {
+			case 3, 4, 6:
+				h.Big = true
+			}
+		} else if mn.EndBeat != nil {
+			h.Type = HitObjectLongNote
+		}
+		if h.Type == HitObjectNote {
+			h.Duration = 0
+		}
+		c.HitObjects = append(c.HitObjects, h)
+	}
+	return c
+}
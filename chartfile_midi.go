@@ -0,0 +1,76 @@
+package gosu
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/hndada/gosu/format/midi"
+)
+
+// MIDI has no lanes. Index of chart path stands for key count.
+// MIDI file path without index is regarded as Piano4.
+var midiChartFormat = chartFormat{
+	convert: func(dat []byte, fpath string, index int) (*ChartFile, error) {
+		if index <= 0 {
+			return nil, fmt.Errorf("invalid key count: %d", index)
+		}
+		f, err := midi.Parse(dat)
+		if err != nil {
+			return nil, err
+		}
+		return newChartFileFromMIDI(f, fpath, index), nil
+	},
+	mode: func(_ string, index int) int { return pianoMode(index) },
+}
+
+func newChartFileFromMIDI(f *midi.Format, fpath string, keyCount int) *ChartFile {
+	// MIDI has no music file. Music goes silent unless
+	// an audio file with the same name is found.
+	c := &ChartFile{
+		Version: ChartFileVersion,
+		Mode:    pianoMode(keyCount),
+		ChartHeader: ChartHeader{
+			MusicName:     f.Name,
+			ChartName:     fmt.Sprintf("%dK", keyCount),
+			MusicFilename: sameNameMusicFilename(fpath),
+		},
+		KeyCount: keyCount,
+	}
+	if c.MusicName == "" {
+		c.MusicName = strings.TrimSuffix(filepath.Base(fpath), filepath.Ext(fpath))
+	}
+
+	tempMainBPM := 120.0
+	if len(f.Tempos) > 0 && f.Tempos[0].Tick == 0 {
+		tempMainBPM = f.Tempos[0].BPM
+	}
+	points := make([]measurePoint, 0, len(f.Tempos)+len(f.Measures)+1)
+	points = append(points, measurePoint{0, kindBPM, tempMainBPM})
+	for _, t := range f.Tempos {
+		points = append(points, measurePoint{t.Time, kindBPM, t.BPM})
+	}
+	for _, m := range f.Measures {
+		points = append(points, measurePoint{m.Time, kindMeasure, m.Length})
+	}
+	c.TimingPoints = newMeasureTimingPoints(tempMainBPM, points)
+
+	c.HitObjects = make([]HitObject, 0, len(f.Notes))
+	for _, mn := range f.Notes {
+		if mn.Channel == midi.DrumChannel {
+			continue
+		}
+		h := HitObject{
+			Time:   int64(mn.Time),
+			Type:   HitObjectNote,
+			Column: -1,
+			Pitch:  mn.Pitch,
+		}
+		if float64(mn.Length) >= MIDILongNoteBeats*float64(f.Division) {
+			h.Type = HitObjectLongNote
+			h.Duration = int64(mn.Time+mn.Duration) - h.Time
+		}
+		c.HitObjects = append(c.HitObjects, h)
+	}
+	return c
+}
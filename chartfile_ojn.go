@@ -0,0 +1,85 @@
+package gosu
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/hndada/gosu/format/ojn"
+)
+
+// O2Jam charts are always in 7 keys.
+var ojnChartFormat = chartFormat{
+	convert: func(dat []byte, _ string, index int) (*ChartFile, error) {
+		f, err := ojn.Parse(dat)
+		if err != nil {
+			return nil, err
+		}
+		if err := checkChartIndex(index, len(f.Charts)); err != nil {
+			return nil, err
+		}
+		return newChartFileFromOJN(f.Single(index)), nil
+	},
+	mode: func(string, int) int { return ModePiano7 },
+}
+
+func newChartFileFromOJN(f *ojn.Single) *ChartFile {
+	c := &ChartFile{
+		Version: ChartFileVersion,
+		Mode:    ModePiano7,
+		// Todo: use cover image embedded in OJN file
+		ChartHeader: ChartHeader{
+			ChartID:   int64(f.SongID),
+			MusicName: f.Title,
+			Artist:    f.Artist,
+			ChartName: fmt.Sprintf("%s Lv.%d", ojn.Difficulties[f.Difficulty], f.Levels[f.Difficulty]),
+			Charter:   f.Noter,
+
+			SampleFilename: f.OJMFile,
+		},
+		KeyCount: 7,
+	}
+
+	points := make([]measurePoint, 0, len(f.Measures)+len(f.BPMs))
+	for _, m := range f.Measures {
+		points = append(points, measurePoint{m.Time, kindMeasure, m.Length})
+	}
+	for _, b := range f.BPMs {
+		points = append(points, measurePoint{b.Time, kindBPM, b.BPM})
+	}
+	c.TimingPoints = newMeasureTimingPoints(f.BPM, points)
+
+	c.HitObjects = make([]HitObject, 0, len(f.Notes))
+	for _, on := range f.Notes {
+		h := HitObject{
+			Time:   int64(on.Time),
+			Type:   HitObjectNote,
+			Column: on.Key,
+		}
+		if on.Sample >= 0 {
+			h.Samples = []Sample{{
+				Name:   strconv.Itoa(on.Sample),
+				Volume: on.Volume,
+			}}
+		}
+		if on.Duration > 0 {
+			h.Type = HitObjectLongNote
+			h.Duration = int64(on.Time+on.Duration) - h.Time
+		}
+		c.HitObjects = append(c.HitObjects, h)
+	}
+
+	c.BGMs = make([]BGM, 0, len(f.BGMs))
+	for _, n := range f.BGMs {
+		if n.Sample < 0 {
+			continue
+		}
+		c.BGMs = append(c.BGMs, BGM{
+			Time: int64(n.Time),
+			Sample: Sample{
+				Name:   strconv.Itoa(n.Sample),
+				Volume: n.Volume,
+			},
+		})
+	}
+	return c
+}
@@ -0,0 +1,151 @@
+package gosu
+
+import (
+	"sort"
+
+	"github.com/hndada/gosu/format/osu"
+)
+
+var osuChartFormat = chartFormat{
+	convert: func(dat []byte, _ string, _ int) (*ChartFile, error) {
+		f, err := osu.Parse(dat)
+		if err != nil {
+			return nil, err
+		}
+		return newChartFileFromOsu(f), nil
+	},
+	mode: func(fpath string, _ int) int {
+		mode, keyCount := osu.Mode(fpath)
+		switch mode {
+		case osu.ModeMania:
+			return maniaMode(keyCount)
+		case osu.ModeTaiko:
+			return ModeDrum
+		}
+		return ModeNone
+	},
+}
+
+func newChartFileFromOsu(f *osu.Format) *ChartFile {
+	c := &ChartFile{
+		Version:      ChartFileVersion,
+		Mode:         ModeNone,
+		ChartHeader:  newChartHeaderFromOsu(f),
+		TimingPoints: newTimingPointsFromOsu(f),
+	}
+	switch f.Mode {
+	case osu.ModeMania:
+		c.KeyCount = int(f.CircleSize)
+		if f.SpecialStyle && c.KeyCount == 8 { // N+1 style.
+			c.Scratch = ScratchLeft
+		}
+		c.Mode = maniaMode(c.KeyCount)
+	case osu.ModeTaiko:
+		c.Mode = ModeDrum
+	}
+	c.HitObjects = make([]HitObject, 0, len(f.HitObjects))
+	for _, ho := range f.HitObjects {
+		h := HitObject{
+			Time:    int64(ho.Time),
+			Type:    HitObjectNote,
+			Samples: newOsuSamples(f, ho),
+		}
+		if c.Mode == ModeDrum {
+			switch {
+			case ho.NoteType&osu.HitTypeSlider != 0:
+				h.Type = HitObjectRoll
+				s, _ := f.Slider(ho)
+				h.Duration = int64(s.Duration)
+			case ho.NoteType&osu.HitTypeSpinner != 0:
+				h.Type = HitObjectShake
+				h.Duration = int64(ho.EndTime) - h.Time
+			}
+			h.Kat = osu.IsKat(ho)
+			h.Big = osu.IsBig(ho)
+		} else {
+			h.Column = ho.Column(c.KeyCount)
+			if ho.NoteType&osu.ComboMask == osu.HitTypeHoldNote {
+				h.Type = HitObjectLongNote
+				h.Duration = int64(ho.EndTime) - h.Time
+			}
+		}
+		c.HitObjects = append(c.HitObjects, h)
+	}
+	return c
+}
+
+func newChartHeaderFromOsu(f *osu.Format) ChartHeader {
+	c := ChartHeader{
+		MusicName:     f.Title,
+		MusicUnicode:  f.TitleUnicode,
+		Artist:        f.Artist,
+		ArtistUnicode: f.ArtistUnicode,
+		MusicSource:   f.Source,
+		ChartName:     f.Version,
+		Charter:       f.Creator,
+
+		PreviewTime:   int64(f.PreviewTime),
+		MusicFilename: f.AudioFilename,
+	}
+	var e osu.Event
+	e, _ = f.Background()
+	c.ImageFilename = e.Filename
+	e, _ = f.Video()
+	c.VideoFilename, c.VideoTimeOffset = e.Filename, int64(e.StartTime)
+	for _, e := range f.Events {
+		if e.Type == "Break" {
+			c.Breaks = append(c.Breaks, Break{int64(e.StartTime), int64(e.EndTime)})
+		}
+	}
+	c.LetterboxInBreaks = f.LetterboxInBreaks
+	for _, t := range f.Editor.Bookmarks {
+		c.Bookmarks = append(c.Bookmarks, int64(t))
+	}
+	c.SamplesMatchPlaybackRate = f.SamplesMatchPlaybackRate
+	return c
+}
+
+// First BPM is used as temporary main BPM.
+// No two TimingPoints have same Time.
+func newTimingPointsFromOsu(f *osu.Format) []TimingPoint {
+	sort.SliceStable(f.TimingPoints, func(i int, j int) bool {
+		if f.TimingPoints[i].Time == f.TimingPoints[j].Time {
+			return f.TimingPoints[i].Uninherited
+		}
+		return f.TimingPoints[i].Time < f.TimingPoints[j].Time
+	})
+	// Inherited points without Uninherited points will go dropped.
+	for len(f.TimingPoints) > 0 && !f.TimingPoints[0].Uninherited {
+		f.TimingPoints = f.TimingPoints[1:]
+	}
+	if len(f.TimingPoints) == 0 {
+		return nil
+	}
+	tempMainBPM := f.TimingPoints[0].BPM()
+	tps := make([]TimingPoint, 0, len(f.TimingPoints))
+	prevBPM := tempMainBPM
+	for _, timingPoint := range f.TimingPoints {
+		tp := TimingPoint{
+			Time:      int64(timingPoint.Time),
+			BPM:       prevBPM,
+			Speed:     prevBPM / tempMainBPM,
+			Meter:     timingPoint.Meter,
+			NewBeat:   timingPoint.Uninherited,
+			Volume:    float64(timingPoint.Volume) / 100,
+			Highlight: timingPoint.IsKiai(),
+		}
+		if timingPoint.Uninherited {
+			tp.BPM = timingPoint.BPM()
+			tp.Speed = tp.BPM / tempMainBPM
+		} else {
+			tp.Speed *= timingPoint.BeatLengthScale()
+		}
+		if len(tps) > 0 && tps[len(tps)-1].Time == tp.Time { // Drop a TimingPoint with a same time
+			tp.NewBeat = tps[len(tps)-1].NewBeat || tp.NewBeat
+			tps = tps[:len(tps)-1]
+		}
+		tps = append(tps, tp)
+		prevBPM = tp.BPM
+	}
+	return tps
+}
@@ -0,0 +1,139 @@
+package gosu
+
+import (
+	"sort"
+
+	"github.com/hndada/gosu/format/qua"
+)
+
+var quaChartFormat = chartFormat{
+	convert: func(dat []byte, _ string, _ int) (*ChartFile, error) {
+		f, err := qua.Parse(dat)
+		if err != nil {
+			return nil, err
+		}
+		return newChartFileFromQua(f), nil
+	},
+	mode: func(fpath string, _ int) int {
+		keyCount := qua.KeyCount(fpath)
+		if keyCount == 0 {
+			return ModeNone
+		}
+		return pianoMode(keyCount)
+	},
+}
+
+func newChartFileFromQua(f *qua.Format) *ChartFile {
+	c := &ChartFile{
+		Version: ChartFileVersion,
+		ChartHeader: ChartHeader{
+			ChartSetID:     int64(f.MapSetId),
+			ChartID:        int64(f.MapId),
+			MusicName:      f.Title,
+			MusicUnicode:   f.Title,
+			Artist:         f.Artist,
+			ArtistUnicode:  f.Artist,
+			MusicSource:    f.Source,
+			ChartName:      f.DifficultyName,
+			Charter:        f.Creator,
+			PreviewTime:    int64(f.SongPreviewTime),
+			MusicFilename:  f.AudioFile,
+			ImageFilename:  f.BackgroundFile,
+			BannerFilename: f.BannerFile,
+		},
+		KeyCount:     f.KeyCount(),
+		TimingPoints: newTimingPointsFromQua(f),
+	}
+	if f.HasScratchKey {
+		c.Scratch = ScratchRight
+	}
+	c.Mode = pianoMode(c.KeyCount)
+
+	c.HitObjects = make([]HitObject, 0, len(f.HitObjects))
+	for _, ho := range f.HitObjects {
+		h := HitObject{
+			Time:   int64(ho.StartTime),
+			Type:   HitObjectNote,
+			Column: ho.Lane - 1,
+		}
+		for _, ks := range ho.KeySounds {
+			h.Samples = append(h.Samples, Sample{
+				Name:   f.Sample(ks.Sample),
+				Volume: float64(ks.Volume) / 100,
+			})
+		}
+		if ho.EndTime > ho.StartTime {
+			h.Type = HitObjectLongNote
+			h.Duration = int64(ho.EndTime - ho.StartTime)
+		}
+		c.HitObjects = append(c.HitObjects, h)
+	}
+
+	c.BGMs = make([]BGM, 0, len(f.SoundEffects))
+	for _, e := range f.SoundEffects {
+		c.BGMs = append(c.BGMs, BGM{
+			Time: int64(e.StartTime),
+			Sample: Sample{
+				Name:   f.Sample(e.Sample),
+				Volume: float64(e.Volume) / 100,
+			},
+		})
+	}
+	return c
+}
+
+func newTimingPointsFromQua(f *qua.Format) []TimingPoint {
+	sort.SliceStable(f.TimingPoints, func(i, j int) bool {
+		return f.TimingPoints[i].StartTime < f.TimingPoints[j].StartTime
+	})
+	sort.SliceStable(f.SliderVelocities, func(i, j int) bool {
+		return f.SliderVelocities[i].StartTime < f.SliderVelocities[j].StartTime
+	})
+	if len(f.TimingPoints) == 0 {
+		return nil
+	}
+	tempMainBPM := f.TimingPoints[0].Bpm
+	var (
+		bpm   = tempMainBPM
+		meter = 4
+		sv    = f.ScrollVelocity()
+		i, j  int
+	)
+	tps := make([]TimingPoint, 0, len(f.TimingPoints)+len(f.SliderVelocities))
+	// Timing point goes first at the same time.
+	for i < len(f.TimingPoints) || j < len(f.SliderVelocities) {
+		var (
+			time    float64
+			newBeat bool
+		)
+		if j == len(f.SliderVelocities) ||
+			i < len(f.TimingPoints) && f.TimingPoints[i].StartTime <= f.SliderVelocities[j].StartTime {
+			tp := f.TimingPoints[i]
+			time, bpm, meter, newBeat = tp.StartTime, tp.Bpm, tp.Meter(), true
+			i++
+		} else {
+			time, sv = f.SliderVelocities[j].StartTime, f.SliderVelocities[j].Multiplier
+			j++
+			if i == 0 { // Slider velocities before the first timing point only affect initial speed.
+				continue
+			}
+		}
+		tp := TimingPoint{
+			Time:    int64(time),
+			BPM:     bpm,
+			Speed:   bpm / tempMainBPM * sv,
+			Meter:   meter,
+			NewBeat: newBeat,
+			Volume:  1,
+		}
+		if f.BPMDoesNotAffectScrollVelocity {
+			tp.Speed = sv
+		}
+		if len(tps) > 0 && tps[len(tps)-1].Time == tp.Time { // Drop a TimingPoint with a same time
+			tp.NewBeat = tps[len(tps)-1].NewBeat || tp.NewBeat
+			tps = tps[:len(tps)-1]
+		}
+		tps = append(tps, tp)
+	}
+	return tps
+}
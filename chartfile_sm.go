@@ -0,0 +1,115 @@
+package gosu
+
+import (
+	"fmt"
+
+	"github.com/hndada/gosu/format/sm"
+)
+
+// Only dance-single charts are listed, which are in 4 keys.
+var smChartFormat = chartFormat{
+	convert: func(dat []byte, _ string, index int) (*ChartFile, error) {
+		f, err := sm.Parse(dat)
+		if err != nil {
+			return nil, err
+		}
+		if err := checkChartIndex(index, len(f.Charts)); err != nil {
+			return nil, err
+		}
+		return newChartFileFromSM(f.Single(index)), nil
+	},
+	mode: func(string, int) int { return ModePiano4 },
+}
+
+func newChartFileFromSM(f *sm.Single) *ChartFile {
+	c := &ChartFile{
+		Version: ChartFileVersion,
+		ChartHeader: ChartHeader{
+			MusicName:      f.Title,
+			MusicUnicode:   f.Title,
+			Artist:         f.Artist,
+			ArtistUnicode:  f.Artist,
+			ChartName:      fmt.Sprintf("%s %d", f.Difficulty, f.Meter),
+			Charter:        f.Chart.Credit,
+			PreviewTime:    int64(f.SampleStart * 1000),
+			MusicFilename:  f.Music,
+			ImageFilename:  f.Background,
+			BannerFilename: f.Banner,
+		},
+		KeyCount:     f.KeyCount(),
+		TimingPoints: newTimingPointsFromSM(f),
+	}
+	c.Mode = pianoMode(c.KeyCount)
+	// Transliterated texts are in alphabet.
+	if f.TitleTranslit != "" {
+		c.MusicName = f.TitleTranslit
+	}
+	if f.ArtistTranslit != "" {
+		c.Artist = f.ArtistTranslit
+	}
+	if c.Charter == "" {
+		c.Charter = f.Description
+	}
+	if c.Charter == "" {
+		c.Charter = f.Header.Credit
+	}
+	if c.ImageFilename == "" {
+		c.ImageFilename = f.Banner
+	}
+
+	c.HitObjects = make([]HitObject, 0, len(f.Notes))
+	for _, sn := range f.Notes {
+		h := HitObject{
+			Time:   int64(sn.Time),
+			Type:   HitObjectNote,
+			Column: sn.Key,
+		}
+		if sn.Type != sm.Tap && sn.Duration > 0 {
+			h.Type = HitObjectLongNote
+			if sn.Type == sm.Roll {
+				h.Type = HitObjectRoll
+			}
+			h.Duration = int64(sn.Time+sn.Duration) - h.Time
+		}
+		c.HitObjects = append(c.HitObjects, h)
+	}
+	return c
+}
+
+func newTimingPointsFromSM(f *sm.Single) []TimingPoint {
+	t := f.Timing
+	points := make([]measurePoint, 0, f.MeasureCount+len(t.BPMs))
+	for m := 0; m < f.MeasureCount; m++ {
+		points = append(points, measurePoint{t.Time(float64(4 * m)), kindMeasure, 1})
+	}
+	for _, b := range t.BPMs {
+		points = append(points, measurePoint{t.Time(b.Beat), kindBPM, b.Value})
+	}
+	for _, s := range t.Stops {
+		points = append(points, measurePoint{t.Time(s.Beat), kindStopStart, 0})
+		points = append(points, measurePoint{t.Time(s.Beat) + s.Value*1000, kindStopEnd, 0})
+	}
+	for _, d := range t.Delays {
+		points = append(points, measurePoint{t.Time(d.Beat) - d.Value*1000, kindStopStart, 0})
+		points = append(points, measurePoint{t.Time(d.Beat), kindStopEnd, 0})
+	}
+	// Scrolls and speeds are multiplied.
+	// Todo: apply speed changes gradually
+	var (
+		scroll = 1.0
+		speed  = 1.0
+		i, j   int
+	)
+	for i < len(t.Scrolls) || j < len(t.Speeds) {
+		var beat float64
+		if j == len(t.Speeds) || i < len(t.Scrolls) && t.Scrolls[i].Beat <= t.Speeds[j].Beat {
+			beat, scroll = t.Scrolls[i].Beat, t.Scrolls[i].Value
+			i++
+		} else {
+			beat, speed = t.Speeds[j].Beat, t.Speeds[j].Ratio
+			j++
+		}
+		points = append(points, measurePoint{t.Time(beat), kindScroll, scroll * speed})
+	}
+	return newMeasureTimingPoints(t.BPMs[0].Value, points)
+}
@@ -0,0 +1,96 @@
+package gosu
+
+import (
+	"fmt"
+	"math"
+	"strings"
+
+	"github.com/hndada/gosu/format/tja"
+)
+
+var tjaChartFormat = chartFormat{
+	convert: func(dat []byte, _ string, index int) (*ChartFile, error) {
+		f, err := tja.Parse(dat)
+		if err != nil {
+			return nil, err
+		}
+		if err := checkChartIndex(index, len(f.Courses)); err != nil {
+			return nil, err
+		}
+		return newChartFileFromTJA(f.Single(index)), nil
+	},
+	mode: func(string, int) int { return ModeDrum },
+}
+
+func newChartFileFromTJA(f *tja.Single) *ChartFile {
+	c := &ChartFile{
+		Version: ChartFileVersion,
+		Mode:    ModeDrum,
+		ChartHeader: ChartHeader{
+			MusicName:     f.Title,
+			MusicSource:   f.Genre,
+			PreviewTime:   int64(f.DemoStart * 1000),
+			MusicFilename: f.Wave,
+		},
+		TimingPoints: newTimingPointsFromTJA(f),
+	}
+	// Subtitle usually starts with "--" or "++", followed by artist.
+	c.Artist = strings.TrimLeft(f.SubTitle, "-+")
+	c.ChartName = fmt.Sprintf("Level %d", f.Level)
+	if f.Course.Course >= 0 && f.Course.Course < len(tja.Courses) {
+		c.ChartName = fmt.Sprintf("%s %d", tja.Courses[f.Course.Course], f.Level)
+	}
+
+	c.HitObjects = make([]HitObject, 0, len(f.Notes))
+	for _, tn := range f.Notes {
+		h := HitObject{
+			Time: int64(tn.Time),
+			Type: HitObjectNote,
+			Big:  tn.Big,
+		}
+		switch tn.Type {
+		case tja.Roll:
+			h.Type = HitObjectRoll
+			h.Duration = int64(tn.Duration)
+		case tja.Balloon:
+			h.Type = HitObjectShake
+			h.Duration = int64(tn.Duration)
+			h.Hits = tn.Hits
+		case tja.Ka:
+			h.Kat = true
+		}
+		c.HitObjects = append(c.HitObjects, h)
+	}
+	return c
+}
+
+func newTimingPointsFromTJA(f *tja.Single) []TimingPoint {
+	if len(f.Points) == 0 {
+		return nil
+	}
+	tempMainBPM := f.Points[0].BPM
+	tps := make([]TimingPoint, 0, len(f.Points))
+	for _, p := range f.Points {
+		tp := TimingPoint{
+			Time:      int64(p.Time),
+			BPM:       p.BPM,
+			Speed:     p.BPM / tempMainBPM * p.Scroll,
+			Meter:     int(math.Ceil(4 * p.Length)),
+			NewBeat:   p.NewMeasure,
+			Volume:    1,
+			Highlight: p.GoGo,
+		}
+		if p.Stop {
+			tp.Speed = 0
+		}
+		if tp.Meter < 1 {
+			tp.Meter = 1
+		}
+		if len(tps) > 0 && tps[len(tps)-1].Time == tp.Time { // Drop a TimingPoint with a same time
+			tp.NewBeat = tps[len(tps)-1].NewBeat || tp.NewBeat
+			tps = tps[:len(tps)-1]
+		}
+		tps = append(tps, tp)
+	}
+	return tps
+}
@@ -1,10 +1,6 @@
 package gosu
 
-import (
-	"path/filepath"
-
-	"github.com/hndada/gosu/format/osu"
-)
+import "path/filepath"
 
 // ChartHeader contains non-play information.
 // Chaning ChartHeader's data will not affect integrity of the chart.
@@ -24,32 +20,16 @@ type ChartHeader struct {
 	PreviewTime     int64
 	MusicFilename   string
 	ImageFilename   string
+	BannerFilename  string
 	VideoFilename   string
 	VideoTimeOffset int64
-}
+	SampleFilename  string // Archive of samples, e.g., OJM file of O2Jam.
 
-func NewChartHeader(f any) (c ChartHeader) {
-	switch f := f.(type) {
-	case *osu.Format:
-		c = ChartHeader{
-			MusicName:     f.Title,
-			MusicUnicode:  f.TitleUnicode,
-			Artist:        f.Artist,
-			ArtistUnicode: f.ArtistUnicode,
-			MusicSource:   f.Source,
-			ChartName:     f.Version,
-			Charter:       f.Creator,
+	Breaks            []Break
+	LetterboxInBreaks bool
+	Bookmarks         []int64 // Start points at practice.
 
-			PreviewTime:   int64(f.PreviewTime),
-			MusicFilename: f.AudioFilename,
-		}
-		var e osu.Event
-		e, _ = f.Background()
-		c.ImageFilename = e.Filename
-		e, _ = f.Video()
-		c.VideoFilename, c.VideoTimeOffset = e.Filename, int64(e.StartTime)
-	}
-	return c
+	SamplesMatchPlaybackRate bool // Hit sounds change pitch along with the rate.
 }
 
 func (c ChartHeader) MusicPath(cpath string) (string, bool) {
@@ -61,3 +41,9 @@ func (c ChartHeader) MusicPath(cpath string) (string, bool) {
 func (c ChartHeader) BackgroundPath(cpath string) string {
 	return filepath.Join(filepath.Dir(cpath), c.ImageFilename)
 }
+func (c ChartHeader) BannerPath(cpath string) (string, bool) {
+	if c.BannerFilename == "" {
+		return "", false
+	}
+	return filepath.Join(filepath.Dir(cpath), c.BannerFilename), true
+}
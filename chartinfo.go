@@ -10,7 +10,7 @@ import (
 // ChartInfo is used at SceneSelect.
 type ChartInfo struct {
 	Path string
-	// Mods    Mods
+	MD5  [16]byte
 	// Header  ChartHeader
 	ChartHeader
 	Mode    int
@@ -29,7 +29,7 @@ func (c ChartInfo) Text() string {
 	switch c.Mode {
 	case ModePiano4, ModePiano7:
 		return fmt.Sprintf("(%dK Level %3.1f) %s [%s]", c.SubMode, c.Level, c.MusicName, c.ChartName)
-	case ModeDrum:
+	case ModeDrum, ModeKaraoke:
 		return fmt.Sprintf("(Level %3.1f) %s [%s]", c.Level, c.MusicName, c.ChartName)
 	}
 	return ""
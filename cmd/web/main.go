@@ -4,6 +4,7 @@ import (
 	"github.com/hajimehoshi/ebiten/v2"
 	"github.com/hndada/gosu"
 	"github.com/hndada/gosu/mode/drum"
+	"github.com/hndada/gosu/mode/karaoke"
 	"github.com/hndada/gosu/mode/piano"
 )
 
@@ -14,7 +15,7 @@ import (
 // var music embed.FS
 
 func main() {
-	g := gosu.NewGame([]gosu.ModeProp{piano.ModePiano4, piano.ModePiano7, drum.ModeDrum}) //, skin, music)
+	g := gosu.NewGame([]gosu.ModeProp{piano.ModePiano4, piano.ModePiano7, drum.ModeDrum, karaoke.ModeKaraoke}) //, skin, music)
 	if err := ebiten.RunGame(g); err != nil {
 		panic(err)
 	}
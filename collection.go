@@ -0,0 +1,175 @@
+package gosu
+
+import (
+	"encoding/hex"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/hndada/gosu/db"
+	"github.com/hndada/gosu/format/osr"
+	"github.com/hndada/gosu/format/osudb"
+)
+
+// Collection is a named list of charts.
+type Collection struct {
+	Name  string
+	Paths []string
+}
+
+var Collections []Collection
+
+func collectionsFilename() string {
+	if db.MarshalType == "json" {
+		return "collections.json"
+	}
+	return "collections.db" // Not to be confused with osu!'s collection.db.
+}
+func LoadCollections() error { return db.LoadData(collectionsFilename(), &Collections) }
+func SaveCollections()       { db.SaveData(collectionsFilename(), &Collections) }
+
+// PutCollection merges charts to the collection with the same name.
+func PutCollection(c Collection) {
+	for i, c2 := range Collections {
+		if c2.Name != c.Name {
+			continue
+		}
+		exists := make(map[string]bool)
+		for _, path := range c2.Paths {
+			exists[path] = true
+		}
+		for _, path := range c.Paths {
+			if !exists[path] {
+				Collections[i].Paths = append(Collections[i].Paths, path)
+			}
+		}
+		return
+	}
+	Collections = append(Collections, c)
+}
+
+type chartIndex struct {
+	mode int // Index of mode props.
+	path string
+	md5  [16]byte
+}
+
+// ImportOsu imports local scores and collections from osu! folder.
+// Charts are matched by MD5. osu!.db complements charts
+// whose MD5 is not known, by folder name and file name.
+// Only the best score of each chart is imported.
+func ImportOsu(osuRoot string, modeProps []ModeProp) error {
+	charts := make(map[string]chartIndex) // Key is hex string of MD5.
+	paths := make(map[string]chartIndex)  // Key is a path relative to music root.
+	for i, prop := range modeProps {
+		for _, info := range prop.ChartInfos {
+			ci := chartIndex{i, info.Path, info.MD5}
+			if info.MD5 != [16]byte{} {
+				charts[hex.EncodeToString(info.MD5[:])] = ci
+			}
+			if rel, err := filepath.Rel(MusicRoot, info.Path); err == nil {
+				paths[strings.ToLower(filepath.ToSlash(rel))] = ci
+			}
+		}
+	}
+
+	// Each file is imported independently: any of them may be missing.
+	var read int
+	if dat, err := os.ReadFile(filepath.Join(osuRoot, "osu!.db")); err == nil {
+		read++
+		odb, err := osudb.ParseOsuDB(dat)
+		if err != nil {
+			fmt.Printf("error at parsing osu!.db: %s\n", err)
+		}
+		for _, b := range odb.Beatmaps {
+			if _, ok := charts[b.MD5]; ok {
+				continue
+			}
+			rel := strings.ToLower(b.FolderName + "/" + b.Filename)
+			if ci, ok := paths[rel]; ok {
+				md5, err := hex.DecodeString(b.MD5)
+				if err != nil || len(md5) != 16 {
+					continue
+				}
+				copy(ci.md5[:], md5)
+				charts[b.MD5] = ci
+			}
+		}
+	}
+
+	if dat, err := os.ReadFile(filepath.Join(osuRoot, "scores.db")); err == nil {
+		read++
+		scores, err := osudb.ParseScores(dat)
+		if err != nil {
+			fmt.Printf("error at parsing scores.db: %s\n", err)
+		}
+		importOsuScores(scores, charts, modeProps)
+	}
+
+	if dat, err := os.ReadFile(filepath.Join(osuRoot, "collection.db")); err == nil {
+		read++
+		cs, err := osudb.ParseCollections(dat)
+		if err != nil {
+			fmt.Printf("error at parsing collection.db: %s\n", err)
+		}
+		for _, c := range cs {
+			c2 := Collection{Name: c.Name, Paths: make([]string, 0, len(c.MD5s))}
+			for _, md5 := range c.MD5s {
+				if ci, ok := charts[md5]; ok {
+					c2.Paths = append(c2.Paths, ci.path)
+				}
+			}
+			PutCollection(c2)
+		}
+		SaveCollections()
+	}
+	if read == 0 {
+		return fmt.Errorf("no osu! database at %s", osuRoot)
+	}
+	return nil
+}
+
+// importOsuScores puts the best score of each chart to results.
+func importOsuScores(scores map[string][]osr.Format, charts map[string]chartIndex, modeProps []ModeProp) {
+	for md5, ss := range scores {
+		ci, ok := charts[md5]
+		if !ok || len(ss) == 0 {
+			continue
+		}
+		best := ss[0]
+		for _, s := range ss[1:] {
+			if s.Score > best.Score {
+				best = s
+			}
+		}
+		prop := modeProps[ci.mode]
+		if prop.Results == nil {
+			modeProps[ci.mode].Results = make(map[[16]byte]Result)
+		}
+		r := newResultFromOsu(prop.Mode, best)
+		r.MD5 = ci.md5
+		if old, ok := prop.Results[ci.md5]; ok && old.Scores[Total] >= r.Scores[Total] {
+			continue
+		}
+		modeProps[ci.mode].Results[ci.md5] = r
+	}
+}
+
+// Judgment counts are converted to gosu's of each mode.
+// Piano: Kool, Cool, Good, Bad, Miss. Drum: Cool, Good, Miss and 4 kinds of partials and ticks.
+func newResultFromOsu(mode int, s osr.Format) Result {
+	r := Result{
+		PlayedTime: s.Time(),
+		MaxCombo:   int(s.Combo),
+	}
+	r.Scores[Total] = float64(s.Score)
+	switch mode {
+	case ModePiano4, ModePiano7:
+		r.JudgmentCounts = []int{int(s.NumGeki), int(s.Num300),
+			int(s.NumKatu), int(s.Num100 + s.Num50), int(s.NumMiss)}
+	case ModeDrum:
+		r.JudgmentCounts = []int{int(s.Num300), int(s.Num100), int(s.NumMiss), 0, 0, 0, 0}
+	}
+	return r
+}
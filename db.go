@@ -34,7 +34,8 @@ func LoadChartInfosSet(modeProps []ModeProp) error {
 func TidyChartInfosSet(modeProps []ModeProp) {
 	for i, prop := range modeProps {
 		for j, info := range prop.ChartInfos {
-			if _, err := os.Stat(info.Path); err != nil {
+			fpath, _ := SplitChartPath(info.Path)
+			if _, err := os.Stat(fpath); err != nil {
 				info1 := prop.ChartInfos[:j]
 				info2 := prop.ChartInfos[j+1:]
 				modeProps[i].ChartInfos = append(info1, info2...)
@@ -78,16 +79,32 @@ func (prop ModeProp) LoadNewChartInfos(musicRoot string) []ChartInfo {
 			if f.IsDir() || !isNew(f) { // There may be directory e.g., SB
 				continue
 			}
-			cpath := filepath.Join(dpath, f.Name())
-			if ChartFileMode(cpath) != prop.Mode {
+			fpath := filepath.Join(dpath, f.Name())
+			fileMode := ChartFileMode(fpath)
+			if fileMode == ModeNone {
 				continue
 			}
-			info, err := prop.NewChartInfo(cpath) // First load should be done with no mods
+			cpaths, err := ChartPaths(fpath)
 			if err != nil {
-				fmt.Printf("error at %s: %s\n", filepath.Base(cpath), err)
+				fmt.Printf("error at %s: %s\n", filepath.Base(fpath), err)
 				continue
 			}
-			chartInfos = PutChartInfo(chartInfos, info)
+			for _, cpath := range cpaths {
+				// Charts in a file may differ in mode, e.g., MIDI by key count.
+				mode := fileMode
+				if cpath != fpath {
+					mode = ChartFileMode(cpath)
+				}
+				if mode != prop.Mode {
+					continue
+				}
+				info, err := prop.NewChartInfo(cpath) // First load should be done with no mods
+				if err != nil {
+					fmt.Printf("error at %s: %s\n", filepath.Base(cpath), err)
+					continue
+				}
+				chartInfos = PutChartInfo(chartInfos, info)
+			}
 		}
 	}
 	prop.LastUpdateTime = time.Now()
@@ -0,0 +1,42 @@
+package gosu
+
+import (
+	"fmt"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/ebitenutil"
+	"github.com/hajimehoshi/ebiten/v2/inpututil"
+)
+
+// SceneDemo shows Auto playing a chart from select screen.
+// Escape goes back to select. Replays are not exported at demo.
+type SceneDemo struct {
+	play   Practicable
+	fields PracticeFields
+}
+
+func NewSceneDemo(scene Scene) (Scene, error) {
+	play, ok := scene.(Practicable)
+	if !ok {
+		return nil, fmt.Errorf("demo not supported: %T", scene)
+	}
+	s := &SceneDemo{
+		play:   play,
+		fields: play.PracticeFields(),
+	}
+	s.fields.KeyLogger.Actions = nil
+	s.fields.Scorer.Gauge.NoFail = true
+	return s, nil
+}
+
+func (s *SceneDemo) Update() any {
+	if inpututil.IsKeyJustPressed(ebiten.KeyEscape) {
+		s.fields.Timer.Quit = true
+	}
+	return s.play.Update()
+}
+
+func (s SceneDemo) Draw(screen *ebiten.Image) {
+	s.play.Draw(screen)
+	ebitenutil.DebugPrintAt(screen, "Demo\nBack to select (Esc)", screenSizeX-160, 0)
+}
@@ -160,10 +160,7 @@ func (r ChimuResult) Download(dir string) error {
 }
 func (r ChimuResult) Filename() string {
 	name := fmt.Sprintf("%d %s - %s.osz", r.SetId, r.Artist, r.Title)
-	for _, letter := range []string{"<", ">", ":", "\"", "/", "\\", "|", "?", "*"} {
-		name = strings.ReplaceAll(name, letter, "-")
-	}
-	return name
+	return sanitizeFilename(name)
 }
 
 func ChartSetList(root string) map[int]bool {
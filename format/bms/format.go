@@ -0,0 +1,155 @@
+package bms
+
+import "strings"
+
+// Format is for BMS family: .bms, .bme and .bml.
+// Unlike osu!, BMS places objects at fractions of measures.
+// Times of all objects are calculated in milliseconds when parsing.
+type Format struct {
+	Header
+	WAVs     map[int]string // Keysound table. Key is an index of #WAVxx.
+	Notes    []Note
+	BGMs     []Note // Keysounds played automatically at channel 01.
+	BPMs     []BPMChange
+	Stops    []Stop
+	Measures []Measure
+}
+
+type Header struct {
+	Player     int
+	Genre      string
+	Title      string
+	SubTitle   string
+	Artist     string
+	SubArtist  string
+	Maker      string
+	BPM        float64 // Initial BPM.
+	PlayLevel  int
+	Rank       int
+	Total      float64
+	Difficulty int
+	StageFile  string
+	Banner     string
+	BackBMP    string
+	Preview    string
+	LNType     int
+	LNObj      int
+}
+
+// Channel is a visible channel of a note: 0x11-0x19 for player 1,
+// 0x21-0x29 for player 2. Long notes at channel 5x and 6x are
+// also stored with their visible channel.
+type Note struct {
+	Time     float64
+	Channel  int
+	Duration float64 // Non-zero for long notes.
+	WAV      int
+}
+
+type BPMChange struct {
+	Time float64
+	BPM  float64
+}
+
+// Scroll stops while time goes Duration.
+type Stop struct {
+	Time     float64
+	Duration float64
+}
+
+// Length is a ratio to standard 4/4 measure.
+type Measure struct {
+	Time   float64
+	Length float64
+}
+
+// Charter returns #MAKER, or the part of #SUBARTIST tagged with "obj",
+// e.g., "obj: Someone" at "music: Other / obj: Someone".
+func (h Header) Charter() string {
+	if h.Maker != "" {
+		return h.Maker
+	}
+	for _, part := range strings.Split(h.SubArtist, "/") {
+		part = strings.TrimSpace(part)
+		if len(part) > 3 && strings.EqualFold(part[:3], "obj") {
+			return strings.TrimSpace(strings.TrimLeft(part[3:], ".:"))
+		}
+	}
+	return ""
+}
+
+// Difficulties are names of #DIFFICULTY values.
+var Difficulties = []string{"", "Beginner", "Normal", "Hyper", "Another", "Insane"}
+
+// Channels for player 1 in column order of 7-key. Scratch is excluded.
+// Channels for player 2 are those plus 0x10.
+var keyChannels = []int{0x11, 0x12, 0x13, 0x14, 0x15, 0x18, 0x19}
+
+const (
+	scratchChannel = 0x16
+	pedalChannel   = 0x17 // Free zone at 5-key; not supported.
+	player2Offset  = 0x10
+)
+
+// Keys returns the number of keys except scratch, and whether scratch is used.
+// Chart is regarded as 5-key when channel 18 and 19 are not used.
+// Keys of both sides are counted at double play, hence 10 or 14.
+func (f Format) Keys() (keys int, scratch bool) {
+	keys = 5
+	var double bool
+	for _, n := range f.Notes {
+		ch := n.Channel
+		if ch > 0x20 {
+			double = true
+			ch -= player2Offset
+		}
+		switch ch {
+		case 0x18, 0x19:
+			keys = 7
+		case scratchChannel:
+			scratch = true
+		}
+	}
+	if double { // Layout of double play always has scratch lanes.
+		keys *= 2
+		scratch = true
+	}
+	return
+}
+
+// Column returns an index of column of the channel.
+// Scratch goes to the leftmost column when the chart uses scratch.
+// At double play, keys more than 7, player 2 goes to the right of
+// player 1 and the scratch of player 2 goes to the rightmost column.
+// It returns -1 when the channel is not a column of the key mode.
+func Column(channel, keys int, scratch bool) int {
+	side := keys
+	if keys > 7 {
+		side = keys / 2
+		if channel > 0x20 {
+			if scratch && channel == scratchChannel+player2Offset {
+				return 2*side + 1
+			}
+			if col := Column(channel-player2Offset, side, scratch); col >= 0 {
+				return col + side
+			}
+			return -1
+		}
+	}
+	var offset int
+	if scratch {
+		if channel == scratchChannel {
+			return 0
+		}
+		offset = 1
+	}
+	for i, ch := range keyChannels {
+		if i >= side {
+			break
+		}
+		if ch == channel {
+			return i + offset
+		}
+	}
+	return -1
+}
@@ -0,0 +1,430 @@
+package bms
+
+import (
+	"bytes"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Todo: support Shift-JIS encoded texts
+func Parse(dat []byte) (*Format, error) {
+	f := &Format{
+		Header: Header{
+			Player: 1,
+			BPM:    130,
+			LNType: 1,
+		},
+		WAVs:     make(map[int]string),
+		Notes:    make([]Note, 0),
+		BGMs:     make([]Note, 0),
+		BPMs:     make([]BPMChange, 0),
+		Stops:    make([]Stop, 0),
+		Measures: make([]Measure, 0),
+	}
+	var (
+		exBPMs  = make(map[int]float64)
+		stops   = make(map[int]float64)
+		lengths = make(map[int]float64)
+		objects = make([]object, 0)
+		branch  brancher
+	)
+	dat = bytes.ReplaceAll(dat, []byte("\r\n"), []byte("\n"))
+	for _, l := range bytes.Split(dat, []byte("\n")) {
+		line := strings.TrimSpace(string(l))
+		if len(line) < 2 || line[0] != '#' {
+			continue
+		}
+		line = line[1:]
+		if branch.update(line) || branch.skipping() {
+			continue
+		}
+		if isChannelLine(line) {
+			measure, _ := strconv.Atoi(line[:3])
+			data := strings.TrimSpace(line[6:])
+			channel, err := strconv.ParseInt(line[3:5], 16, 0)
+			if err != nil { // Unknown channel such as BGA layers with extended names.
+				continue
+			}
+			if channel == 0x02 {
+				v, err := strconv.ParseFloat(data, 64)
+				if err != nil {
+					return f, fmt.Errorf("error at %s: %s", line, err)
+				}
+				lengths[measure] = v
+				continue
+			}
+			if len(data)%2 != 0 {
+				return f, fmt.Errorf("error at %s: odd length of data", line)
+			}
+			count := len(data) / 2
+			for i := 0; i < count; i++ {
+				s := data[2*i : 2*i+2]
+				if s == "00" {
+					continue
+				}
+				base := 36
+				if channel == 0x03 { // BPM in hexadecimal.
+					base = 16
+				}
+				v, err := strconv.ParseInt(s, base, 0)
+				if err != nil {
+					return f, fmt.Errorf("error at %s: %s", line, err)
+				}
+				objects = append(objects, object{
+					measure: measure,
+					pos:     float64(i) / float64(count),
+					size:    1 / float64(count),
+					channel: int(channel),
+					value:   int(v),
+				})
+			}
+			continue
+		}
+
+		kv := strings.SplitN(line, " ", 2)
+		key := strings.ToUpper(kv[0])
+		var value string
+		if len(kv) == 2 {
+			value = strings.TrimSpace(kv[1])
+		}
+		switch key {
+		case "PLAYER":
+			f.Player, _ = strconv.Atoi(value)
+		case "GENRE":
+			f.Genre = value
+		case "TITLE":
+			f.Title = value
+		case "SUBTITLE":
+			f.SubTitle = value
+		case "ARTIST":
+			f.Artist = value
+		case "SUBARTIST":
+			f.SubArtist = value
+		case "MAKER":
+			f.Maker = value
+		case "BPM":
+			v, err := strconv.ParseFloat(value, 64)
+			if err != nil {
+				return f, fmt.Errorf("error at %s: %s", line, err)
+			}
+			if v > 0 { // Non-positive BPM is not supported, as at #EXBPM.
+				f.BPM = v
+			}
+		case "PLAYLEVEL":
+			f.PlayLevel, _ = strconv.Atoi(value)
+		case "RANK":
+			f.Rank, _ = strconv.Atoi(value)
+		case "TOTAL":
+			f.Total, _ = strconv.ParseFloat(value, 64)
+		case "DIFFICULTY":
+			f.Difficulty, _ = strconv.Atoi(value)
+		case "STAGEFILE":
+			f.StageFile = value
+		case "BANNER":
+			f.Banner = value
+		case "BACKBMP":
+			f.BackBMP = value
+		case "PREVIEW":
+			f.Preview = value
+		case "LNTYPE":
+			f.LNType, _ = strconv.Atoi(value)
+		case "LNOBJ":
+			v, err := strconv.ParseInt(value, 36, 0)
+			if err != nil {
+				return f, fmt.Errorf("error at %s: %s", line, err)
+			}
+			f.LNObj = int(v)
+		default:
+			for _, prefix := range []string{"WAV", "EXBPM", "BPM", "STOP"} {
+				if !strings.HasPrefix(key, prefix) || len(key) != len(prefix)+2 {
+					continue
+				}
+				index, err := strconv.ParseInt(key[len(prefix):], 36, 0)
+				if err != nil {
+					break
+				}
+				switch prefix {
+				case "WAV":
+					f.WAVs[int(index)] = value
+				case "EXBPM", "BPM":
+					v, err := strconv.ParseFloat(value, 64)
+					if err != nil {
+						return f, fmt.Errorf("error at %s: %s", line, err)
+					}
+					exBPMs[int(index)] = v
+				case "STOP":
+					v, err := strconv.ParseFloat(value, 64)
+					if err != nil {
+						return f, fmt.Errorf("error at %s: %s", line, err)
+					}
+					stops[int(index)] = v
+				}
+				break
+			}
+		}
+	}
+	f.setTimes(objects, lengths, exBPMs, stops)
+	return f, nil
+}
+
+// Format of channel line is #mmmcc:data.
+func isChannelLine(line string) bool {
+	if len(line) < 6 || line[5] != ':' {
+		return false
+	}
+	for _, c := range line[:3] {
+		if c < '0' || c > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+type object struct {
+	measure int
+	pos     float64 // Position in a measure: [0, 1).
+	size    float64 // Length of the cell in a measure.
+	channel int
+	value   int
+}
+
+// Events at the same beat are processed in the order of kinds:
+// BPM change goes first, then objects. Stop goes last.
+// End of long note cell goes before objects so that the next cell continues it.
+const (
+	kindBPM = iota
+	kindLongEnd
+	kindObject
+	kindStop
+)
+
+type event struct {
+	beat float64
+	kind int
+	object
+}
+
+// setTimes calculates times of all objects.
+// 4 beats make a standard measure.
+func (f *Format) setTimes(objects []object, lengths, exBPMs, stops map[int]float64) {
+	var measureCount int
+	for _, o := range objects {
+		if measureCount < o.measure+1 {
+			measureCount = o.measure + 1
+		}
+	}
+	length := func(m int) float64 {
+		if v, ok := lengths[m]; ok && v > 0 {
+			return v
+		}
+		return 1
+	}
+	starts := make([]float64, measureCount+1) // Beats at the start of each measure.
+	for m := 0; m < measureCount; m++ {
+		starts[m+1] = starts[m] + 4*length(m)
+	}
+
+	events := make([]event, 0, len(objects)+measureCount)
+	for m := 0; m < measureCount; m++ {
+		o := object{measure: m, channel: 0x02}
+		events = append(events, event{starts[m], kindObject, o})
+	}
+	for _, o := range objects {
+		e := event{
+			beat:   starts[o.measure] + 4*length(o.measure)*o.pos,
+			kind:   kindObject,
+			object: o,
+		}
+		switch o.channel {
+		case 0x03, 0x08:
+			e.kind = kindBPM
+		case 0x09:
+			e.kind = kindStop
+		}
+		events = append(events, e)
+		if f.LNType == 2 && isLong(o.channel) {
+			end := e
+			end.beat = starts[o.measure] + 4*length(o.measure)*(o.pos+o.size)
+			end.kind = kindLongEnd
+			events = append(events, end)
+		}
+	}
+	sort.SliceStable(events, func(i, j int) bool {
+		if events[i].beat == events[j].beat {
+			return events[i].kind < events[j].kind
+		}
+		return events[i].beat < events[j].beat
+	})
+
+	var (
+		bpm      = f.BPM
+		beat     float64
+		time     float64
+		lastNote = make(map[int]int)    // Index of the last note for each channel. For #LNOBJ.
+		lnHeads  = make(map[int]object) // Pending long note heads for each channel.
+		lnTimes  = make(map[int]float64)
+		lnEnds   = make(map[int][2]float64) // Beat and time at the end of the last cell. For LNTYPE 2.
+	)
+	addLong := func(ch int, end float64) {
+		f.Notes = append(f.Notes, Note{
+			Time:     lnTimes[ch],
+			Channel:  ch,
+			Duration: end - lnTimes[ch],
+			WAV:      lnHeads[ch].value,
+		})
+		delete(lnHeads, ch)
+	}
+	for _, e := range events {
+		time += (e.beat - beat) * 60000 / bpm
+		beat = e.beat
+		switch e.kind {
+		case kindBPM:
+			v := float64(e.value)
+			if e.channel == 0x08 {
+				v = exBPMs[e.value]
+			}
+			if v <= 0 { // Negative BPM is not supported.
+				continue
+			}
+			bpm = v
+			f.BPMs = append(f.BPMs, BPMChange{Time: time, BPM: bpm})
+		case kindStop:
+			// Duration of stop is in 1/192 of a standard measure.
+			d := stops[e.value] / 48 * 60000 / bpm
+			if d <= 0 {
+				continue
+			}
+			f.Stops = append(f.Stops, Stop{Time: time, Duration: d})
+			time += d
+		case kindLongEnd:
+			lnEnds[e.channel-0x40] = [2]float64{e.beat, time}
+		case kindObject:
+			ch := e.channel
+			switch {
+			case ch == 0x02:
+				f.Measures = append(f.Measures, Measure{Time: time, Length: length(e.measure)})
+			case ch == 0x01:
+				f.BGMs = append(f.BGMs, Note{Time: time, Channel: ch, WAV: e.value})
+			case isVisible(ch):
+				if f.LNObj != 0 && e.value == f.LNObj {
+					if i, ok := lastNote[ch]; ok && f.Notes[i].Duration == 0 {
+						f.Notes[i].Duration = time - f.Notes[i].Time
+					}
+					continue
+				}
+				lastNote[ch] = len(f.Notes)
+				f.Notes = append(f.Notes, Note{Time: time, Channel: ch, WAV: e.value})
+			case isLong(ch) && f.LNType == 2:
+				// Consecutive cells make a long note.
+				ch -= 0x40
+				if _, ok := lnHeads[ch]; ok {
+					if end := lnEnds[ch]; math.Abs(end[0]-e.beat) < 1e-9 {
+						continue
+					}
+					addLong(ch, lnEnds[ch][1])
+				}
+				lnHeads[ch] = e.object
+				lnTimes[ch] = time
+			case isLong(ch): // A pair of objects makes a long note.
+				ch -= 0x40
+				if _, ok := lnHeads[ch]; !ok {
+					lnHeads[ch] = e.object
+					lnTimes[ch] = time
+					continue
+				}
+				addLong(ch, time)
+			}
+		}
+	}
+	if f.LNType == 2 { // Long notes at the end of chart.
+		chs := make([]int, 0, len(lnHeads))
+		for ch := range lnHeads {
+			chs = append(chs, ch)
+		}
+		sort.Ints(chs)
+		for _, ch := range chs {
+			addLong(ch, lnEnds[ch][1])
+		}
+	}
+	sort.SliceStable(f.Notes, func(i, j int) bool {
+		return f.Notes[i].Time < f.Notes[j].Time
+	})
+}
+
+func isVisible(ch int) bool {
+	return ch >= 0x11 && ch <= 0x19 || ch >= 0x21 && ch <= 0x29
+}
+func isLong(ch int) bool {
+	return ch >= 0x51 && ch <= 0x59 || ch >= 0x61 && ch <= 0x69
+}
+
+// brancher handles control flow: #RANDOM, #IF, #ELSEIF, #ELSE and #ENDIF.
+// Random value of #RANDOM is fixed to 1 so that a chart always goes the same.
+// #SETRANDOM sets the value as it is.
+type brancher struct {
+	random int
+	conds  []bool // Whether current block is active.
+	taken  []bool // Whether any block in current #IF has been active.
+}
+
+// update returns true when the line is a control flow command.
+func (b *brancher) update(line string) bool {
+	kv := strings.Fields(line)
+	if len(kv) == 0 {
+		return false
+	}
+	key := strings.ToUpper(kv[0])
+	var value int
+	if len(kv) >= 2 {
+		value, _ = strconv.Atoi(kv[1])
+	}
+	switch key {
+	case "RANDOM":
+		if value > 0 {
+			b.random = 1
+		}
+	case "SETRANDOM":
+		b.random = value
+	case "IF":
+		cond := value == b.random
+		b.conds = append(b.conds, cond)
+		b.taken = append(b.taken, cond)
+	case "ELSEIF":
+		if len(b.conds) == 0 {
+			return true
+		}
+		i := len(b.conds) - 1
+		b.conds[i] = !b.taken[i] && value == b.random
+		b.taken[i] = b.taken[i] || b.conds[i]
+	case "ELSE":
+		if len(b.conds) == 0 {
+			return true
+		}
+		i := len(b.conds) - 1
+		b.conds[i] = !b.taken[i]
+		b.taken[i] = true
+	case "ENDIF", "END":
+		if key == "END" && (len(kv) < 2 || strings.ToUpper(kv[1]) != "IF") {
+			return false
+		}
+		if len(b.conds) > 0 {
+			b.conds = b.conds[:len(b.conds)-1]
+			b.taken = b.taken[:len(b.taken)-1]
+		}
+	case "ENDRANDOM":
+	default:
+		return false
+	}
+	return true
+}
+func (b brancher) skipping() bool {
+	for _, cond := range b.conds {
+		if !cond {
+			return true
+		}
+	}
+	return false
+}
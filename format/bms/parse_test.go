@@ -0,0 +1,91 @@
+package bms
+
+import (
+	"math"
+	"reflect"
+	"testing"
+)
+
+// At 120 BPM, a measure lasts 2000ms.
+func TestParseLongNotes(t *testing.T) {
+	for _, tc := range []struct {
+		name string
+		dat  string
+		want []Note
+	}{
+		{"LNTYPE 1", "#BPM 120\n#LNTYPE 1\n#00151:01000100\n#00251:0100\n#00351:01\n", []Note{
+			{Time: 2000, Channel: 0x11, Duration: 1000, WAV: 1},
+			{Time: 4000, Channel: 0x11, Duration: 2000, WAV: 1},
+		}},
+		{"LNTYPE 2", "#BPM 120\n#LNTYPE 2\n#00151:00020202\n#00251:0200\n#00252:01\n#00351:03000003\n", []Note{
+			{Time: 2500, Channel: 0x11, Duration: 2500, WAV: 2},
+			{Time: 4000, Channel: 0x12, Duration: 2000, WAV: 1},
+			{Time: 6000, Channel: 0x11, Duration: 500, WAV: 3},
+			{Time: 7500, Channel: 0x11, Duration: 500, WAV: 3},
+		}},
+		{"LNOBJ", "#BPM 120\n#LNOBJ ZZ\n#00111:0100ZZ00\n", []Note{
+			{Time: 2000, Channel: 0x11, Duration: 1000, WAV: 1},
+		}},
+	} {
+		f, err := Parse([]byte(tc.dat))
+		if err != nil {
+			t.Fatalf("%s: %s", tc.name, err)
+		}
+		if !reflect.DeepEqual(f.Notes, tc.want) {
+			t.Errorf("%s:\n got %+v\nwant %+v", tc.name, f.Notes, tc.want)
+		}
+	}
+}
+
+// Each block has a note at its own channel.
+func TestParseRandom(t *testing.T) {
+	const blocks = "#IF 1\n#00111:01\n#ELSEIF 2\n#00112:01\n#ELSE\n#00113:01\n#ENDIF\n"
+	for _, tc := range []struct {
+		name   string
+		random string
+		want   int
+	}{
+		{"RANDOM", "#RANDOM 3\n", 0x11},
+		{"SETRANDOM", "#SETRANDOM 2\n", 0x12},
+		{"SETRANDOM to else", "#SETRANDOM 3\n", 0x13},
+	} {
+		f, err := Parse([]byte("#BPM 120\n" + tc.random + blocks + "#ENDRANDOM\n"))
+		if err != nil {
+			t.Fatalf("%s: %s", tc.name, err)
+		}
+		if len(f.Notes) != 1 || f.Notes[0].Channel != tc.want {
+			t.Errorf("%s: notes %+v, want at channel %x", tc.name, f.Notes, tc.want)
+		}
+	}
+}
+
+func TestParseBPM(t *testing.T) {
+	f, err := Parse([]byte("#BPM 0\n#EXBPM01 0\n#BPM02 240\n#00111:01\n#00108:0102\n#00211:01\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if f.BPM != 130 {
+		t.Errorf("initial BPM: %v, want the default", f.BPM)
+	}
+	// #EXBPM01 0 at beat 4 is skipped.
+	bpmTime := 60000.0 * 6 / 130
+	if len(f.BPMs) != 1 || f.BPMs[0].BPM != 240 || math.Abs(f.BPMs[0].Time-bpmTime) > 1e-6 {
+		t.Errorf("BPMs: %+v", f.BPMs)
+	}
+	if got := f.Notes[1].Time; math.Abs(got-(bpmTime+500)) > 1e-6 {
+		t.Errorf("note time: %v, want %v", got, bpmTime+500)
+	}
+}
+
+func TestCharter(t *testing.T) {
+	for h, want := range map[Header]string{
+		{SubArtist: "obj: Someone"}:                 "Someone",
+		{SubArtist: "music:Other / obj.Someone"}:    "Someone",
+		{SubArtist: "Other"}:                        "",
+		{SubArtist: "obj: Someone", Maker: "Maker"}: "Maker",
+	} {
+		if got := h.Charter(); got != want {
+			t.Errorf("%+v: %q, want %q", h, got, want)
+		}
+	}
+}
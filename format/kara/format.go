@@ -0,0 +1,62 @@
+package kara
+
+// Format is for karaoke timing file (.kara), which is written in the way
+// of osu! file: sections of "key: value" pairs and comma-separated records.
+// Times are in milliseconds.
+//
+//	gosu karaoke file format v1
+//
+//	[General]
+//	AudioFilename: audio.mp3
+//
+//	[TimingPoints]
+//	// time,beatLength,meter
+//	1000,500,4
+//
+//	[Syllables]
+//	// time,duration,line,text
+//	1000,250,0,Hel
+//	1250,250,0,lo
+type Format struct {
+	FormatVersion int
+	General
+	Metadata
+	TimingPoints []TimingPoint
+	Syllables    []Syllable // Sorted by time.
+}
+
+type General struct {
+	AudioFilename      string
+	BackgroundFilename string
+	PreviewTime        int
+}
+
+type Metadata struct {
+	Title         string
+	TitleUnicode  string
+	Artist        string
+	ArtistUnicode string
+	Creator       string
+	Version       string
+	Source        string
+}
+
+// TimingPoint is the same as uninherited timing point of osu!.
+type TimingPoint struct {
+	Time       float64
+	BeatLength float64
+	Meter      int
+}
+
+func (tp TimingPoint) BPM() float64 { return 60000 / tp.BeatLength }
+
+// Syllable is a part of lyrics sung at a time.
+// Syllables with the same Line are shown together.
+// Text is the rest of a record: it may have commas, and keeps
+// trailing spaces for separating words.
+type Syllable struct {
+	Time     int
+	Duration int
+	Line     int
+	Text     string
+}
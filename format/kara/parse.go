@@ -0,0 +1,146 @@
+package kara
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+const (
+	sectionNone = iota
+	sectionGeneral
+	sectionMetadata
+	sectionTimingPoints
+	sectionSyllables
+)
+
+func Parse(dat []byte) (*Format, error) {
+	f := &Format{}
+	var (
+		section int
+		no      int // Line number.
+	)
+	dat = bytes.TrimPrefix(dat, []byte("\xef\xbb\xbf")) // UTF-8 BOM.
+	dat = bytes.ReplaceAll(dat, []byte("\r\n"), []byte("\n"))
+	for _, l := range bytes.Split(dat, []byte("\n")) {
+		no++
+		raw := string(l)
+		line := strings.TrimSpace(raw)
+		if line == "" || strings.HasPrefix(line, "//") {
+			continue
+		}
+		if strings.HasPrefix(line, "gosu karaoke file format v") {
+			f.FormatVersion, _ = strconv.Atoi(line[len("gosu karaoke file format v"):])
+			continue
+		}
+		if strings.HasPrefix(line, "[") && strings.HasSuffix(line, "]") {
+			switch line[1 : len(line)-1] {
+			case "General":
+				section = sectionGeneral
+			case "Metadata":
+				section = sectionMetadata
+			case "TimingPoints":
+				section = sectionTimingPoints
+			case "Syllables":
+				section = sectionSyllables
+			default:
+				section = sectionNone
+			}
+			continue
+		}
+		var err error
+		switch section {
+		case sectionGeneral, sectionMetadata:
+			kv := strings.SplitN(line, ":", 2)
+			if len(kv) < 2 {
+				continue
+			}
+			err = f.setValue(strings.TrimSpace(kv[0]), strings.TrimSpace(kv[1]))
+		case sectionTimingPoints:
+			var tp TimingPoint
+			tp, err = parseTimingPoint(line)
+			f.TimingPoints = append(f.TimingPoints, tp)
+		case sectionSyllables:
+			var s Syllable
+			s, err = parseSyllable(strings.TrimLeft(raw, " \t"))
+			f.Syllables = append(f.Syllables, s)
+		}
+		if err != nil {
+			return f, fmt.Errorf("error at line %d: %s", no, err)
+		}
+	}
+	sort.SliceStable(f.TimingPoints, func(i, j int) bool {
+		return f.TimingPoints[i].Time < f.TimingPoints[j].Time
+	})
+	sort.SliceStable(f.Syllables, func(i, j int) bool {
+		return f.Syllables[i].Time < f.Syllables[j].Time
+	})
+	return f, nil
+}
+
+func (f *Format) setValue(k, v string) (err error) {
+	switch k {
+	case "AudioFilename":
+		f.AudioFilename = v
+	case "BackgroundFilename":
+		f.BackgroundFilename = v
+	case "PreviewTime":
+		f.PreviewTime, err = strconv.Atoi(v)
+	case "Title":
+		f.Title = v
+	case "TitleUnicode":
+		f.TitleUnicode = v
+	case "Artist":
+		f.Artist = v
+	case "ArtistUnicode":
+		f.ArtistUnicode = v
+	case "Creator":
+		f.Creator = v
+	case "Version":
+		f.Version = v
+	case "Source":
+		f.Source = v
+	}
+	return
+}
+
+func parseTimingPoint(line string) (tp TimingPoint, err error) {
+	vs := strings.Split(line, ",")
+	if len(vs) < 2 {
+		return tp, fmt.Errorf("invalid timing point: %s", line)
+	}
+	if tp.Time, err = strconv.ParseFloat(vs[0], 64); err != nil {
+		return
+	}
+	if tp.BeatLength, err = strconv.ParseFloat(vs[1], 64); err != nil {
+		return
+	}
+	if tp.BeatLength <= 0 {
+		return tp, fmt.Errorf("invalid beat length: %s", vs[1])
+	}
+	tp.Meter = 4
+	if len(vs) >= 3 {
+		tp.Meter, err = strconv.Atoi(vs[2])
+	}
+	return
+}
+
+func parseSyllable(line string) (s Syllable, err error) {
+	vs := strings.SplitN(line, ",", 4)
+	if len(vs) < 4 {
+		return s, fmt.Errorf("invalid syllable: %s", line)
+	}
+	if s.Time, err = strconv.Atoi(vs[0]); err != nil {
+		return
+	}
+	if s.Duration, err = strconv.Atoi(vs[1]); err != nil {
+		return
+	}
+	if s.Line, err = strconv.Atoi(vs[2]); err != nil {
+		return
+	}
+	s.Text = vs[3]
+	return
+}
@@ -0,0 +1,51 @@
+package kara
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	const dat = "\ufeffgosu karaoke file format v1\r\n\r\n" +
+		"[General]\r\nAudioFilename: audio.mp3\r\nPreviewTime: 1500\r\n\r\n" +
+		"[Metadata]\r\nTitle: Song\r\nArtist: Artist\r\nVersion: Easy\r\n\r\n" +
+		"[TimingPoints]\r\n// time,beatLength,meter\r\n2000,250,3\r\n1000,500\r\n\r\n" +
+		"[Syllables]\r\n// time,duration,line,text\r\n" +
+		"1250,250,0,lo, \r\n1000,250,0,Hel\r\n2000,500,1,world\r\n"
+	f, err := Parse([]byte(dat))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if f.FormatVersion != 1 || f.AudioFilename != "audio.mp3" || f.PreviewTime != 1500 {
+		t.Errorf("general: %d %+v", f.FormatVersion, f.General)
+	}
+	if f.Title != "Song" || f.Artist != "Artist" || f.Version != "Easy" {
+		t.Errorf("metadata: %+v", f.Metadata)
+	}
+	wantTPs := []TimingPoint{{1000, 500, 4}, {2000, 250, 3}}
+	if !reflect.DeepEqual(f.TimingPoints, wantTPs) {
+		t.Errorf("timing points:\n got %+v\nwant %+v", f.TimingPoints, wantTPs)
+	}
+	wantSyllables := []Syllable{
+		{1000, 250, 0, "Hel"},
+		{1250, 250, 0, "lo, "}, // Commas and trailing spaces are kept.
+		{2000, 500, 1, "world"},
+	}
+	if !reflect.DeepEqual(f.Syllables, wantSyllables) {
+		t.Errorf("syllables:\n got %+v\nwant %+v", f.Syllables, wantSyllables)
+	}
+}
+
+func TestParseInvalid(t *testing.T) {
+	for _, dat := range []string{
+		"[TimingPoints]\n1000\n",
+		"[TimingPoints]\n1000,0,4\n",
+		"[Syllables]\n1000,250,Hel\n",
+		"[Syllables]\n1000,x,0,Hel\n",
+		"[General]\nPreviewTime: x\n",
+	} {
+		if _, err := Parse([]byte(dat)); err == nil {
+			t.Errorf("Parse(%q): no error", dat)
+		}
+	}
+}
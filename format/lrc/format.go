@@ -0,0 +1,38 @@
+package lrc
+
+// Format is for LRC lyrics file (.lrc).
+// Enhanced LRC, which tags words with times like <mm:ss.xx>, is supported.
+// Times are in milliseconds with Offset applied.
+type Format struct {
+	Title   string // [ti:]
+	Artist  string // [ar:]
+	Album   string // [al:]
+	Author  string // [au:] Songwriter.
+	Creator string // [by:] Creator of the LRC file.
+	Length  int64  // [length:] Length of the song.
+	Offset  int64  // [offset:] Positive value shows lyrics earlier.
+	Lines   []Line // Sorted by time.
+}
+
+// Line without word tags has a single word.
+// Duration of a line lasts until the next line or a blank line.
+// Duration of the last line is 0 unless its end is given.
+type Line struct {
+	Time     int64
+	Duration int64
+	Words    []Word
+}
+
+type Word struct {
+	Time     int64
+	Duration int64
+	Text     string
+}
+
+func (l Line) Text() string {
+	var s string
+	for _, w := range l.Words {
+		s += w.Text
+	}
+	return s
+}
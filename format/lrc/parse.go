@@ -0,0 +1,182 @@
+package lrc
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// line is a line of the file with each of its time tags.
+// End is the time of trailing word tag. -1 means no end is given.
+type line struct {
+	time  int64
+	words []Word
+	end   int64
+}
+
+func Parse(dat []byte) (*Format, error) {
+	f := &Format{}
+	var (
+		ls []line
+		no int // Line number.
+	)
+	dat = bytes.TrimPrefix(dat, []byte("\xef\xbb\xbf")) // UTF-8 BOM.
+	dat = bytes.ReplaceAll(dat, []byte("\r\n"), []byte("\n"))
+	for _, l := range bytes.Split(dat, []byte("\n")) {
+		no++
+		s := strings.TrimSpace(string(l))
+		var times []int64
+		for strings.HasPrefix(s, "[") {
+			end := strings.Index(s, "]")
+			if end == -1 {
+				break
+			}
+			tag := s[1:end]
+			s = s[end+1:]
+			if !isTimeTag(tag) {
+				f.setTag(tag)
+				continue
+			}
+			t, err := parseTime(tag)
+			if err != nil {
+				return f, fmt.Errorf("error at line %d: %s", no, err)
+			}
+			times = append(times, t)
+		}
+		if len(times) == 0 {
+			continue
+		}
+		words, end, err := parseWords(s)
+		if err != nil {
+			return f, fmt.Errorf("error at line %d: %s", no, err)
+		}
+		for _, t := range times { // A line may be repeated, e.g., chorus.
+			l := line{time: t, end: end}
+			for _, w := range words {
+				if w.Time < 0 { // Text before the first word tag.
+					w.Time = t
+				}
+				l.words = append(l.words, w)
+			}
+			ls = append(ls, l)
+		}
+	}
+	sort.SliceStable(ls, func(i, j int) bool { return ls[i].time < ls[j].time })
+	for i, l := range ls {
+		if len(l.words) == 0 { // Blank line ends the previous line.
+			continue
+		}
+		end := l.end
+		if end < 0 {
+			switch {
+			case i < len(ls)-1:
+				end = ls[i+1].time
+			case f.Length > l.time:
+				end = f.Length
+			default:
+				end = l.words[len(l.words)-1].Time
+			}
+		}
+		for j := range l.words {
+			next := end
+			if j < len(l.words)-1 {
+				next = l.words[j+1].Time
+			}
+			l.words[j].Duration = next - l.words[j].Time
+			if l.words[j].Duration < 0 {
+				l.words[j].Duration = 0
+			}
+			l.words[j].Time -= f.Offset
+		}
+		f.Lines = append(f.Lines, Line{
+			Time:     l.time - f.Offset,
+			Duration: end - l.time,
+			Words:    l.words,
+		})
+	}
+	return f, nil
+}
+
+func (f *Format) setTag(tag string) {
+	kv := strings.SplitN(tag, ":", 2)
+	if len(kv) < 2 {
+		return
+	}
+	v := strings.TrimSpace(kv[1])
+	switch strings.ToLower(strings.TrimSpace(kv[0])) {
+	case "ti":
+		f.Title = v
+	case "ar":
+		f.Artist = v
+	case "al":
+		f.Album = v
+	case "au":
+		f.Author = v
+	case "by":
+		f.Creator = v
+	case "length":
+		f.Length, _ = parseTime(v)
+	case "offset":
+		f.Offset, _ = strconv.ParseInt(v, 10, 64)
+	}
+}
+
+// parseWords parses text with word tags. Word of text before the first
+// word tag has negative time. Trailing word tag is the end of the line.
+// Text between consecutive word tags is dropped when it is blank.
+func parseWords(s string) (words []Word, end int64, err error) {
+	end = -1
+	w := Word{Time: -1}
+	for {
+		start := strings.Index(s, "<")
+		stop := strings.Index(s, ">")
+		if start == -1 || stop < start || !isTimeTag(s[start+1:stop]) {
+			w.Text += s
+			break
+		}
+		w.Text += s[:start]
+		if strings.TrimSpace(w.Text) != "" {
+			words = append(words, w)
+		} else if len(words) > 0 { // Spaces go to the previous word.
+			words[len(words)-1].Text += w.Text
+		}
+		t, err := parseTime(s[start+1 : stop])
+		if err != nil {
+			return nil, -1, err
+		}
+		w = Word{Time: t}
+		s = s[stop+1:]
+	}
+	if strings.TrimSpace(w.Text) != "" {
+		words = append(words, w)
+	} else if w.Time >= 0 {
+		end = w.Time
+	}
+	return
+}
+
+func isTimeTag(tag string) bool {
+	return len(tag) > 0 && tag[0] >= '0' && tag[0] <= '9'
+}
+
+// parseTime parses "mm:ss.xx" into milliseconds.
+// Fraction may have any digits, and may follow a colon instead of a dot.
+func parseTime(s string) (int64, error) {
+	s = strings.TrimSpace(s)
+	vs := strings.SplitN(s, ":", 2)
+	if len(vs) < 2 {
+		return 0, fmt.Errorf("invalid time: %s", s)
+	}
+	m, err := strconv.ParseInt(vs[0], 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid time: %s", s)
+	}
+	sec := strings.Replace(vs[1], ":", ".", 1)
+	v, err := strconv.ParseFloat(sec, 64)
+	if err != nil || v < 0 {
+		return 0, fmt.Errorf("invalid time: %s", s)
+	}
+	return m*60000 + int64(v*1000+0.5), nil
+}
@@ -0,0 +1,41 @@
+package lrc
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	const dat = "[ti:Song]\r\n[ar:Artist]\r\n[offset:100]\r\n" +
+		"[00:01.00]<00:01.00>Hel<00:01.50>lo <00:02.00>world<00:03.00>\r\n" +
+		"[00:04.00][00:10.00]La la\r\n" +
+		"[00:06.00]\r\n"
+	f, err := Parse([]byte(dat))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if f.Title != "Song" || f.Artist != "Artist" || f.Offset != 100 {
+		t.Errorf("tags: %+v", f)
+	}
+	want := []Line{
+		{900, 2000, []Word{{900, 500, "Hel"}, {1400, 500, "lo "}, {1900, 1000, "world"}}},
+		{3900, 2000, []Word{{3900, 2000, "La la"}}},
+		{9900, 0, []Word{{9900, 0, "La la"}}},
+	}
+	if !reflect.DeepEqual(f.Lines, want) {
+		t.Errorf("lines:\n got %+v\nwant %+v", f.Lines, want)
+	}
+}
+
+func TestParseTime(t *testing.T) {
+	for s, want := range map[string]int64{
+		"01:02.34":  62340,
+		"01:02.345": 62345,
+		"01:02":     62000,
+		"01:02:50":  62500,
+	} {
+		if got, err := parseTime(s); err != nil || got != want {
+			t.Errorf("parseTime(%q) = %d, %v; want %d", s, got, err, want)
+		}
+	}
+}
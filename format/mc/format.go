@@ -0,0 +1,149 @@
+package mc
+
+import (
+	"encoding/json"
+	"os"
+	"sort"
+)
+
+// Format is for Malody's .mc file, which is written in JSON.
+// Positions of all objects are written in beats.
+type Format struct {
+	Meta   Meta     `json:"meta"`
+	Time   []BPM    `json:"time"`
+	Effect []Effect `json:"effect"`
+	Note   []Note   `json:"note"`
+	offset float64  // Music offset in milliseconds.
+}
+
+// Modes of Malody.
+const (
+	ModeKey   = 0
+	ModeTaiko = 5
+)
+
+type Meta struct {
+	FormatVersion int     `json:"$ver"`
+	Creator       string  `json:"creator"`
+	Background    string  `json:"background"`
+	Version       string  `json:"version"` // Difficulty name.
+	Preview       int     `json:"preview"`
+	ID            int     `json:"id"`
+	Mode          int     `json:"mode"`
+	Song          Song    `json:"song"`
+	ModeExt       ModeExt `json:"mode_ext"`
+}
+
+type Song struct {
+	Title     string `json:"title"`
+	Artist    string `json:"artist"`
+	ID        int    `json:"id"`
+	TitleOrg  string `json:"titleorg"`
+	ArtistOrg string `json:"artistorg"`
+}
+
+type ModeExt struct {
+	Column int `json:"column"` // Key count at key mode.
+}
+
+// Beat is written as [x, y, z], which means x + y/z beats.
+type Beat [3]int
+
+func (b Beat) Value() float64 {
+	if b[2] == 0 {
+		return float64(b[0])
+	}
+	return float64(b[0]) + float64(b[1])/float64(b[2])
+}
+
+type BPM struct {
+	Beat Beat    `json:"beat"`
+	BPM  float64 `json:"bpm"`
+}
+
+type Effect struct {
+	Beat   Beat    `json:"beat"`
+	Scroll float64 `json:"scroll"`
+}
+
+// Note is a hit object at key and taiko mode,
+// or a music when Type is 1. EndBeat is set for long notes.
+// Style at taiko mode follows Taiko Jiro's:
+// 1 for Don, 2 for Ka, 3 and 4 for big ones, 5 and 6 for Roll, 7 for Balloon.
+type Note struct {
+	Beat    Beat   `json:"beat"`
+	EndBeat *Beat  `json:"endbeat"`
+	Column  int    `json:"column"`
+	Style   int    `json:"style"`
+	Hits    int    `json:"hits"`
+	Sound   string `json:"sound"`
+	Volume  int    `json:"vol"`
+	Offset  int    `json:"offset"` // Music offset in milliseconds.
+	Type    int    `json:"type"`
+}
+
+const NoteTypeMusic = 1
+
+func Parse(dat []byte) (*Format, error) {
+	var f Format
+	if err := json.Unmarshal(dat, &f); err != nil {
+		return nil, err
+	}
+	sort.SliceStable(f.Time, func(i, j int) bool {
+		return f.Time[i].Beat.Value() < f.Time[j].Beat.Value()
+	})
+	sort.SliceStable(f.Effect, func(i, j int) bool {
+		return f.Effect[i].Beat.Value() < f.Effect[j].Beat.Value()
+	})
+	if music, ok := f.Music(); ok {
+		f.offset = float64(music.Offset)
+	}
+	return &f, nil
+}
+
+// Mode reads mode and key count of the file. Mode is -1 when failed.
+func Mode(path string) (int, int) {
+	const modeError = -1
+	dat, err := os.ReadFile(path)
+	if err != nil {
+		return modeError, 0
+	}
+	var f struct {
+		Meta Meta `json:"meta"`
+	}
+	if err := json.Unmarshal(dat, &f); err != nil {
+		return modeError, 0
+	}
+	return f.Meta.Mode, f.Meta.ModeExt.Column
+}
+
+// Music returns the note of music. Ok is false when the chart has no music.
+func (f Format) Music() (n Note, ok bool) {
+	for _, n := range f.Note {
+		if n.Type == NoteTypeMusic {
+			return n, true
+		}
+	}
+	return Note{}, false
+}
+
+// BeatTime returns time of the beat in milliseconds.
+func (f Format) BeatTime(beat float64) float64 {
+	time := -f.offset
+	if len(f.Time) == 0 {
+		return time
+	}
+	var last float64
+	bpm := f.Time[0].BPM
+	for _, b := range f.Time[1:] {
+		v := b.Beat.Value()
+		if v >= beat {
+			break
+		}
+		time += (v - last) * 60000 / bpm
+		last = v
+		bpm = b.BPM
+	}
+	time += (beat - last) * 60000 / bpm
+	return time
+}
@@ -0,0 +1,99 @@
+package mc
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+const testChart = `{
+	"meta": {"$ver": 0, "creator": "Someone", "version": "4K Hard", "mode": 0,
+		"song": {"title": "Song", "artist": "Artist"}, "mode_ext": {"column": 4}},
+	"time": [{"beat": [4, 0, 1], "bpm": 240}, {"beat": [0, 0, 1], "bpm": 120}],
+	"note": [
+		{"beat": [0, 0, 1], "column": 0},
+		{"beat": [1, 1, 2], "endbeat": [2, 0, 1], "column": 3},
+		{"beat": [0, 0, 1], "sound": "song.ogg", "offset": 100, "type": 1}
+	]
+}`
+
+func TestBeatValue(t *testing.T) {
+	for _, tc := range []struct {
+		beat Beat
+		want float64
+	}{
+		{Beat{0, 0, 1}, 0},
+		{Beat{1, 1, 2}, 1.5},
+		{Beat{2, 3, 4}, 2.75},
+		{Beat{3, 1, 0}, 3}, // Zero denominator is ignored.
+	} {
+		if got := tc.beat.Value(); got != tc.want {
+			t.Errorf("%v.Value() = %v; want %v", tc.beat, got, tc.want)
+		}
+	}
+}
+
+// Time points are sorted, and times are shifted by the music offset.
+func TestBeatTime(t *testing.T) {
+	f, err := Parse([]byte(testChart))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, tc := range []struct {
+		beat float64
+		want float64
+	}{
+		{0, -100},
+		{2, 900},
+		{4, 1900},
+		{6, 2400},
+	} {
+		if got := f.BeatTime(tc.beat); got != tc.want {
+			t.Errorf("BeatTime(%v) = %v; want %v", tc.beat, got, tc.want)
+		}
+	}
+}
+
+func TestParse(t *testing.T) {
+	f, err := Parse([]byte(testChart))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if f.Meta.Song.Title != "Song" || f.Meta.Version != "4K Hard" || f.Meta.ModeExt.Column != 4 {
+		t.Errorf("meta: %+v", f.Meta)
+	}
+	if len(f.Note) != 3 || f.Note[0].EndBeat != nil || f.Note[1].EndBeat == nil {
+		t.Errorf("notes: %+v", f.Note)
+	}
+	if music, ok := f.Music(); !ok || music.Sound != "song.ogg" {
+		t.Errorf("Music() = %+v, %v", music, ok)
+	}
+	if _, err := Parse([]byte("{")); err == nil {
+		t.Error("Parse of malformed JSON: no error")
+	}
+}
+
+func TestMode(t *testing.T) {
+	dir := t.TempDir()
+	for _, tc := range []struct {
+		name     string
+		dat      string
+		mode     int
+		keyCount int
+	}{
+		{"key.mc", testChart, ModeKey, 4},
+		{"taiko.mc", `{"meta": {"mode": 5}}`, ModeTaiko, 0},
+		{"malformed.mc", "{", -1, 0},
+	} {
+		path := filepath.Join(dir, tc.name)
+		if err := os.WriteFile(path, []byte(tc.dat), 0644); err != nil {
+			t.Fatal(err)
+		}
+		if mode, keyCount := Mode(path); mode != tc.mode || keyCount != tc.keyCount {
+			t.Errorf("Mode(%s) = %d, %d; want %d, %d", tc.name, mode, keyCount, tc.mode, tc.keyCount)
+		}
+	}
+	if mode, _ := Mode(filepath.Join(dir, "none.mc")); mode != -1 {
+		t.Errorf("Mode of missing file = %d; want -1", mode)
+	}
+}
@@ -0,0 +1,50 @@
+package midi
+
+// Format is for Standard MIDI File (.mid). Format 0 and 1 are supported.
+// Times of all events are calculated in milliseconds when parsing.
+type Format struct {
+	Type     int    // 0: single track, 1: multiple tracks played simultaneously.
+	Division int    // Ticks per quarter note.
+	Name     string // Name of the first track, which is usually a title.
+	Notes    []Note
+	Tempos   []Tempo
+	Meters   []Meter
+	Measures []Measure
+}
+
+// DrumChannel is the channel reserved for percussions in General MIDI.
+const DrumChannel = 9
+
+type Note struct {
+	Tick     int64
+	Time     float64
+	Duration float64
+	Length   int64 // Duration in ticks.
+	Pitch    int   // 60 is the middle C.
+	Velocity int
+	Channel  int
+	Track    int
+}
+
+type Tempo struct {
+	Tick int64
+	Time float64
+	BPM  float64
+}
+
+// Meter is a time signature.
+type Meter struct {
+	Tick        int64
+	Time        float64
+	Numerator   int
+	Denominator int
+}
+
+// Length is a ratio to standard 4/4 measure.
+type Measure struct {
+	Tick   int64
+	Time   float64
+	Length float64
+}
+
+const defaultBPM = 120
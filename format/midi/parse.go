@@ -0,0 +1,291 @@
+package midi
+
+import (
+	"encoding/binary"
+	"fmt"
+	"sort"
+)
+
+// Todo: support SMPTE time division
+func Parse(dat []byte) (*Format, error) {
+	if len(dat) < 14 || string(dat[:4]) != "MThd" {
+		return nil, fmt.Errorf("not a standard MIDI file")
+	}
+	size := int(binary.BigEndian.Uint32(dat[4:8]))
+	if size < 6 || 8+size > len(dat) {
+		return nil, fmt.Errorf("invalid header size: %d", size)
+	}
+	f := &Format{
+		Type:     int(binary.BigEndian.Uint16(dat[8:10])),
+		Division: int(binary.BigEndian.Uint16(dat[12:14])),
+		Notes:    make([]Note, 0),
+		Tempos:   make([]Tempo, 0),
+		Meters:   make([]Meter, 0),
+	}
+	if f.Type > 1 {
+		return f, fmt.Errorf("unsupported format: %d", f.Type)
+	}
+	if f.Division&0x8000 != 0 || f.Division == 0 {
+		return f, fmt.Errorf("unsupported division: %#x", f.Division)
+	}
+
+	var endTick int64
+	dat = dat[8+size:]
+	for track := 0; len(dat) >= 8; track++ {
+		id := string(dat[:4])
+		size := int(binary.BigEndian.Uint32(dat[4:8]))
+		if 8+size > len(dat) {
+			size = len(dat) - 8 // Some files have wrong chunk size.
+		}
+		chunk := dat[8 : 8+size]
+		dat = dat[8+size:]
+		if id != "MTrk" { // Unknown chunks should be ignored.
+			track--
+			continue
+		}
+		tick, err := f.parseTrack(chunk, track)
+		if err != nil {
+			return f, fmt.Errorf("error at track %d: %s", track, err)
+		}
+		if tick > endTick {
+			endTick = tick
+		}
+	}
+
+	sort.SliceStable(f.Tempos, func(i, j int) bool { return f.Tempos[i].Tick < f.Tempos[j].Tick })
+	sort.SliceStable(f.Meters, func(i, j int) bool { return f.Meters[i].Tick < f.Meters[j].Tick })
+	sort.SliceStable(f.Notes, func(i, j int) bool {
+		if f.Notes[i].Tick == f.Notes[j].Tick {
+			return f.Notes[i].Pitch < f.Notes[j].Pitch
+		}
+		return f.Notes[i].Tick < f.Notes[j].Tick
+	})
+	for i, t := range f.Tempos {
+		f.Tempos[i].Time = f.TickTime(t.Tick)
+	}
+	for i, m := range f.Meters {
+		f.Meters[i].Time = f.TickTime(m.Tick)
+	}
+	for i, n := range f.Notes {
+		f.Notes[i].Time = f.TickTime(n.Tick)
+		f.Notes[i].Duration = f.TickTime(n.Tick+n.Length) - f.Notes[i].Time
+	}
+	f.setMeasures(endTick)
+	return f, nil
+}
+
+type noteKey struct{ channel, pitch int }
+
+// parseTrack returns the tick of the last event.
+func (f *Format) parseTrack(dat []byte, track int) (int64, error) {
+	var (
+		r       = reader{dat: dat}
+		tick    int64
+		status  byte
+		pending = make(map[noteKey][]Note) // Notes not released yet.
+	)
+	release := func(k noteKey) {
+		ns := pending[k]
+		if len(ns) == 0 {
+			return
+		}
+		n := ns[0] // First in, first out.
+		n.Length = tick - n.Tick
+		f.Notes = append(f.Notes, n)
+		pending[k] = ns[1:]
+	}
+	for r.len() > 0 {
+		delta, err := r.varLen()
+		if err != nil {
+			return tick, err
+		}
+		tick += delta
+		b, err := r.byte()
+		if err != nil {
+			return tick, err
+		}
+		switch {
+		case b == 0xFF: // Meta event.
+			status = 0
+			typ, err := r.byte()
+			if err != nil {
+				return tick, err
+			}
+			data, err := r.varData()
+			if err != nil {
+				return tick, err
+			}
+			switch typ {
+			case 0x03: // Sequence or track name.
+				if track == 0 && f.Name == "" {
+					f.Name = string(data)
+				}
+			case 0x51:
+				if len(data) < 3 {
+					continue
+				}
+				usec := int(data[0])<<16 | int(data[1])<<8 | int(data[2])
+				if usec == 0 {
+					continue
+				}
+				f.Tempos = append(f.Tempos, Tempo{Tick: tick, BPM: 60e6 / float64(usec)})
+			case 0x58:
+				if len(data) < 2 || data[0] == 0 || data[1] > 7 {
+					continue
+				}
+				f.Meters = append(f.Meters, Meter{
+					Tick:        tick,
+					Numerator:   int(data[0]),
+					Denominator: 1 << data[1],
+				})
+			case 0x2F: // End of track.
+				r.pos = len(r.dat)
+			}
+			continue
+		case b == 0xF0 || b == 0xF7: // System exclusive event.
+			status = 0
+			if _, err := r.varData(); err != nil {
+				return tick, err
+			}
+			continue
+		case b >= 0x80:
+			status = b
+		default: // Running status: the byte is the first data.
+			if status == 0 {
+				return tick, fmt.Errorf("data byte %#x without status", b)
+			}
+			r.pos--
+		}
+
+		var data [2]byte
+		count := 2
+		switch status & 0xF0 {
+		case 0xC0, 0xD0: // Program change, channel pressure.
+			count = 1
+		}
+		for i := 0; i < count; i++ {
+			if data[i], err = r.byte(); err != nil {
+				return tick, err
+			}
+		}
+		k := noteKey{int(status & 0x0F), int(data[0])}
+		switch status & 0xF0 {
+		case 0x80:
+			release(k)
+		case 0x90:
+			if data[1] == 0 { // Note on with zero velocity is note off.
+				release(k)
+				continue
+			}
+			pending[k] = append(pending[k], Note{
+				Tick:     tick,
+				Pitch:    k.pitch,
+				Velocity: int(data[1]),
+				Channel:  k.channel,
+				Track:    track,
+			})
+		}
+	}
+	for k := range pending { // Notes not released are released at the end of track.
+		for len(pending[k]) > 0 {
+			release(k)
+		}
+	}
+	return tick, nil
+}
+
+// TickTime returns time of the tick in milliseconds.
+// Tempos should be sorted.
+func (f Format) TickTime(tick int64) float64 {
+	var (
+		time float64
+		last int64
+		bpm  float64 = defaultBPM
+	)
+	for _, t := range f.Tempos {
+		if t.Tick >= tick {
+			break
+		}
+		time += float64(t.Tick-last) / float64(f.Division) * 60000 / bpm
+		last = t.Tick
+		bpm = t.BPM
+	}
+	time += float64(tick-last) / float64(f.Division) * 60000 / bpm
+	return time
+}
+
+// setMeasures lays measures from the start to the end tick.
+// A new time signature starts a new measure.
+func (f *Format) setMeasures(endTick int64) {
+	f.Measures = make([]Measure, 0)
+	var (
+		tick int64
+		num  = 4
+		den  = 4
+		i    int // Index of next meter.
+	)
+	for tick <= endTick {
+		for ; i < len(f.Meters) && f.Meters[i].Tick <= tick; i++ {
+			num, den = f.Meters[i].Numerator, f.Meters[i].Denominator
+		}
+		length := float64(num) / float64(den)
+		next := tick + int64(4*length*float64(f.Division))
+		if i < len(f.Meters) && f.Meters[i].Tick < next {
+			next = f.Meters[i].Tick
+		}
+		if next <= tick {
+			next = tick + int64(f.Division)
+		}
+		f.Measures = append(f.Measures, Measure{
+			Tick:   tick,
+			Time:   f.TickTime(tick),
+			Length: float64(next-tick) / float64(4*f.Division),
+		})
+		tick = next
+	}
+}
+
+type reader struct {
+	dat []byte
+	pos int
+}
+
+func (r reader) len() int { return len(r.dat) - r.pos }
+
+func (r *reader) byte() (byte, error) {
+	if r.pos >= len(r.dat) {
+		return 0, fmt.Errorf("unexpected end of track")
+	}
+	b := r.dat[r.pos]
+	r.pos++
+	return b, nil
+}
+
+// varLen reads a variable-length quantity: 7 bits per byte, big-endian.
+func (r *reader) varLen() (int64, error) {
+	var v int64
+	for i := 0; i < 4; i++ {
+		b, err := r.byte()
+		if err != nil {
+			return v, err
+		}
+		v = v<<7 | int64(b&0x7F)
+		if b&0x80 == 0 {
+			return v, nil
+		}
+	}
+	return v, fmt.Errorf("variable-length quantity too long")
+}
+
+func (r *reader) varData() ([]byte, error) {
+	size, err := r.varLen()
+	if err != nil {
+		return nil, err
+	}
+	if int(size) > r.len() {
+		return nil, fmt.Errorf("unexpected end of track")
+	}
+	data := r.dat[r.pos : r.pos+int(size)]
+	r.pos += int(size)
+	return data, nil
+}
@@ -0,0 +1,125 @@
+package midi
+
+import (
+	"encoding/binary"
+	"reflect"
+	"testing"
+)
+
+func chunk(id string, dat []byte) []byte {
+	b := append([]byte(id), 0, 0, 0, 0)
+	binary.BigEndian.PutUint32(b[4:], uint32(len(dat)))
+	return append(b, dat...)
+}
+
+func testMIDI(typ int, tracks ...[]byte) []byte {
+	header := []byte{0, byte(typ), 0, byte(len(tracks)), 0x01, 0xE0} // 480 ticks per quarter note.
+	dat := chunk("MThd", header)
+	for _, track := range tracks {
+		dat = append(dat, chunk("MTrk", track)...)
+	}
+	return dat
+}
+
+// 120 BPM in 4/4 from tick 0, then 240 BPM in 3/4 from tick 960.
+var (
+	testTempoTrack = []byte{
+		0x00, 0xFF, 0x03, 0x04, 'S', 'o', 'n', 'g',
+		0x00, 0xFF, 0x51, 0x03, 0x07, 0xA1, 0x20,
+		0x87, 0x40, 0xFF, 0x51, 0x03, 0x03, 0xD0, 0x90,
+		0x00, 0xFF, 0x58, 0x04, 0x03, 0x02, 0x18, 0x08,
+		0x00, 0xFF, 0x2F, 0x00,
+	}
+	testNoteTrack = []byte{
+		0x00, 0x90, 0x3C, 0x64,
+		0x83, 0x60, 0x80, 0x3C, 0x40,
+		0x00, 0x90, 0x40, 0x64,
+		0x83, 0x60, 0x40, 0x00, // Running status; note on with zero velocity.
+		0x00, 0x99, 0x24, 0x64, // Released at the end of track.
+		0x83, 0x60, 0xFF, 0x2F, 0x00,
+	}
+)
+
+func TestParse(t *testing.T) {
+	f, err := Parse(testMIDI(1, testTempoTrack, testNoteTrack))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if f.Type != 1 || f.Division != 480 || f.Name != "Song" {
+		t.Errorf("header: %d %d %q", f.Type, f.Division, f.Name)
+	}
+	wantNotes := []Note{
+		{Tick: 0, Time: 0, Duration: 500, Length: 480, Pitch: 60, Velocity: 100, Track: 1},
+		{Tick: 480, Time: 500, Duration: 500, Length: 480, Pitch: 64, Velocity: 100, Track: 1},
+		{Tick: 960, Time: 1000, Duration: 250, Length: 480, Pitch: 36, Velocity: 100, Channel: DrumChannel, Track: 1},
+	}
+	if !reflect.DeepEqual(f.Notes, wantNotes) {
+		t.Errorf("notes:\n got %+v\nwant %+v", f.Notes, wantNotes)
+	}
+	wantTempos := []Tempo{{0, 0, 120}, {960, 1000, 240}}
+	if !reflect.DeepEqual(f.Tempos, wantTempos) {
+		t.Errorf("tempos:\n got %+v\nwant %+v", f.Tempos, wantTempos)
+	}
+	wantMeters := []Meter{{960, 1000, 3, 4}}
+	if !reflect.DeepEqual(f.Meters, wantMeters) {
+		t.Errorf("meters:\n got %+v\nwant %+v", f.Meters, wantMeters)
+	}
+	// The first measure is cut by the new time signature.
+	wantMeasures := []Measure{{0, 0, 0.5}, {960, 1000, 0.75}}
+	if !reflect.DeepEqual(f.Measures, wantMeasures) {
+		t.Errorf("measures:\n got %+v\nwant %+v", f.Measures, wantMeasures)
+	}
+}
+
+func TestTickTime(t *testing.T) {
+	f := Format{Division: 480, Tempos: []Tempo{{Tick: 960, BPM: 240}}}
+	for _, tc := range []struct {
+		tick int64
+		want float64
+	}{
+		{0, 0},
+		{480, 500}, // Default BPM before the first tempo.
+		{960, 1000},
+		{1440, 1250},
+	} {
+		if got := f.TickTime(tc.tick); got != tc.want {
+			t.Errorf("TickTime(%d) = %v; want %v", tc.tick, got, tc.want)
+		}
+	}
+}
+
+func TestVarLen(t *testing.T) {
+	for _, tc := range []struct {
+		dat  []byte
+		want int64
+	}{
+		{[]byte{0x00}, 0},
+		{[]byte{0x7F}, 127},
+		{[]byte{0x81, 0x00}, 128},
+		{[]byte{0x83, 0x60}, 480},
+		{[]byte{0xFF, 0xFF, 0xFF, 0x7F}, 0x0FFFFFFF},
+	} {
+		r := reader{dat: tc.dat}
+		if got, err := r.varLen(); err != nil || got != tc.want {
+			t.Errorf("varLen(% x) = %d, %v; want %d", tc.dat, got, err, tc.want)
+		}
+	}
+}
+
+func TestParseInvalid(t *testing.T) {
+	for _, tc := range []struct {
+		name string
+		dat  []byte
+	}{
+		{"not MIDI", []byte("RIFF0000000000")},
+		{"short header", append(chunk("MThd", []byte{0, 0, 0, 1}), 0, 0)},
+		{"format 2", testMIDI(2, testNoteTrack)},
+		{"running status without status", testMIDI(0, []byte{0x00, 0x3C, 0x64})},
+		{"cut event", testMIDI(0, []byte{0x00, 0x90, 0x3C})},
+		{"long quantity", testMIDI(0, []byte{0xFF, 0xFF, 0xFF, 0xFF, 0x00})},
+	} {
+		if _, err := Parse(tc.dat); err == nil {
+			t.Errorf("%s: no error", tc.name)
+		}
+	}
+}
@@ -0,0 +1,334 @@
+package ojn
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"math"
+	"sort"
+)
+
+// Format is for O2Jam's .ojn file. A file has 3 charts: Easy, Normal and Hard.
+// Times of all objects are calculated in milliseconds when parsing.
+type Format struct {
+	Header
+	Charts [3]Chart
+	Cover  []byte // JPEG image.
+}
+
+type Header struct {
+	SongID     int
+	Genre      int
+	BPM        float64
+	Levels     [3]int
+	NoteCounts [3]int
+	Title      string
+	Artist     string
+	Noter      string
+	OJMFile    string
+	Durations  [3]int // In seconds.
+}
+
+type Chart struct {
+	Notes    []Note
+	BGMs     []Note // Samples played automatically.
+	BPMs     []BPMChange
+	Measures []Measure
+}
+
+type Note struct {
+	Time     float64
+	Key      int     // Key is in range of [0, 6].
+	Duration float64 // Non-zero for long notes.
+	Sample   int     // ID of sample at OJM file. Negative value means no sample.
+	Volume   float64 // Range is [0, 1].
+}
+
+type BPMChange struct {
+	Time float64
+	BPM  float64
+}
+
+// Length is a ratio to standard 4/4 measure.
+type Measure struct {
+	Time   float64
+	Length float64
+}
+
+var Difficulties = [3]string{"Easy", "Normal", "Hard"}
+
+// Single is a Format with one chart of given difficulty.
+type Single struct {
+	Header
+	Difficulty int
+	Chart
+}
+
+func (f Format) Single(difficulty int) *Single {
+	return &Single{
+		Header:     f.Header,
+		Difficulty: difficulty,
+		Chart:      f.Charts[difficulty],
+	}
+}
+
+// header is a raw header of OJN file: 300 bytes.
+type header struct {
+	SongID           int32
+	Signature        [4]byte
+	EncodeVersion    float32
+	Genre            int32
+	BPM              float32
+	Levels           [4]int16
+	EventCounts      [3]int32
+	NoteCounts       [3]int32
+	MeasureCounts    [3]int32
+	PackageCounts    [3]int32
+	OldEncodeVersion int16
+	OldSongID        int16
+	OldGenre         [20]byte
+	BMPSize          int32
+	OldFileVersion   int32
+	Title            [64]byte
+	Artist           [32]byte
+	Noter            [32]byte
+	OJMFile          [32]byte
+	CoverSize        int32
+	Times            [3]int32
+	NoteOffsets      [3]int32
+	CoverOffset      int32
+}
+
+// ParseHeader parses header only. It is for listing charts quickly.
+func ParseHeader(dat []byte) (Header, error) {
+	h, err := parseHeader(dat)
+	if err != nil {
+		return Header{}, err
+	}
+	return h.Header(), nil
+}
+
+func parseHeader(dat []byte) (h header, err error) {
+	err = binary.Read(bytes.NewReader(dat), binary.LittleEndian, &h)
+	if err != nil {
+		return
+	}
+	if string(h.Signature[:3]) != "ojn" {
+		err = fmt.Errorf("invalid signature: %q", h.Signature)
+	}
+	return
+}
+
+func (h header) Header() Header {
+	hd := Header{
+		SongID:  int(h.SongID),
+		Genre:   int(h.Genre),
+		BPM:     float64(h.BPM),
+		Title:   cString(h.Title[:]),
+		Artist:  cString(h.Artist[:]),
+		Noter:   cString(h.Noter[:]),
+		OJMFile: cString(h.OJMFile[:]),
+	}
+	for i := 0; i < 3; i++ {
+		hd.Levels[i] = int(h.Levels[i])
+		hd.NoteCounts[i] = int(h.NoteCounts[i])
+		hd.Durations[i] = int(h.Times[i])
+	}
+	return hd
+}
+
+// Todo: support EUC-KR encoded texts
+func cString(b []byte) string {
+	if i := bytes.IndexByte(b, 0); i >= 0 {
+		b = b[:i]
+	}
+	return string(b)
+}
+
+func Parse(dat []byte) (*Format, error) {
+	h, err := parseHeader(dat)
+	if err != nil {
+		return nil, err
+	}
+	f := &Format{Header: h.Header()}
+	for i := 0; i < 3; i++ {
+		start := int(h.NoteOffsets[i])
+		end := int(h.CoverOffset)
+		if i < 2 {
+			end = int(h.NoteOffsets[i+1])
+		}
+		if start < 0 || end > len(dat) || start > end {
+			return f, fmt.Errorf("invalid note offset at %s: %d-%d", Difficulties[i], start, end)
+		}
+		c, err := parseChart(dat[start:end], int(h.PackageCounts[i]), f.BPM)
+		if err != nil {
+			return f, fmt.Errorf("error at %s: %s", Difficulties[i], err)
+		}
+		f.Charts[i] = c
+	}
+	if start, end := int(h.CoverOffset), int(h.CoverOffset+h.CoverSize); start >= 0 && end <= len(dat) && start < end {
+		f.Cover = dat[start:end]
+	}
+	return f, nil
+}
+
+// Channel 0 is for measure length, 1 is for BPM,
+// 2 to 8 are for keys and the others are for BGM.
+const (
+	channelMeasure = 0
+	channelBPM     = 1
+	channelKey     = 2
+	channelBGM     = 9
+)
+
+// Note types.
+const (
+	typeNormal  = 0
+	typeLNStart = 2
+	typeLNEnd   = 3
+)
+
+type packageHeader struct {
+	Measure int32
+	Channel int16
+	Events  int16
+}
+
+type event struct {
+	measure int
+	pos     float64 // Position in a measure: [0, 1).
+	channel int
+	// Values of note event.
+	value  int
+	volPan uint8
+	typ    uint8
+	// Value of measure and BPM event.
+	float float64
+}
+
+func parseChart(dat []byte, packageCount int, bpm float64) (c Chart, err error) {
+	r := bytes.NewReader(dat)
+	events := make([]event, 0)
+	lengths := make(map[int]float64)
+	var measureCount int
+	for i := 0; i < packageCount; i++ {
+		var ph packageHeader
+		if err = binary.Read(r, binary.LittleEndian, &ph); err != nil {
+			return
+		}
+		if measureCount < int(ph.Measure)+1 {
+			measureCount = int(ph.Measure) + 1
+		}
+		for j := 0; j < int(ph.Events); j++ {
+			var raw [4]byte
+			if _, err = r.Read(raw[:]); err != nil {
+				return
+			}
+			e := event{
+				measure: int(ph.Measure),
+				pos:     float64(j) / float64(ph.Events),
+				channel: int(ph.Channel),
+			}
+			switch e.channel {
+			case channelMeasure, channelBPM:
+				e.float = float64(math.Float32frombits(binary.LittleEndian.Uint32(raw[:])))
+				if e.float <= 0 {
+					continue
+				}
+				if e.channel == channelMeasure {
+					lengths[e.measure] = e.float
+					continue
+				}
+			default:
+				e.value = int(int16(binary.LittleEndian.Uint16(raw[:2])))
+				e.volPan = raw[2]
+				e.typ = raw[3]
+				if e.value == 0 {
+					continue
+				}
+			}
+			events = append(events, e)
+		}
+	}
+
+	length := func(m int) float64 {
+		if v, ok := lengths[m]; ok {
+			return v
+		}
+		return 1
+	}
+	starts := make([]float64, measureCount+1) // Beats at the start of each measure.
+	for m := 0; m < measureCount; m++ {
+		starts[m+1] = starts[m] + 4*length(m)
+	}
+	beatOf := func(e event) float64 { return starts[e.measure] + 4*length(e.measure)*e.pos }
+	for m := 0; m < measureCount; m++ {
+		events = append(events, event{measure: m, channel: channelMeasure})
+	}
+	// BPM changes go first at the same beat.
+	sort.SliceStable(events, func(i, j int) bool {
+		bi, bj := beatOf(events[i]), beatOf(events[j])
+		if bi == bj {
+			return events[i].channel == channelBPM && events[j].channel != channelBPM
+		}
+		return bi < bj
+	})
+
+	var (
+		beat    float64
+		time    float64
+		lnHeads = make(map[int]int) // Index of pending long note heads for each key.
+	)
+	c.Notes = make([]Note, 0)
+	c.BGMs = make([]Note, 0)
+	c.BPMs = make([]BPMChange, 0)
+	c.Measures = make([]Measure, 0, measureCount)
+	for _, e := range events {
+		b := beatOf(e)
+		time += (b - beat) * 60000 / bpm
+		beat = b
+		switch {
+		case e.channel == channelMeasure:
+			c.Measures = append(c.Measures, Measure{Time: time, Length: length(e.measure)})
+		case e.channel == channelBPM:
+			bpm = e.float
+			c.BPMs = append(c.BPMs, BPMChange{Time: time, BPM: bpm})
+		case e.channel >= channelKey && e.channel < channelBGM:
+			n := e.note(time)
+			switch e.typ % 4 { // Types 4 to 7 are of OGG samples.
+			case typeLNStart:
+				lnHeads[n.Key] = len(c.Notes)
+				c.Notes = append(c.Notes, n)
+			case typeLNEnd:
+				if i, ok := lnHeads[n.Key]; ok {
+					c.Notes[i].Duration = time - c.Notes[i].Time
+					delete(lnHeads, n.Key)
+				}
+			default:
+				c.Notes = append(c.Notes, n)
+			}
+		default:
+			c.BGMs = append(c.BGMs, e.note(time))
+		}
+	}
+	return
+}
+
+func (e event) note(time float64) Note {
+	n := Note{
+		Time:   time,
+		Key:    e.channel - channelKey,
+		Sample: e.value - 1,
+		Volume: float64((e.volPan>>4)&0x0F) / 16,
+	}
+	if e.channel >= channelBGM {
+		n.Key = -1
+	}
+	if e.typ%8 > 3 { // OGG samples.
+		n.Sample += 1000
+	}
+	if n.Volume == 0 {
+		n.Volume = 1
+	}
+	return n
+}
@@ -0,0 +1,152 @@
+package ojn
+
+import (
+	"bytes"
+	"encoding/binary"
+	"math"
+	"reflect"
+	"testing"
+)
+
+type testEvent struct {
+	value  int16
+	volPan uint8
+	typ    uint8
+}
+
+func writeLE(b *bytes.Buffer, v any) { binary.Write(b, binary.LittleEndian, v) }
+
+func writePackage(b *bytes.Buffer, measure int32, channel int16, events []testEvent) {
+	writeLE(b, packageHeader{measure, channel, int16(len(events))})
+	for _, e := range events {
+		writeLE(b, e)
+	}
+}
+
+func writeFloatPackage(b *bytes.Buffer, measure int32, channel int16, v float32) {
+	writeLE(b, packageHeader{measure, channel, 1})
+	writeLE(b, math.Float32bits(v))
+}
+
+// testOJN has a chart at Easy only. The chart starts at 120 BPM.
+// Measure 0 has every note type at key 0 in order.
+// Measure 1 is a half measure at 240 BPM with a BGM.
+// Measure 2 has a note at key 1 with half volume.
+func testOJN() []byte {
+	var chart bytes.Buffer
+	events := make([]testEvent, 8)
+	for typ := range events {
+		events[typ] = testEvent{value: int16(typ + 1), typ: uint8(typ)}
+	}
+	writePackage(&chart, 0, channelKey, events)
+	writeFloatPackage(&chart, 1, channelBPM, 240)
+	writeFloatPackage(&chart, 1, channelMeasure, 0.5)
+	writePackage(&chart, 1, channelBGM, []testEvent{{value: 3}, {value: 0}})
+	writePackage(&chart, 2, channelKey+1, []testEvent{{value: 1, volPan: 0x80}})
+
+	const headerSize = 300
+	end := int32(headerSize + chart.Len())
+	h := header{
+		SongID:        100,
+		Signature:     [4]byte{'o', 'j', 'n'},
+		Genre:         2,
+		BPM:           120,
+		Levels:        [4]int16{1, 2, 3},
+		NoteCounts:    [3]int32{7},
+		PackageCounts: [3]int32{5},
+		CoverSize:     3,
+		Times:         [3]int32{90, 90, 90},
+		NoteOffsets:   [3]int32{headerSize, end, end},
+		CoverOffset:   end,
+	}
+	copy(h.Title[:], "Song")
+	copy(h.Artist[:], "Artist")
+	copy(h.Noter[:], "Noter")
+	copy(h.OJMFile[:], "o2ma100.ojm")
+
+	var b bytes.Buffer
+	writeLE(&b, h)
+	b.Write(chart.Bytes())
+	b.WriteString("JPG")
+	return b.Bytes()
+}
+
+func TestParse(t *testing.T) {
+	f, err := Parse(testOJN())
+	if err != nil {
+		t.Fatal(err)
+	}
+	wantHeader := Header{
+		SongID:     100,
+		Genre:      2,
+		BPM:        120,
+		Levels:     [3]int{1, 2, 3},
+		NoteCounts: [3]int{7},
+		Title:      "Song",
+		Artist:     "Artist",
+		Noter:      "Noter",
+		OJMFile:    "o2ma100.ojm",
+		Durations:  [3]int{90, 90, 90},
+	}
+	if f.Header != wantHeader {
+		t.Errorf("header:\n got %+v\nwant %+v", f.Header, wantHeader)
+	}
+	if string(f.Cover) != "JPG" {
+		t.Errorf("cover: %q", f.Cover)
+	}
+
+	c := f.Charts[0]
+	wantNotes := []Note{
+		{Time: 0, Key: 0, Sample: 0, Volume: 1},                      // Type 0: normal.
+		{Time: 250, Key: 0, Sample: 1, Volume: 1},                    // Type 1: regarded as normal.
+		{Time: 500, Key: 0, Duration: 250, Sample: 2, Volume: 1},     // Type 2 and 3: long note.
+		{Time: 1000, Key: 0, Sample: 1004, Volume: 1},                // Type 4: normal with OGG sample.
+		{Time: 1250, Key: 0, Sample: 1005, Volume: 1},                // Type 5.
+		{Time: 1500, Key: 0, Duration: 250, Sample: 1006, Volume: 1}, // Type 6 and 7: long note with OGG sample.
+		{Time: 2500, Key: 1, Sample: 0, Volume: 0.5},
+	}
+	if !reflect.DeepEqual(c.Notes, wantNotes) {
+		t.Errorf("notes:\n got %+v\nwant %+v", c.Notes, wantNotes)
+	}
+	wantBGMs := []Note{{Time: 2000, Key: -1, Sample: 2, Volume: 1}}
+	if !reflect.DeepEqual(c.BGMs, wantBGMs) {
+		t.Errorf("BGMs:\n got %+v\nwant %+v", c.BGMs, wantBGMs)
+	}
+	wantBPMs := []BPMChange{{2000, 240}}
+	if !reflect.DeepEqual(c.BPMs, wantBPMs) {
+		t.Errorf("BPMs:\n got %+v\nwant %+v", c.BPMs, wantBPMs)
+	}
+	wantMeasures := []Measure{{0, 1}, {2000, 0.5}, {2500, 1}}
+	if !reflect.DeepEqual(c.Measures, wantMeasures) {
+		t.Errorf("measures:\n got %+v\nwant %+v", c.Measures, wantMeasures)
+	}
+	for _, i := range []int{1, 2} {
+		if len(f.Charts[i].Notes) != 0 {
+			t.Errorf("%s: %d notes", Difficulties[i], len(f.Charts[i].Notes))
+		}
+	}
+}
+
+func TestParseInvalid(t *testing.T) {
+	dat := testOJN()
+	badSignature := append([]byte{}, dat...)
+	copy(badSignature[4:], "bms")
+	badOffset := append([]byte{}, dat...)
+	binary.LittleEndian.PutUint32(badOffset[284:], uint32(len(dat)+1)) // NoteOffsets[0]
+	for _, tc := range []struct {
+		name string
+		dat  []byte
+	}{
+		{"short header", dat[:100]},
+		{"bad signature", badSignature},
+		{"bad note offset", badOffset},
+		{"cut package", dat[:310]},
+	} {
+		if _, err := Parse(tc.dat); err == nil {
+			t.Errorf("%s: no error", tc.name)
+		}
+	}
+	if _, err := ParseHeader(dat[:300]); err != nil {
+		t.Errorf("ParseHeader: %s", err)
+	}
+}
@@ -0,0 +1,264 @@
+package ojn
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+)
+
+// Sample is an audio data in OJM file.
+// Data is a complete audio file; Ext tells its format.
+type Sample struct {
+	ID   int
+	Name string
+	Ext  string // ".wav" or ".ogg".
+	Data []byte
+}
+
+// ParseOJM parses O2Jam's .ojm file: M30, OMC and OJM are supported.
+func ParseOJM(dat []byte) ([]Sample, error) {
+	if len(dat) < 4 {
+		return nil, fmt.Errorf("too short data")
+	}
+	switch sig := string(dat[:3]); sig {
+	case "M30":
+		return parseM30(dat)
+	case "OMC":
+		return parseOMC(dat, true)
+	case "OJM":
+		return parseOMC(dat, false)
+	default:
+		return nil, fmt.Errorf("unknown signature: %q", sig)
+	}
+}
+
+type m30Header struct {
+	Signature    [4]byte
+	Version      int32
+	Encryption   int32
+	SampleCount  int32
+	SampleOffset int32
+	PayloadSize  int32
+	Padding      int32
+}
+
+type m30SampleHeader struct {
+	Name       [32]byte
+	Size       int32
+	Codec      int16
+	Codec2     int16
+	MusicFlag  int32
+	Ref        int16
+	Unknown    int16
+	PCMSamples int32
+}
+
+// M30 contains OGG samples only.
+func parseM30(dat []byte) ([]Sample, error) {
+	r := bytes.NewReader(dat)
+	var h m30Header
+	if err := binary.Read(r, binary.LittleEndian, &h); err != nil {
+		return nil, err
+	}
+	if _, err := r.Seek(int64(h.SampleOffset), 0); err != nil {
+		return nil, err
+	}
+	samples := make([]Sample, 0, h.SampleCount)
+	for i := 0; i < int(h.SampleCount); i++ {
+		var sh m30SampleHeader
+		if err := binary.Read(r, binary.LittleEndian, &sh); err != nil {
+			return samples, err
+		}
+		data := make([]byte, sh.Size)
+		if _, err := r.Read(data); err != nil {
+			return samples, err
+		}
+		switch h.Encryption {
+		case 16:
+			xor(data, []byte("nami"))
+		case 32:
+			xor(data, []byte("0412"))
+		}
+		s := Sample{
+			ID:   int(sh.Ref),
+			Name: cString(sh.Name[:]),
+			Ext:  ".ogg",
+			Data: data,
+		}
+		if sh.Codec == 0 { // Background samples.
+			s.ID += 1000
+		}
+		samples = append(samples, s)
+	}
+	return samples, nil
+}
+
+// xor is applied per 4 bytes. Remained bytes are left as they are.
+func xor(data, mask []byte) {
+	for i := 0; i+3 < len(data); i += 4 {
+		for j := 0; j < 4; j++ {
+			data[i+j] ^= mask[j]
+		}
+	}
+}
+
+type omcHeader struct {
+	Signature [4]byte
+	WAVCount  int16
+	OGGCount  int16
+	WAVStart  int32
+	OGGStart  int32
+	FileSize  int32
+}
+
+type omcWAVHeader struct {
+	Name          [32]byte
+	AudioFormat   int16
+	NumChannels   int16
+	SampleRate    int32
+	BitRate       int32
+	BlockAlign    int16
+	BitsPerSample int16
+	Unknown       int32
+	ChunkSize     int32
+}
+
+type omcOGGHeader struct {
+	Name [32]byte
+	Size int32
+}
+
+// OMC has encrypted WAV samples while OJM has plain ones.
+// IDs of WAV samples start from 0, and those of OGG samples start from 1000.
+func parseOMC(dat []byte, encrypted bool) ([]Sample, error) {
+	r := bytes.NewReader(dat)
+	var h omcHeader
+	if err := binary.Read(r, binary.LittleEndian, &h); err != nil {
+		return nil, err
+	}
+	samples := make([]Sample, 0, int(h.WAVCount)+int(h.OGGCount))
+	d := decrypter{keyByte: 0xFF}
+	if _, err := r.Seek(int64(h.WAVStart), 0); err != nil {
+		return nil, err
+	}
+	for id := 0; r.Size()-int64(r.Len()) < int64(h.OGGStart); id++ {
+		var wh omcWAVHeader
+		if err := binary.Read(r, binary.LittleEndian, &wh); err != nil {
+			return samples, err
+		}
+		if wh.ChunkSize == 0 {
+			continue
+		}
+		data := make([]byte, wh.ChunkSize)
+		if _, err := r.Read(data); err != nil {
+			return samples, err
+		}
+		if encrypted {
+			data = rearrange(data)
+			d.accXOR(data)
+		}
+		samples = append(samples, Sample{
+			ID:   id,
+			Name: cString(wh.Name[:]),
+			Ext:  ".wav",
+			Data: wh.wav(data),
+		})
+	}
+	if _, err := r.Seek(int64(h.OGGStart), 0); err != nil {
+		return nil, err
+	}
+	for id := 1000; r.Size()-int64(r.Len()) < int64(h.FileSize) && r.Len() > 0; id++ {
+		var oh omcOGGHeader
+		if err := binary.Read(r, binary.LittleEndian, &oh); err != nil {
+			return samples, err
+		}
+		if oh.Size == 0 {
+			continue
+		}
+		data := make([]byte, oh.Size)
+		if _, err := r.Read(data); err != nil {
+			return samples, err
+		}
+		samples = append(samples, Sample{
+			ID:   id,
+			Name: cString(oh.Name[:]),
+			Ext:  ".ogg",
+			Data: data,
+		})
+	}
+	return samples, nil
+}
+
+// wav wraps PCM data with RIFF header.
+func (h omcWAVHeader) wav(pcm []byte) []byte {
+	var b bytes.Buffer
+	w := func(v any) { binary.Write(&b, binary.LittleEndian, v) }
+	b.WriteString("RIFF")
+	w(int32(36 + len(pcm)))
+	b.WriteString("WAVEfmt ")
+	w(int32(16))
+	w(h.AudioFormat)
+	w(h.NumChannels)
+	w(h.SampleRate)
+	w(h.BitRate)
+	w(h.BlockAlign)
+	w(h.BitsPerSample)
+	b.WriteString("data")
+	w(int32(len(pcm)))
+	b.Write(pcm)
+	return b.Bytes()
+}
+
+// rearrange swaps 17 blocks of data by the table.
+func rearrange(src []byte) []byte {
+	dst := make([]byte, len(src))
+	copy(dst, src)
+	key := ((len(src) % 17) << 4) + (len(src) % 17)
+	size := len(src) / 17
+	for block := 0; block < 17; block++ {
+		start := size * block
+		copy(dst[size*rearrangeTable[key]:], src[start:start+size])
+		key++
+	}
+	return dst
+}
+
+// decrypter keeps its state through all WAV samples of a file.
+type decrypter struct {
+	keyByte byte
+	counter int
+}
+
+func (d *decrypter) accXOR(data []byte) {
+	for i, b := range data {
+		if (int(d.keyByte)<<d.counter)&0x80 != 0 {
+			data[i] = ^b
+		}
+		d.counter++
+		if d.counter > 7 {
+			d.counter = 0
+			d.keyByte = b
+		}
+	}
+}
+
+var rearrangeTable = []int{
+	16, 14, 2, 9, 4, 0, 7, 1, 6, 8, 15, 10, 5, 12, 3, 13, 11,
+	7, 2, 10, 11, 3, 5, 13, 8, 4, 0, 12, 6, 15, 14, 16, 1, 9,
+	12, 13, 3, 0, 6, 9, 10, 1, 7, 8, 16, 2, 11, 14, 4, 15, 5,
+	8, 3, 4, 13, 6, 5, 11, 16, 2, 12, 7, 9, 10, 15, 14, 0, 1,
+	15, 2, 12, 13, 0, 4, 1, 5, 7, 3, 9, 16, 6, 11, 10, 8, 14,
+	0, 4, 11, 16, 15, 13, 12, 6, 5, 7, 1, 2, 3, 8, 9, 10, 14,
+	3, 16, 8, 7, 6, 9, 14, 13, 0, 10, 11, 4, 5, 12, 2, 1, 15,
+	4, 14, 16, 15, 5, 8, 7, 11, 0, 1, 6, 2, 12, 9, 3, 10, 13,
+	6, 13, 14, 7, 16, 10, 11, 0, 1, 12, 15, 2, 3, 8, 9, 4, 5,
+	10, 12, 0, 8, 9, 13, 3, 4, 5, 16, 14, 15, 1, 2, 11, 6, 7,
+	5, 6, 12, 4, 13, 15, 7, 14, 8, 1, 9, 2, 16, 10, 11, 0, 3,
+	11, 15, 4, 14, 3, 1, 0, 2, 13, 12, 6, 7, 5, 16, 9, 8, 10,
+	3, 2, 1, 0, 4, 12, 13, 11, 16, 5, 6, 15, 14, 7, 9, 10, 8,
+	9, 10, 0, 7, 8, 6, 16, 3, 4, 1, 2, 5, 11, 14, 15, 13, 12,
+	10, 6, 9, 12, 11, 16, 7, 8, 0, 15, 3, 1, 2, 5, 13, 14, 4,
+	13, 0, 1, 14, 2, 3, 8, 11, 7, 12, 9, 5, 10, 15, 4, 6, 16,
+	1, 14, 2, 3, 13, 11, 7, 0, 8, 12, 9, 6, 15, 16, 5, 10, 4,
+	0,
+}
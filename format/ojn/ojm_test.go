@@ -0,0 +1,189 @@
+package ojn
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+)
+
+// encrypter does the inverse of accXOR and rearrange.
+// Its state goes along with that of decrypter.
+type encrypter struct {
+	keyByte byte
+	counter int
+}
+
+func (e *encrypter) encrypt(plain []byte) []byte {
+	data := make([]byte, len(plain))
+	for i, b := range plain {
+		if (int(e.keyByte)<<e.counter)&0x80 != 0 {
+			b = ^b
+		}
+		data[i] = b
+		e.counter++
+		if e.counter > 7 {
+			e.counter = 0
+			e.keyByte = b
+		}
+	}
+	dst := make([]byte, len(data))
+	copy(dst, data)
+	key := ((len(data) % 17) << 4) + (len(data) % 17)
+	size := len(data) / 17
+	for block := 0; block < 17; block++ {
+		copy(dst[size*block:size*(block+1)], data[size*rearrangeTable[key]:])
+		key++
+	}
+	return dst
+}
+
+func testM30(encryption int32, codec int16, plain []byte) []byte {
+	const headerSize = 28
+	var b bytes.Buffer
+	writeLE(&b, m30Header{
+		Signature:    [4]byte{'M', '3', '0'},
+		Encryption:   encryption,
+		SampleCount:  1,
+		SampleOffset: headerSize,
+	})
+	sh := m30SampleHeader{Size: int32(len(plain)), Codec: codec, Ref: 5}
+	copy(sh.Name[:], "kick.ogg")
+	writeLE(&b, sh)
+	data := append([]byte{}, plain...)
+	switch encryption {
+	case 16:
+		xor(data, []byte("nami"))
+	case 32:
+		xor(data, []byte("0412"))
+	}
+	b.Write(data)
+	return b.Bytes()
+}
+
+func testOMC(signature string, encrypted bool, wavs [][]byte, ogg []byte) []byte {
+	const headerSize = 20
+	var wavPart bytes.Buffer
+	e := encrypter{keyByte: 0xFF}
+	for i, pcm := range wavs {
+		wh := omcWAVHeader{AudioFormat: 1, NumChannels: 1, SampleRate: 44100, BitsPerSample: 16,
+			ChunkSize: int32(len(pcm))}
+		copy(wh.Name[:], []string{"a.wav", "b.wav", "c.wav"}[i])
+		writeLE(&wavPart, wh)
+		if encrypted {
+			pcm = e.encrypt(pcm)
+		}
+		wavPart.Write(pcm)
+	}
+	var oggPart bytes.Buffer
+	oh := omcOGGHeader{Size: int32(len(ogg))}
+	copy(oh.Name[:], "d.ogg")
+	writeLE(&oggPart, oh)
+	oggPart.Write(ogg)
+
+	var b bytes.Buffer
+	writeLE(&b, omcHeader{
+		Signature: [4]byte{signature[0], signature[1], signature[2]},
+		WAVCount:  int16(len(wavs)),
+		OGGCount:  1,
+		WAVStart:  headerSize,
+		OGGStart:  int32(headerSize + wavPart.Len()),
+		FileSize:  int32(headerSize + wavPart.Len() + oggPart.Len()),
+	})
+	b.Write(wavPart.Bytes())
+	b.Write(oggPart.Bytes())
+	return b.Bytes()
+}
+
+func TestParseM30(t *testing.T) {
+	plain := []byte("OggS sample data") // 2 bytes at the end are not xored.
+	plain = append(plain, 1, 2)
+	for _, tc := range []struct {
+		name       string
+		encryption int32
+		codec      int16
+		id         int
+	}{
+		{"plain", 0, 5, 5},
+		{"nami", 16, 5, 5},
+		{"0412", 32, 5, 5},
+		{"background", 16, 0, 1005},
+	} {
+		samples, err := ParseOJM(testM30(tc.encryption, tc.codec, plain))
+		if err != nil {
+			t.Errorf("%s: %s", tc.name, err)
+			continue
+		}
+		want := []Sample{{ID: tc.id, Name: "kick.ogg", Ext: ".ogg", Data: plain}}
+		if !reflect.DeepEqual(samples, want) {
+			t.Errorf("%s:\n got %+v\nwant %+v", tc.name, samples, want)
+		}
+	}
+}
+
+// The decrypter keeps its state from the first WAV sample to the second one.
+func TestParseOMC(t *testing.T) {
+	pcm1 := []byte("first PCM data which is longer than 17 bytes!")
+	pcm2 := []byte("second PCM data, which has a remainder")
+	ogg := []byte("OggS data")
+	for _, tc := range []struct {
+		signature string
+		encrypted bool
+	}{
+		{"OMC", true},
+		{"OJM", false},
+	} {
+		dat := testOMC(tc.signature, tc.encrypted, [][]byte{pcm1, {}, pcm2}, ogg)
+		samples, err := ParseOJM(dat)
+		if err != nil {
+			t.Errorf("%s: %s", tc.signature, err)
+			continue
+		}
+		var got []any
+		for _, s := range samples {
+			got = append(got, s.ID, s.Name, s.Ext)
+		}
+		want := []any{0, "a.wav", ".wav", 2, "c.wav", ".wav", 1000, "d.ogg", ".ogg"}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("%s: samples = %v; want %v", tc.signature, got, want)
+			continue
+		}
+		for i, pcm := range [][]byte{pcm1, pcm2} {
+			s := samples[i]
+			if !bytes.HasPrefix(s.Data, []byte("RIFF")) || !bytes.HasSuffix(s.Data, pcm) || len(s.Data) != 44+len(pcm) {
+				t.Errorf("%s: WAV %d = %q", tc.signature, i, s.Data)
+			}
+		}
+		if !bytes.Equal(samples[2].Data, ogg) {
+			t.Errorf("%s: OGG = %q", tc.signature, samples[2].Data)
+		}
+	}
+}
+
+// Each block of data goes to the position in the table.
+func TestRearrange(t *testing.T) {
+	src := make([]byte, 17)
+	for i := range src {
+		src[i] = byte(i)
+	}
+	dst := rearrange(src)
+	for block := range src {
+		if got := dst[rearrangeTable[block]]; got != byte(block) {
+			t.Errorf("block %d at %d: %d", block, rearrangeTable[block], got)
+		}
+	}
+}
+
+func TestParseOJMInvalid(t *testing.T) {
+	for _, tc := range []struct {
+		name string
+		dat  []byte
+	}{
+		{"too short", []byte("M3")},
+		{"unknown signature", []byte("WAVE0000")},
+		{"cut M30", testM30(0, 5, []byte("OggS"))[:40]},
+	} {
+		if _, err := ParseOJM(tc.dat); err == nil {
+			t.Errorf("%s: no error", tc.name)
+		}
+	}
+}
@@ -0,0 +1,108 @@
+package osr
+
+import (
+	"bytes"
+	"encoding/binary"
+	"io"
+	"strconv"
+	"time"
+
+	"github.com/ulikunitz/xz/lzma"
+)
+
+// Last action of replay data is dummy which has a random seed at Z.
+const SeedActionTime = -12345
+
+// Encode writes the Format in .osr format.
+// Seed action is appended when the replay data does not end with it.
+func (f Format) Encode() ([]byte, error) {
+	var b bytes.Buffer
+	w := func(v any) { _ = binary.Write(&b, binary.LittleEndian, v) } // Writing to Buffer never fails.
+	w(f.GameMode)
+	w(f.GameVersion)
+	writeString(&b, f.BeatmapMD5)
+	writeString(&b, f.PlayerName)
+	writeString(&b, f.ReplayMD5)
+	w(f.Num300)
+	w(f.Num100)
+	w(f.Num50)
+	w(f.NumGeki)
+	w(f.NumKatu)
+	w(f.NumMiss)
+	w(f.Score)
+	w(f.Combo)
+	w(f.FullCombo)
+	w(f.ModsBits)
+	writeString(&b, f.LifeBar)
+	w(f.TimeStamp)
+	data, err := encodeReplayData(f.ReplayData)
+	if err != nil {
+		return nil, err
+	}
+	w(int32(len(data)))
+	b.Write(data)
+	w(f.OnlineID)
+	return b.Bytes(), nil
+}
+
+// WriteTo implements io.WriterTo.
+func (f Format) WriteTo(w io.Writer) (int64, error) {
+	dat, err := f.Encode()
+	if err != nil {
+		return 0, err
+	}
+	n, err := w.Write(dat)
+	return int64(n), err
+}
+
+// String is written as 0x0b, ULEB128 length and UTF-8 bytes.
+// Empty string is written as 0x00.
+func writeString(b *bytes.Buffer, s string) {
+	if s == "" {
+		b.WriteByte(0x00)
+		return
+	}
+	b.WriteByte(0x0b)
+	buf := make([]byte, binary.MaxVarintLen64)
+	b.Write(buf[:binary.PutUvarint(buf, uint64(len(s)))])
+	b.WriteString(s)
+}
+
+func encodeReplayData(actions []Action) ([]byte, error) {
+	if len(actions) == 0 || actions[len(actions)-1].W != SeedActionTime {
+		actions = append(actions, Action{W: SeedActionTime})
+	}
+	var raw bytes.Buffer
+	for _, a := range actions {
+		raw.WriteString(strconv.FormatInt(a.W, 10))
+		raw.WriteByte('|')
+		raw.WriteString(strconv.FormatFloat(a.X, 'f', -1, 64))
+		raw.WriteByte('|')
+		raw.WriteString(strconv.FormatFloat(a.Y, 'f', -1, 64))
+		raw.WriteByte('|')
+		raw.WriteString(strconv.FormatInt(a.Z, 10))
+		raw.WriteByte(',') // The stream ends with separator too.
+	}
+
+	var b bytes.Buffer
+	cfg := lzma.WriterConfig{Size: int64(raw.Len())}
+	w, err := cfg.NewWriter(&b)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := w.Write(raw.Bytes()); err != nil {
+		return nil, err
+	}
+	if err := w.Close(); err != nil {
+		return nil, err
+	}
+	return b.Bytes(), nil
+}
+
+// Time stamp is in Windows ticks: the number of 100 nanoseconds since 0001-01-01.
+const unixEpochTicks = 621355968000000000
+
+func NewTimeStamp(t time.Time) int64 { return t.UnixNano()/100 + unixEpochTicks }
+
+// Time returns the time when the replay was played.
+func (f Format) Time() time.Time { return time.Unix(0, (f.TimeStamp-unixEpochTicks)*100) }
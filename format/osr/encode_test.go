@@ -0,0 +1,57 @@
+package osr
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestEncode(t *testing.T) {
+	paths, err := filepath.Glob("../../cmd/gosu/replay/*.osr")
+	if err != nil {
+		t.Fatal(err)
+	}
+	paths = append(paths, "test.osr")
+	for _, path := range paths {
+		dat, err := os.ReadFile(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		want, err := Parse(dat)
+		if err != nil {
+			t.Fatalf("%s: %s", path, err)
+		}
+		dat2, err := want.Encode()
+		if err != nil {
+			t.Fatalf("%s: %s", path, err)
+		}
+		got, err := Parse(dat2)
+		if err != nil {
+			t.Fatalf("%s: %s", path, err)
+		}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("%s: round trip failed", path)
+		}
+	}
+}
+
+func TestSeedAction(t *testing.T) {
+	want := Action{W: SeedActionTime, X: 1.35, Y: 2, Z: 1234567890123}
+	f := Format{ReplayData: []Action{{W: 10, X: 1}, want}}
+	dat, err := f.Encode()
+	if err != nil {
+		t.Fatal(err)
+	}
+	f2, err := Parse(dat)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, ok := f2.SeedAction()
+	if !ok || got != want {
+		t.Errorf("got %v, want %v", got, want)
+	}
+	if seed, _ := f2.Seed(); seed != want.Z {
+		t.Errorf("seed: got %d, want %d", seed, want.Z)
+	}
+}
@@ -0,0 +1,38 @@
+package osr
+
+// Bits of ModsBits. Only mods supported by gosu are listed.
+// https://github.com/ppy/osu-api/wiki#mods
+const (
+	ModNoFail      = 1 << 0
+	ModEasy        = 1 << 1
+	ModHidden      = 1 << 3
+	ModSuddenDeath = 1 << 5
+	ModDoubleTime  = 1 << 6
+	ModHalfTime    = 1 << 8
+	ModNightcore   = 1 << 9 // Always set with DoubleTime.
+	ModFlashlight  = 1 << 10
+	ModAutoplay    = 1 << 11
+	ModPerfect     = 1 << 14 // Always set with SuddenDeath.
+	ModFadeIn      = 1 << 20
+	ModRandom      = 1 << 21
+	ModMirror      = 1 << 30
+)
+
+// Seed returns the random seed written at the last action.
+func (f Format) Seed() (int64, bool) {
+	a, ok := f.SeedAction()
+	return a.Z, ok
+}
+
+// SeedAction returns the dummy action at the end, which has the random seed at Z.
+// X and Y of the action may hold values which mods bits cannot.
+func (f Format) SeedAction() (Action, bool) {
+	if len(f.ReplayData) == 0 {
+		return Action{}, false
+	}
+	a := f.ReplayData[len(f.ReplayData)-1]
+	if a.W != SeedActionTime {
+		return Action{}, false
+	}
+	return a, true
+}
@@ -0,0 +1,151 @@
+package osu
+
+import "math"
+
+// Easings are in order of their numeric values in storyboard commands.
+const (
+	EasingLinear = iota
+	EasingOut
+	EasingIn
+	EasingInQuad
+	EasingOutQuad
+	EasingInOutQuad
+	EasingInCubic
+	EasingOutCubic
+	EasingInOutCubic
+	EasingInQuart
+	EasingOutQuart
+	EasingInOutQuart
+	EasingInQuint
+	EasingOutQuint
+	EasingInOutQuint
+	EasingInSine
+	EasingOutSine
+	EasingInOutSine
+	EasingInExpo
+	EasingOutExpo
+	EasingInOutExpo
+	EasingInCirc
+	EasingOutCirc
+	EasingInOutCirc
+	EasingInElastic
+	EasingOutElastic
+	EasingOutElasticHalf
+	EasingOutElasticQuarter
+	EasingInOutElastic
+	EasingInBack
+	EasingOutBack
+	EasingInOutBack
+	EasingInBounce
+	EasingOutBounce
+	EasingInOutBounce
+)
+
+// Ease maps progress x in [0, 1] to eased progress.
+// Unknown easing falls back to linear.
+func Ease(easing int, x float64) float64 {
+	switch easing {
+	case EasingOut, EasingOutQuad:
+		return reverse(inQuad, x)
+	case EasingIn, EasingInQuad:
+		return inQuad(x)
+	case EasingInOutQuad:
+		return toInOut(inQuad, x)
+	case EasingInCubic:
+		return inCubic(x)
+	case EasingOutCubic:
+		return reverse(inCubic, x)
+	case EasingInOutCubic:
+		return toInOut(inCubic, x)
+	case EasingInQuart:
+		return inQuart(x)
+	case EasingOutQuart:
+		return reverse(inQuart, x)
+	case EasingInOutQuart:
+		return toInOut(inQuart, x)
+	case EasingInQuint:
+		return inQuint(x)
+	case EasingOutQuint:
+		return reverse(inQuint, x)
+	case EasingInOutQuint:
+		return toInOut(inQuint, x)
+	case EasingInSine:
+		return inSine(x)
+	case EasingOutSine:
+		return reverse(inSine, x)
+	case EasingInOutSine:
+		return toInOut(inSine, x)
+	case EasingInExpo:
+		return inExpo(x)
+	case EasingOutExpo:
+		return reverse(inExpo, x)
+	case EasingInOutExpo:
+		return toInOut(inExpo, x)
+	case EasingInCirc:
+		return inCirc(x)
+	case EasingOutCirc:
+		return reverse(inCirc, x)
+	case EasingInOutCirc:
+		return toInOut(inCirc, x)
+	case EasingInElastic:
+		return reverse(outElastic, x)
+	case EasingOutElastic:
+		return outElastic(x)
+	case EasingOutElasticHalf:
+		return math.Pow(2, -10*x)*math.Sin((0.5*x-0.075)*2*math.Pi/0.3) + 1
+	case EasingOutElasticQuarter:
+		return math.Pow(2, -10*x)*math.Sin((0.25*x-0.075)*2*math.Pi/0.3) + 1
+	case EasingInOutElastic:
+		return toInOut(func(x float64) float64 { return reverse(outElastic, x) }, x)
+	case EasingInBack:
+		return inBack(x)
+	case EasingOutBack:
+		return reverse(inBack, x)
+	case EasingInOutBack:
+		const s = 1.70158 * 1.525
+		return toInOut(func(x float64) float64 { return x * x * ((s+1)*x - s) }, x)
+	case EasingInBounce:
+		return reverse(outBounce, x)
+	case EasingOutBounce:
+		return outBounce(x)
+	case EasingInOutBounce:
+		return toInOut(func(x float64) float64 { return reverse(outBounce, x) }, x)
+	default:
+		return x
+	}
+}
+
+func reverse(f func(float64) float64, x float64) float64 { return 1 - f(1-x) }
+func toInOut(f func(float64) float64, x float64) float64 {
+	if x < 0.5 {
+		return 0.5 * f(2*x)
+	}
+	return 0.5 * (2 - f(2-2*x))
+}
+
+func inQuad(x float64) float64  { return x * x }
+func inCubic(x float64) float64 { return x * x * x }
+func inQuart(x float64) float64 { return x * x * x * x }
+func inQuint(x float64) float64 { return x * x * x * x * x }
+func inSine(x float64) float64  { return 1 - math.Cos(x*math.Pi/2) }
+func inExpo(x float64) float64  { return math.Pow(2, 10*(x-1)) }
+func inCirc(x float64) float64  { return 1 - math.Sqrt(1-x*x) }
+func inBack(x float64) float64  { return x * x * ((1.70158+1)*x - 1.70158) }
+func outElastic(x float64) float64 {
+	return math.Pow(2, -10*x)*math.Sin((x-0.075)*2*math.Pi/0.3) + 1
+}
+func outBounce(x float64) float64 {
+	switch {
+	case x < 1/2.75:
+		return 7.5625 * x * x
+	case x < 2/2.75:
+		x -= 1.5 / 2.75
+		return 7.5625*x*x + 0.75
+	case x < 2.5/2.75:
+		x -= 2.25 / 2.75
+		return 7.5625*x*x + 0.9375
+	default:
+		x -= 2.625 / 2.75
+		return 7.5625*x*x + 0.984375
+	}
+}
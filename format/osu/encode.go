@@ -0,0 +1,219 @@
+package osu
+
+import (
+	"bytes"
+	"fmt"
+	"image/color"
+	"io"
+	"strconv"
+	"strings"
+)
+
+const (
+	formatVersionPrefix = "osu file format v"
+	// Encode always writes in the latest format version.
+	FormatVersionLatest = 14
+)
+
+// Encode writes the Format in .osu format v14.
+// Parse(Encode()) results in the same Format except FormatVersion.
+func (o Format) Encode() []byte {
+	var b bytes.Buffer
+	fmt.Fprintf(&b, "%s%d\r\n", formatVersionPrefix, FormatVersionLatest)
+	o.encodeGeneral(&b)
+	o.encodeEditor(&b)
+	o.encodeMetadata(&b)
+	o.encodeDifficulty(&b)
+	o.encodeEvents(&b)
+	o.encodeTimingPoints(&b)
+	o.encodeColours(&b)
+	o.encodeHitObjects(&b)
+	return b.Bytes()
+}
+
+// WriteTo implements io.WriterTo.
+func (o Format) WriteTo(w io.Writer) (int64, error) {
+	n, err := w.Write(o.Encode())
+	return int64(n), err
+}
+
+func formatFloat(f float64) string { return strconv.FormatFloat(f, 'f', -1, 64) }
+func formatBool(b bool) string {
+	if b {
+		return "1"
+	}
+	return "0"
+}
+func formatInts(vs []int, sep string) string {
+	ss := make([]string, len(vs))
+	for i, v := range vs {
+		ss[i] = strconv.Itoa(v)
+	}
+	return strings.Join(ss, sep)
+}
+
+func (o Format) encodeGeneral(b *bytes.Buffer) {
+	g := o.General
+	b.WriteString("\r\n[General]\r\n")
+	fmt.Fprintf(b, "AudioFilename: %s\r\n", g.AudioFilename)
+	fmt.Fprintf(b, "AudioLeadIn: %d\r\n", g.AudioLeadIn)
+	if g.AudioHash != "" {
+		fmt.Fprintf(b, "AudioHash: %s\r\n", g.AudioHash)
+	}
+	fmt.Fprintf(b, "PreviewTime: %d\r\n", g.PreviewTime)
+	fmt.Fprintf(b, "Countdown: %d\r\n", g.Countdown)
+	fmt.Fprintf(b, "SampleSet: %s\r\n", g.SampleSet)
+	fmt.Fprintf(b, "StackLeniency: %s\r\n", formatFloat(g.StackLeniency))
+	fmt.Fprintf(b, "Mode: %d\r\n", g.Mode)
+	fmt.Fprintf(b, "LetterboxInBreaks: %s\r\n", formatBool(g.LetterboxInBreaks))
+	fmt.Fprintf(b, "StoryFireInFront: %s\r\n", formatBool(g.StoryFireInFront))
+	fmt.Fprintf(b, "UseSkinSprites: %s\r\n", formatBool(g.UseSkinSprites))
+	fmt.Fprintf(b, "AlwaysShowPlayfield: %s\r\n", formatBool(g.AlwaysShowPlayfield))
+	fmt.Fprintf(b, "OverlayPosition: %s\r\n", g.OverlayPosition)
+	if g.SkinPreference != "" {
+		fmt.Fprintf(b, "SkinPreference: %s\r\n", g.SkinPreference)
+	}
+	fmt.Fprintf(b, "EpilepsyWarning: %s\r\n", formatBool(g.EpilepsyWarning))
+	fmt.Fprintf(b, "CountdownOffset: %d\r\n", g.CountdownOffset)
+	fmt.Fprintf(b, "SpecialStyle: %s\r\n", formatBool(g.SpecialStyle))
+	fmt.Fprintf(b, "WidescreenStoryboard: %s\r\n", formatBool(g.WidescreenStoryboard))
+	fmt.Fprintf(b, "SamplesMatchPlaybackRate: %s\r\n", formatBool(g.SamplesMatchPlaybackRate))
+}
+
+func (o Format) encodeEditor(b *bytes.Buffer) {
+	e := o.Editor
+	b.WriteString("\r\n[Editor]\r\n")
+	if e.Bookmarks != nil {
+		fmt.Fprintf(b, "Bookmarks: %s\r\n", formatInts(e.Bookmarks, ","))
+	}
+	fmt.Fprintf(b, "DistanceSpacing: %s\r\n", formatFloat(e.DistanceSpacing))
+	fmt.Fprintf(b, "BeatDivisor: %s\r\n", formatFloat(e.BeatDivisor))
+	fmt.Fprintf(b, "GridSize: %d\r\n", e.GridSize)
+	fmt.Fprintf(b, "TimelineZoom: %s\r\n", formatFloat(e.TimelineZoom))
+}
+
+// Metadata and Difficulty have no space after colon.
+func (o Format) encodeMetadata(b *bytes.Buffer) {
+	m := o.Metadata
+	b.WriteString("\r\n[Metadata]\r\n")
+	fmt.Fprintf(b, "Title:%s\r\n", m.Title)
+	fmt.Fprintf(b, "TitleUnicode:%s\r\n", m.TitleUnicode)
+	fmt.Fprintf(b, "Artist:%s\r\n", m.Artist)
+	fmt.Fprintf(b, "ArtistUnicode:%s\r\n", m.ArtistUnicode)
+	fmt.Fprintf(b, "Creator:%s\r\n", m.Creator)
+	fmt.Fprintf(b, "Version:%s\r\n", m.Version)
+	fmt.Fprintf(b, "Source:%s\r\n", m.Source)
+	if m.Tags != nil {
+		fmt.Fprintf(b, "Tags:%s\r\n", strings.Join(m.Tags, " "))
+	}
+	fmt.Fprintf(b, "BeatmapID:%d\r\n", m.BeatmapID)
+	fmt.Fprintf(b, "BeatmapSetID:%d\r\n", m.BeatmapSetID)
+}
+
+func (o Format) encodeDifficulty(b *bytes.Buffer) {
+	d := o.Difficulty
+	b.WriteString("\r\n[Difficulty]\r\n")
+	fmt.Fprintf(b, "HPDrainRate:%s\r\n", formatFloat(d.HPDrainRate))
+	fmt.Fprintf(b, "CircleSize:%s\r\n", formatFloat(d.CircleSize))
+	fmt.Fprintf(b, "OverallDifficulty:%s\r\n", formatFloat(d.OverallDifficulty))
+	fmt.Fprintf(b, "ApproachRate:%s\r\n", formatFloat(d.ApproachRate))
+	fmt.Fprintf(b, "SliderMultiplier:%s\r\n", formatFloat(d.SliderMultiplier))
+	fmt.Fprintf(b, "SliderTickRate:%s\r\n", formatFloat(d.SliderTickRate))
+}
+
+func (o Format) encodeEvents(b *bytes.Buffer) {
+	b.WriteString("\r\n[Events]\r\n")
+	// Events are written in the order as they are,
+	// since storyboard commands follow their objects.
+	for _, e := range o.Events {
+		switch e.Type {
+		case "Background":
+			fmt.Fprintf(b, "0,%d,\"%s\",%d,%d\r\n", e.StartTime, e.Filename, e.XOffset, e.YOffset)
+		case "Video":
+			fmt.Fprintf(b, "Video,%d,\"%s\",%d,%d\r\n", e.StartTime, e.Filename, e.XOffset, e.YOffset)
+		case "Break":
+			fmt.Fprintf(b, "2,%d,%d\r\n", e.StartTime, e.EndTime)
+		default:
+			fmt.Fprintf(b, "%s\r\n", e.Raw)
+		}
+	}
+}
+
+func (o Format) encodeTimingPoints(b *bytes.Buffer) {
+	b.WriteString("\r\n[TimingPoints]\r\n")
+	for _, tp := range o.TimingPoints {
+		fmt.Fprintf(b, "%d,%s,%d,%d,%d,%d,%s,%d\r\n", tp.Time, formatFloat(tp.BeatLength),
+			tp.Meter, tp.SampleSet, tp.SampleIndex, tp.Volume, formatBool(tp.Uninherited), tp.Effects)
+	}
+}
+
+// Colours with zero alpha value are regarded as not set.
+func (o Format) encodeColours(b *bytes.Buffer) {
+	c := o.Colours
+	b.WriteString("\r\n[Colours]\r\n")
+	for i, rgb := range c.Combos {
+		if rgb.A != 0 {
+			fmt.Fprintf(b, "Combo%d : %s\r\n", i+1, formatRGB(rgb))
+		}
+	}
+	if c.SliderTrackOverride.A != 0 {
+		fmt.Fprintf(b, "SliderTrackOverride : %s\r\n", formatRGB(c.SliderTrackOverride))
+	}
+	if c.SliderBorder.A != 0 {
+		fmt.Fprintf(b, "SliderBorder : %s\r\n", formatRGB(c.SliderBorder))
+	}
+}
+
+func formatRGB(rgb color.RGBA) string { return fmt.Sprintf("%d,%d,%d", rgb.R, rgb.G, rgb.B) }
+
+func (o Format) encodeHitObjects(b *bytes.Buffer) {
+	b.WriteString("\r\n[HitObjects]\r\n")
+	for _, ho := range o.HitObjects {
+		b.WriteString(ho.String())
+		b.WriteString("\r\n")
+	}
+}
+
+// String returns the hit object in a line of .osu format.
+func (ho HitObject) String() string {
+	s := fmt.Sprintf("%d,%d,%d,%d,%d", ho.X, ho.Y, ho.Time, ho.NoteType, ho.HitSound)
+	switch ho.NoteType & ComboMask {
+	case HitTypeSlider:
+		return s + "," + ho.SliderParams.String() + "," + ho.HitSample.String()
+	case HitTypeSpinner:
+		return fmt.Sprintf("%s,%d,%s", s, ho.EndTime, ho.HitSample.String())
+	case HitTypeHoldNote:
+		return fmt.Sprintf("%s,%d:%s", s, ho.EndTime, ho.HitSample.String())
+	default:
+		return s + "," + ho.HitSample.String()
+	}
+}
+
+// Edge sounds and edge sets are always written, since hit sample follows them.
+// Default values are written for each edge when not set.
+func (sp SliderParams) String() string {
+	ps := make([]string, 0, len(sp.CurvePoints)+1)
+	ps = append(ps, sp.CurveType)
+	for _, p := range sp.CurvePoints {
+		ps = append(ps, fmt.Sprintf("%d:%d", p[0], p[1]))
+	}
+	s := fmt.Sprintf("%s,%d,%s", strings.Join(ps, "|"), sp.Slides, formatFloat(sp.Length))
+	edges := sp.Slides + 1
+	sounds := sp.EdgeSounds
+	if len(sounds) == 0 {
+		sounds = make([]int, edges)
+	}
+	setPairs := sp.EdgeSets
+	if len(setPairs) == 0 {
+		setPairs = make([][2]int, edges)
+	}
+	sets := make([]string, len(setPairs))
+	for i, set := range setPairs {
+		sets[i] = fmt.Sprintf("%d:%d", set[0], set[1])
+	}
+	return fmt.Sprintf("%s,%s,%s", s, formatInts(sounds, "|"), strings.Join(sets, "|"))
+}
+
+func (hs HitSample) String() string {
+	return fmt.Sprintf("%d:%d:%d:%d:%s", hs.NormalSet, hs.AdditionSet, hs.Index, hs.Volume, hs.Filename)
+}
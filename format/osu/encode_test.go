@@ -0,0 +1,115 @@
+package osu
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestEncode(t *testing.T) {
+	paths, err := filepath.Glob("../../cmd/gosu/music/*/*.osu")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(paths) == 0 {
+		t.Skip("no charts to test")
+	}
+	for _, path := range paths {
+		dat, err := os.ReadFile(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		want, err := Parse(dat)
+		if err != nil {
+			t.Fatalf("%s: %s", path, err)
+		}
+		got, err := Parse(want.Encode())
+		if err != nil {
+			t.Fatalf("%s: %s", path, err)
+		}
+		got.FormatVersion = want.FormatVersion
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("%s: round trip failed:\n got: %+v\nwant: %+v", path, got, want)
+		}
+	}
+}
+
+// Storyboard events are kept in order with their indentation.
+func TestEncodeEvents(t *testing.T) {
+	const events = "0,0,\"bg.jpg\",0,0\r\n" +
+		"Video,100,\"bga.mp4\",0,0\r\n" +
+		"Sprite,Foreground,Centre,\"sb\\star.png\",320,240\r\n" +
+		" F,0,0,1000,0,1\r\n" +
+		" L,500,2\r\n" +
+		"  S,0,0,100,1,2\r\n" +
+		"_C,0,0,100,255,0,0\r\n" +
+		"2,1000,3000\r\n" +
+		"Animation,Background,TopLeft,\"a.png\",0,0,3,100,LoopOnce\r\n" +
+		" P,0,0,,A\r\n" +
+		"Sample,2000,0,\"clap.wav\",70\r\n" +
+		"3,100,163,162,255\r\n"
+	want, err := Parse([]byte("osu file format v14\r\n\r\n[Events]\r\n" +
+		"//Storyboard Layer 0 (Background)\r\n" + events))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(want.Events) != 12 {
+		t.Fatalf("%d events, want 12", len(want.Events))
+	}
+	dat := want.Encode()
+	if !strings.Contains(string(dat), "[Events]\r\n"+events) {
+		t.Errorf("events not kept:\n%s", dat)
+	}
+	got, err := Parse(dat)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(got.Events, want.Events) {
+		t.Errorf("round trip failed:\n got: %+v\nwant: %+v", got.Events, want.Events)
+	}
+	sb, err := ParseStoryboard(dat)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(sb.Objects) != 2 || len(sb.Objects[0].Commands) != 3 {
+		t.Errorf("storyboard: %+v", sb.Objects)
+	}
+}
+
+func TestHitObjectString(t *testing.T) {
+	for _, tc := range []struct {
+		name string
+		line string
+		want string
+	}{
+		{"note", "256,192,500,1,2,0:1:2:70:normal-hitclap.wav", "256,192,500,1,2,0:1:2:70:normal-hitclap.wav"},
+		{"hold note", "64,192,500,128,0,1000:1:0:0:0:", "64,192,500,128,0,1000:1:0:0:0:"},
+		{"spinner", "256,192,500,12,0,3000,0:0:0:0:", "256,192,500,12,0,3000,0:0:0:0:"},
+		{"slider with edges", "100,100,1000,2,0,B|200:200|250:200,2,150.5,2|0|8,0:0|1:2|0:0,1:2:3:40:clap.wav",
+			"100,100,1000,2,0,B|200:200|250:200,2,150.5,2|0|8,0:0|1:2|0:0,1:2:3:40:clap.wav"},
+		{"slider without edges", "100,100,1000,6,0,L|200:100,1,100,2:1:0:0:",
+			"100,100,1000,6,0,L|200:100,1,100,0|0,0:0|0:0,2:1:0:0:"},
+		{"slider without hit sample", "100,100,1000,2,0,P|150:150|200:100,1,120",
+			"100,100,1000,2,0,P|150:150|200:100,1,120,0|0,0:0|0:0,0:0:0:0:"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			ho, err := newHitObject(tc.line)
+			if err != nil {
+				t.Fatal(err)
+			}
+			got := ho.String()
+			if got != tc.want {
+				t.Errorf("got %s, want %s", got, tc.want)
+			}
+			ho2, err := newHitObject(got)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if ho2.String() != got {
+				t.Errorf("round trip: got %s, want %s", ho2.String(), got)
+			}
+		})
+	}
+}
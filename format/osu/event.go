@@ -2,11 +2,13 @@ package osu
 
 import (
 	"errors"
+	"fmt"
 	"strconv"
 	"strings"
 )
 
-// storyboard not implemented yet
+// Storyboard objects are parsed by ParseStoryboard.
+// Lines of events other than Background, Video and Break are kept in Raw.
 type Event struct { // delimiter,
 	Type      string
 	StartTime int
@@ -14,6 +16,7 @@ type Event struct { // delimiter,
 	Filename  string
 	XOffset   int
 	YOffset   int
+	Raw       string
 }
 
 func newEvent(line string) (Event, error) {
@@ -26,6 +29,8 @@ func newEvent(line string) (Event, error) {
 		e.Type = "Video"
 	case "2", "Break":
 		e.Type = "Break"
+	default: // Storyboard objects and commands are parsed by ParseStoryboard.
+		return e, fmt.Errorf("invalid event: unknown type %s", vs[0])
 	}
 	switch e.Type {
 	case "Background", "Video":
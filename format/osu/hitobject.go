@@ -277,16 +277,6 @@ var HitSounds = []string{"normal", "whistle", "finish", "clap"}
 var SampleSets = []string{"x", "normal", "soft", "drum"}
 var YetDetermined = "?"
 
-// Default sound sample would not even used by most users.
-// func (h HitObject) SampleFilename() string {
-// 	hs := h.HitSample
-// 	set := hs.NormalSet
-// 	if h.HitSound >= 2 {
-// 		set = hs.AdditionSet
-// 	}
-// 	return fmt.Sprintf("%s-hit%s%d.wav", SampleSets[set], HitSounds[h.HitSound], hs.Index)
-// }
-
 const (
 	TaikoKatMask = HitSoundWhistle | HitSoundClap
 	TaikoBigMask = HitSoundFinish
@@ -0,0 +1,120 @@
+package osu
+
+import (
+	"fmt"
+	"strings"
+)
+
+const (
+	SampleSetAuto = iota // Follows timing point, or the default of beatmap.
+	SampleSetNormal
+	SampleSetSoft
+	SampleSetDrum
+)
+
+// SampleLeniency is how late a timing point may be than a hit object
+// and still affect the hit object's samples.
+const SampleLeniency = 5
+
+// HitSoundSample is a sample resolved from a hit object.
+// Filename is a file in the beatmap folder. It is empty when the sample
+// is skin only: it happens when sample index is 0.
+// Default is a file in the skin, played when Filename is not found.
+// Custom filename of hit sample has no default.
+type HitSoundSample struct {
+	Filename string
+	Default  string
+	Volume   int // In percent.
+}
+
+// HitSoundSamples returns samples of the hit object by osu! rules.
+// Normal sound is always played. Whistle, finish and clap are added by
+// hit sound flags, with addition set. Zero values of hit sample follow
+// the timing point at the time.
+// https://osu.ppy.sh/wiki/en/Client/File_formats/osu_%28file_format%29#hitsounds
+func (f Format) HitSoundSamples(ho HitObject) []HitSoundSample {
+	hs := ho.HitSample
+	tp, ok := f.TimingPoints.SampleAt(ho.Time)
+	if !ok {
+		tp = TimingPoint{SampleIndex: 1, Volume: 100}
+	}
+
+	volume := hs.Volume
+	if volume == 0 {
+		volume = tp.Volume
+	}
+	if volume == 0 {
+		volume = 100
+	}
+	if hs.Filename != "" {
+		return []HitSoundSample{{Filename: hs.Filename, Volume: volume}}
+	}
+
+	normalSet := hs.NormalSet
+	if normalSet == SampleSetAuto {
+		normalSet = tp.SampleSet
+	}
+	if normalSet == SampleSetAuto {
+		normalSet = f.General.DefaultSampleSet()
+	}
+	additionSet := hs.AdditionSet
+	if additionSet == SampleSetAuto {
+		additionSet = normalSet
+	}
+	index := hs.Index
+	if index == 0 {
+		index = tp.SampleIndex
+	}
+
+	ss := make([]HitSoundSample, 0, len(HitSounds))
+	for i, name := range HitSounds {
+		if i > 0 && ho.HitSound&(1<<i) == 0 {
+			continue
+		}
+		set := normalSet
+		if i > 0 {
+			set = additionSet
+		}
+		base := fmt.Sprintf("%s-hit%s", SampleSets[set], name)
+		s := HitSoundSample{
+			Default: base + ".wav",
+			Volume:  volume,
+		}
+		switch {
+		case index == 1:
+			s.Filename = base + ".wav"
+		case index > 1:
+			s.Filename = fmt.Sprintf("%s%d.wav", base, index)
+		}
+		ss = append(ss, s)
+	}
+	return ss
+}
+
+// SampleAt returns the latest timing point at the time, regardless of
+// whether it is uninherited. It returns false when there is none.
+func (tps TimingPoints) SampleAt(time int) (TimingPoint, bool) {
+	i := -1
+	for j, tp := range tps {
+		if tp.Time > time+SampleLeniency {
+			continue
+		}
+		if i == -1 || tp.Time >= tps[i].Time {
+			i = j
+		}
+	}
+	if i == -1 {
+		return TimingPoint{}, false
+	}
+	return tps[i], true
+}
+
+// DefaultSampleSet returns the sample set of General section.
+func (g General) DefaultSampleSet() int {
+	for set, name := range SampleSets {
+		if set != SampleSetAuto && strings.EqualFold(g.SampleSet, name) {
+			return set
+		}
+	}
+	return SampleSetNormal
+}
@@ -0,0 +1,46 @@
+package osu
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestHitSoundSamples(t *testing.T) {
+	f := Format{
+		General: General{SampleSet: "Soft"},
+		TimingPoints: TimingPoints{
+			{Time: 0, BeatLength: 500, SampleSet: SampleSetAuto, SampleIndex: 1, Volume: 80, Uninherited: true},
+			{Time: 1003, BeatLength: -100, SampleSet: SampleSetDrum, SampleIndex: 2, Volume: 60}, // Within leniency.
+			{Time: 2000, BeatLength: -100, SampleSet: SampleSetNormal, SampleIndex: 0, Volume: 40},
+		},
+	}
+	for _, tc := range []struct {
+		name string
+		ho   HitObject
+		want []HitSoundSample
+	}{
+		{"default set", HitObject{Time: 500}, []HitSoundSample{
+			{"soft-hitnormal.wav", "soft-hitnormal.wav", 80},
+		}},
+		{"additions", HitObject{Time: 1000, HitSound: HitSoundWhistle | HitSoundClap,
+			HitSample: HitSample{AdditionSet: SampleSetNormal}}, []HitSoundSample{
+			{"drum-hitnormal2.wav", "drum-hitnormal.wav", 60},
+			{"normal-hitwhistle2.wav", "normal-hitwhistle.wav", 60},
+			{"normal-hitclap2.wav", "normal-hitclap.wav", 60},
+		}},
+		{"skin only", HitObject{Time: 2000, HitSound: HitSoundFinish}, []HitSoundSample{
+			{"", "normal-hitnormal.wav", 40},
+			{"", "normal-hitfinish.wav", 40},
+		}},
+		{"hit sample", HitObject{Time: 2000, HitSample: HitSample{NormalSet: SampleSetSoft, Index: 3, Volume: 70}}, []HitSoundSample{
+			{"soft-hitnormal3.wav", "soft-hitnormal.wav", 70},
+		}},
+		{"custom filename", HitObject{Time: 2000, HitSound: HitSoundClap, HitSample: HitSample{Filename: "kick.wav"}}, []HitSoundSample{
+			{"kick.wav", "", 40},
+		}},
+	} {
+		if got := f.HitSoundSamples(tc.ho); !reflect.DeepEqual(got, tc.want) {
+			t.Errorf("%s: got %v, want %v", tc.name, got, tc.want)
+		}
+	}
+}
@@ -29,11 +29,20 @@ func Parse(dat []byte) (*Format, error) {
 
 	var section string
 	for _, l := range bytes.Split(dat, []byte("\n")) {
+		// Storyboard commands are indented with spaces or underscores.
+		raw := string(l)
 		l = bytes.TrimLeftFunc(l, unicode.IsSpace) // prevent trimming delimiter
 		line := string(l)
 		if isPass(line) {
 			continue
 		}
+		if v := strings.TrimPrefix(line, "\ufeff"); section == "" && strings.HasPrefix(v, formatVersionPrefix) {
+			v = strings.TrimRightFunc(v[len(formatVersionPrefix):], unicode.IsSpace)
+			if i, err := strconv.Atoi(v); err == nil {
+				o.FormatVersion = i
+			}
+			continue
+		}
 		if isSection(line) {
 			section = strings.Trim(line, "[]")
 			continue
@@ -271,8 +280,8 @@ func Parse(dat []byte) (*Format, error) {
 		case "Events":
 			e, err := newEvent(line)
 			if err != nil {
-				// return o, fmt.Errorf("error at %s: %s", line, err)
-				continue
+				// Storyboard objects, commands and others are kept as they are.
+				e = Event{Raw: raw}
 			}
 			o.Events = append(o.Events, e)
 		case "TimingPoints":
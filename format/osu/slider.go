@@ -0,0 +1,402 @@
+package osu
+
+import "math"
+
+const (
+	CurveBezier  = "B"
+	CurveCatmull = "C"
+	CurveLinear  = "L"
+	CurvePerfect = "P"
+)
+
+// Tolerances are in osu! pixels, same as osu!'s.
+const (
+	bezierTolerance   = 0.25
+	circularTolerance = 0.1
+	catmullDetail     = 50
+)
+
+type Vector2 [2]float64
+
+func (v Vector2) Add(w Vector2) Vector2             { return Vector2{v[0] + w[0], v[1] + w[1]} }
+func (v Vector2) Sub(w Vector2) Vector2             { return Vector2{v[0] - w[0], v[1] - w[1]} }
+func (v Vector2) Scale(k float64) Vector2           { return Vector2{v[0] * k, v[1] * k} }
+func (v Vector2) Len() float64                      { return math.Hypot(v[0], v[1]) }
+func (v Vector2) Lerp(w Vector2, t float64) Vector2 { return v.Add(w.Sub(v).Scale(t)) }
+
+// SliderPath is a slider curve approximated by vertices.
+// Positions are parameterized by arc length from the head.
+type SliderPath struct {
+	Vertices []Vector2
+	Lengths  []float64 // Cumulative arc lengths at each vertex.
+}
+
+// Path returns the curve of a slider, whose length is fit to the stated length.
+// The head of slider is the first control point.
+func (h HitObject) Path() SliderPath {
+	sp := h.SliderParams
+	cps := make([]Vector2, 0, len(sp.CurvePoints)+1)
+	cps = append(cps, Vector2{float64(h.X), float64(h.Y)})
+	for _, p := range sp.CurvePoints {
+		cps = append(cps, Vector2{float64(p[0]), float64(p[1])})
+	}
+	var vs []Vector2
+	switch sp.CurveType {
+	case CurveLinear:
+		vs = cps
+	case CurvePerfect:
+		if len(cps) == 3 {
+			var ok bool
+			if vs, ok = circularArc(cps); ok {
+				break
+			}
+		}
+		vs = bezierPath(cps)
+	case CurveCatmull:
+		vs = catmullPath(cps)
+	default:
+		vs = bezierPath(cps)
+	}
+	return newSliderPath(vs, sp.Length)
+}
+
+// newSliderPath cuts or extends the path to the expected length.
+// The path remains as it is when the expected length is not positive.
+func newSliderPath(vs []Vector2, expected float64) SliderPath {
+	p := SliderPath{
+		Vertices: make([]Vector2, 0, len(vs)),
+		Lengths:  make([]float64, 0, len(vs)),
+	}
+	var sum float64
+	for i, v := range vs {
+		if i > 0 {
+			d := v.Sub(vs[i-1]).Len()
+			if expected > 0 && sum+d > expected { // Cut the last segment.
+				v = vs[i-1].Lerp(v, (expected-sum)/d)
+				d = expected - sum
+			}
+			sum += d
+		}
+		p.Vertices = append(p.Vertices, v)
+		p.Lengths = append(p.Lengths, sum)
+		if expected > 0 && sum >= expected {
+			return p
+		}
+	}
+	// Extend the last segment.
+	if n := len(p.Vertices); expected > 0 && n >= 2 {
+		a, b := p.Vertices[n-2], p.Vertices[n-1]
+		if d := b.Sub(a).Len(); d > 0 {
+			p.Vertices[n-1] = a.Lerp(b, (expected-p.Lengths[n-2])/d)
+			p.Lengths[n-1] = expected
+		}
+	}
+	return p
+}
+
+// Length returns the arc length of the whole path.
+func (p SliderPath) Length() float64 {
+	if len(p.Lengths) == 0 {
+		return 0
+	}
+	return p.Lengths[len(p.Lengths)-1]
+}
+
+// PositionAt returns the position at the distance from the head.
+func (p SliderPath) PositionAt(d float64) Vector2 {
+	if len(p.Vertices) == 0 {
+		return Vector2{}
+	}
+	if d <= 0 {
+		return p.Vertices[0]
+	}
+	for i := 1; i < len(p.Vertices); i++ {
+		if d > p.Lengths[i] {
+			continue
+		}
+		seg := p.Lengths[i] - p.Lengths[i-1]
+		if seg == 0 {
+			return p.Vertices[i]
+		}
+		return p.Vertices[i-1].Lerp(p.Vertices[i], (d-p.Lengths[i-1])/seg)
+	}
+	return p.Vertices[len(p.Vertices)-1]
+}
+
+// Slider has timing of a slider, calculated with timing points and difficulty.
+// Times are in milliseconds from the start of the music.
+type Slider struct {
+	Path         SliderPath
+	Time         float64
+	Slides       int
+	Velocity     float64 // In osu! pixels per millisecond.
+	SpanDuration float64 // Duration of one slide.
+	Duration     float64 // Duration of all slides.
+	RepeatTimes  []float64
+	TickTimes    []float64 // Sorted. Head, repeats and tail are excluded.
+}
+
+// Slider returns false when the hit object is not a slider.
+func (f Format) Slider(h HitObject) (s Slider, ok bool) {
+	if h.NoteType&HitTypeSlider == 0 {
+		return
+	}
+	beatLength, speed := f.TimingPoints.At(h.Time)
+	s = Slider{
+		Path:   h.Path(),
+		Time:   float64(h.Time),
+		Slides: h.SliderParams.Slides,
+	}
+	if s.Slides < 1 {
+		s.Slides = 1
+	}
+	distance := f.SliderMultiplier * 100 * speed // Per beat.
+	if beatLength <= 0 || distance <= 0 {
+		return s, true
+	}
+	s.Velocity = distance / beatLength
+	s.SpanDuration = s.Path.Length() / s.Velocity
+	s.Duration = s.SpanDuration * float64(s.Slides)
+	for i := 1; i < s.Slides; i++ {
+		s.RepeatTimes = append(s.RepeatTimes, s.Time+s.SpanDuration*float64(i))
+	}
+
+	// Speed does not affect tick distance at old formats.
+	tickDistance := distance / f.SliderTickRate
+	if f.FormatVersion < 8 {
+		tickDistance /= speed
+	}
+	if f.SliderTickRate <= 0 || tickDistance <= 0 {
+		return s, true
+	}
+	length := s.Path.Length()
+	minDistanceFromEnd := s.Velocity * 10
+	for i := 0; i < s.Slides; i++ {
+		start := s.Time + s.SpanDuration*float64(i)
+		ticks := make([]float64, 0)
+		for d := tickDistance; d < length-minDistanceFromEnd; d += tickDistance {
+			ticks = append(ticks, d)
+		}
+		if i%2 == 1 { // Reversed slide.
+			for j, k := 0, len(ticks)-1; j < k; j, k = j+1, k-1 {
+				ticks[j], ticks[k] = ticks[k], ticks[j]
+			}
+			for j := range ticks {
+				ticks[j] = length - ticks[j]
+			}
+		}
+		for _, d := range ticks {
+			s.TickTimes = append(s.TickTimes, start+d/s.Velocity)
+		}
+	}
+	return s, true
+}
+
+// PositionAt returns the position of the slider ball at the time.
+func (s Slider) PositionAt(time float64) Vector2 {
+	if s.SpanDuration <= 0 || time <= s.Time {
+		return s.Path.PositionAt(0)
+	}
+	if time >= s.Time+s.Duration {
+		time = s.Time + s.Duration
+	}
+	span := int((time - s.Time) / s.SpanDuration)
+	progress := (time - s.Time - float64(span)*s.SpanDuration) / s.SpanDuration
+	if span >= s.Slides {
+		span, progress = s.Slides-1, 1
+	}
+	if span%2 == 1 {
+		progress = 1 - progress
+	}
+	return s.Path.PositionAt(progress * s.Path.Length())
+}
+
+// At returns beat length of the uninherited timing point and
+// speed of the inherited timing point which are in effect at the time.
+// The first uninherited timing point is used when the time is ahead of it.
+func (tps TimingPoints) At(time int) (beatLength, speed float64) {
+	speed = 1
+	var (
+		uninherited = -1
+		inherited   = -1
+	)
+	for i, tp := range tps {
+		if !tp.Uninherited || tp.Time > time {
+			continue
+		}
+		if uninherited == -1 || tp.Time >= tps[uninherited].Time {
+			uninherited = i
+		}
+	}
+	for i, tp := range tps { // Falls back to the first one.
+		if !tp.Uninherited || uninherited != -1 && tps[uninherited].Time <= time {
+			continue
+		}
+		if uninherited == -1 || tp.Time < tps[uninherited].Time {
+			uninherited = i
+		}
+	}
+	if uninherited == -1 {
+		return
+	}
+	beatLength = tps[uninherited].BeatLength
+	for i, tp := range tps {
+		if tp.Uninherited || tp.Time > time || tp.Time < tps[uninherited].Time {
+			continue
+		}
+		if inherited == -1 || tp.Time >= tps[inherited].Time {
+			inherited = i
+		}
+	}
+	if inherited != -1 {
+		speed = tps[inherited].BeatLengthScale()
+		if math.IsNaN(speed) {
+			speed = 1
+		}
+		speed = math.Max(0.1, math.Min(10, speed))
+	}
+	return
+}
+
+// bezierPath splits control points at red anchors, which are
+// duplicated consecutive points, then joins each Bezier curve.
+func bezierPath(cps []Vector2) []Vector2 {
+	var vs []Vector2
+	start := 0
+	for i := 1; i <= len(cps); i++ {
+		if i < len(cps) && cps[i] != cps[i-1] {
+			continue
+		}
+		seg := cps[start:i]
+		start = i
+		if len(seg) == 0 {
+			continue
+		}
+		if len(seg) == 1 {
+			vs = append(vs, seg[0])
+			continue
+		}
+		vs = append(vs, bezierCurve(seg)...)
+	}
+	return vs
+}
+
+// bezierCurve subdivides the curve until each piece is flat enough.
+// Each flat piece is approximated with its midpoint and end.
+func bezierCurve(cps []Vector2) []Vector2 {
+	vs := []Vector2{cps[0]}
+	var subdivide func(cps []Vector2, depth int)
+	subdivide = func(cps []Vector2, depth int) {
+		l, r := splitBezier(cps)
+		if depth >= 16 || isFlatEnough(cps) {
+			vs = append(vs, r[0], cps[len(cps)-1])
+			return
+		}
+		subdivide(l, depth+1)
+		subdivide(r, depth+1)
+	}
+	subdivide(cps, 0)
+	return vs
+}
+
+func isFlatEnough(cps []Vector2) bool {
+	for i := 1; i < len(cps)-1; i++ {
+		d := cps[i-1].Sub(cps[i].Scale(2)).Add(cps[i+1])
+		if d[0]*d[0]+d[1]*d[1] > bezierTolerance*bezierTolerance*4 {
+			return false
+		}
+	}
+	return true
+}
+
+// splitBezier splits the curve at the middle with de Casteljau's algorithm.
+func splitBezier(cps []Vector2) (l, r []Vector2) {
+	n := len(cps)
+	l = make([]Vector2, n)
+	r = make([]Vector2, n)
+	mid := make([]Vector2, n)
+	copy(mid, cps)
+	for i := 0; i < n; i++ {
+		l[i] = mid[0]
+		r[n-1-i] = mid[n-1-i]
+		for j := 0; j < n-1-i; j++ {
+			mid[j] = mid[j].Lerp(mid[j+1], 0.5)
+		}
+	}
+	return
+}
+
+// circularArc returns false when the points are on a line.
+func circularArc(cps []Vector2) ([]Vector2, bool) {
+	a, b, c := cps[0], cps[1], cps[2]
+	d := 2 * (a[0]*(b[1]-c[1]) + b[0]*(c[1]-a[1]) + c[0]*(a[1]-b[1]))
+	if math.Abs(d) < 1e-3 {
+		return nil, false
+	}
+	aa := a[0]*a[0] + a[1]*a[1]
+	bb := b[0]*b[0] + b[1]*b[1]
+	cc := c[0]*c[0] + c[1]*c[1]
+	center := Vector2{
+		(aa*(b[1]-c[1]) + bb*(c[1]-a[1]) + cc*(a[1]-b[1])) / d,
+		(aa*(c[0]-b[0]) + bb*(a[0]-c[0]) + cc*(b[0]-a[0])) / d,
+	}
+	radius := a.Sub(center).Len()
+	start := math.Atan2(a[1]-center[1], a[0]-center[0])
+	end := math.Atan2(c[1]-center[1], c[0]-center[0])
+	for end < start {
+		end += 2 * math.Pi
+	}
+	dir := 1.0
+	theta := end - start
+	// Goes the other way when b is at the right side of a to c.
+	if (c[0]-a[0])*(b[1]-a[1])-(c[1]-a[1])*(b[0]-a[0]) > 0 {
+		dir = -1
+		theta = 2*math.Pi - theta
+	}
+	n := 2
+	if 2*radius > circularTolerance {
+		step := 2 * math.Acos(1-circularTolerance/radius)
+		n = int(math.Max(2, math.Ceil(theta/step)))
+	}
+	vs := make([]Vector2, n)
+	for i := range vs {
+		angle := start + dir*float64(i)/float64(n-1)*theta
+		vs[i] = center.Add(Vector2{math.Cos(angle), math.Sin(angle)}.Scale(radius))
+	}
+	return vs, true
+}
+
+// catmullPath duplicates the first and the last point as phantom points.
+func catmullPath(cps []Vector2) []Vector2 {
+	if len(cps) < 2 {
+		return cps
+	}
+	vs := make([]Vector2, 0, (len(cps)-1)*catmullDetail+1)
+	for i := 0; i < len(cps)-1; i++ {
+		v1 := cps[i]
+		if i > 0 {
+			v1 = cps[i-1]
+		}
+		v2, v3 := cps[i], cps[i+1]
+		v4 := v3.Scale(2).Sub(v2)
+		if i < len(cps)-2 {
+			v4 = cps[i+2]
+		}
+		for c := 0; c < catmullDetail; c++ {
+			vs = append(vs, catmull(v1, v2, v3, v4, float64(c)/catmullDetail))
+		}
+	}
+	return append(vs, cps[len(cps)-1])
+}
+
+func catmull(v1, v2, v3, v4 Vector2, t float64) Vector2 {
+	t2, t3 := t*t, t*t*t
+	var v Vector2
+	for i := range v {
+		v[i] = 0.5 * (2*v2[i] +
+			(-v1[i]+v3[i])*t +
+			(2*v1[i]-5*v2[i]+4*v3[i]-v4[i])*t2 +
+			(-v1[i]+3*v2[i]-3*v3[i]+v4[i])*t3)
+	}
+	return v
+}
@@ -0,0 +1,84 @@
+package osu
+
+import (
+	"math"
+	"testing"
+)
+
+func newSlider(curveType string, points [][2]int, length float64) HitObject {
+	return HitObject{
+		NoteType: HitTypeSlider,
+		SliderParams: SliderParams{
+			CurveType:   curveType,
+			CurvePoints: points,
+			Slides:      1,
+			Length:      length,
+		},
+	}
+}
+
+func TestSliderPath(t *testing.T) {
+	for _, tc := range []struct {
+		name string
+		h    HitObject
+		want float64
+		end  Vector2
+	}{
+		{"linear", newSlider(CurveLinear, [][2]int{{100, 0}, {100, 100}}, 0), 200, Vector2{100, 100}},
+		{"linear cut", newSlider(CurveLinear, [][2]int{{100, 0}, {100, 100}}, 150), 150, Vector2{100, 50}},
+		{"linear extended", newSlider(CurveLinear, [][2]int{{100, 0}}, 150), 150, Vector2{150, 0}},
+		{"bezier red anchor", newSlider(CurveBezier, [][2]int{{100, 0}, {100, 0}, {100, 100}}, 0), 200, Vector2{100, 100}},
+		{"perfect", newSlider(CurvePerfect, [][2]int{{100, 100}, {200, 0}}, 0), 100 * math.Pi, Vector2{200, 0}},
+		{"perfect collinear", newSlider(CurvePerfect, [][2]int{{100, 0}, {200, 0}}, 0), 200, Vector2{200, 0}},
+		{"catmull", newSlider(CurveCatmull, [][2]int{{100, 0}, {200, 0}}, 0), 200, Vector2{200, 0}},
+	} {
+		p := tc.h.Path()
+		if got := p.Length(); math.Abs(got-tc.want) > 0.5 {
+			t.Errorf("%s: length = %v, want %v", tc.name, got, tc.want)
+		}
+		if got := p.PositionAt(p.Length()); got.Sub(tc.end).Len() > 0.5 {
+			t.Errorf("%s: end = %v, want %v", tc.name, got, tc.end)
+		}
+	}
+
+	// The arc goes through the middle point.
+	p := newSlider(CurvePerfect, [][2]int{{100, 100}, {200, 0}}, 0).Path()
+	if got := p.PositionAt(p.Length() / 2); got.Sub(Vector2{100, 100}).Len() > 0.5 {
+		t.Errorf("perfect: middle = %v, want %v", got, Vector2{100, 100})
+	}
+}
+
+func TestSliderTiming(t *testing.T) {
+	f := Format{
+		FormatVersion: 14,
+		Difficulty:    Difficulty{SliderMultiplier: 1.4, SliderTickRate: 2},
+		TimingPoints: TimingPoints{
+			{Time: 0, BeatLength: 500, Uninherited: true},
+			{Time: 1000, BeatLength: -50}, // Speed is 2.
+		},
+	}
+	h := newSlider(CurveLinear, [][2]int{{280, 0}}, 280)
+	h.Time = 1000
+	h.SliderParams.Slides = 2
+	s, ok := f.Slider(h)
+	if !ok {
+		t.Fatal("not a slider")
+	}
+	if math.Abs(s.Duration-1000) > 1e-6 {
+		t.Errorf("duration = %v, want 1000", s.Duration)
+	}
+	if len(s.RepeatTimes) != 1 || math.Abs(s.RepeatTimes[0]-1500) > 1e-6 {
+		t.Errorf("repeat times = %v, want [1500]", s.RepeatTimes)
+	}
+	if len(s.TickTimes) != 2 || math.Abs(s.TickTimes[0]-1250) > 1e-6 || math.Abs(s.TickTimes[1]-1750) > 1e-6 {
+		t.Errorf("tick times = %v, want [1250 1750]", s.TickTimes)
+	}
+	for _, tc := range []struct {
+		time float64
+		want Vector2
+	}{{1250, Vector2{140, 0}}, {1500, Vector2{280, 0}}, {1750, Vector2{140, 0}}, {2500, Vector2{0, 0}}} {
+		if got := s.PositionAt(tc.time); got.Sub(tc.want).Len() > 1e-6 {
+			t.Errorf("position at %v = %v, want %v", tc.time, got, tc.want)
+		}
+	}
+}
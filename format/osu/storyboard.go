@@ -0,0 +1,419 @@
+package osu
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Storyboard consists of objects in [Events] of .osu and .osb.
+// Objects are in order of declaration, which is also drawing order in a layer.
+type Storyboard struct {
+	Objects []Object
+}
+
+const (
+	LayerBackground = "Background"
+	LayerFail       = "Fail"
+	LayerPass       = "Pass"
+	LayerForeground = "Foreground"
+	LayerOverlay    = "Overlay"
+)
+
+var Layers = []string{LayerBackground, LayerFail, LayerPass, LayerForeground, LayerOverlay}
+
+// Origins are in order of their numeric values.
+var Origins = []string{"TopLeft", "Centre", "CentreLeft", "TopRight",
+	"BottomCentre", "TopCentre", "Custom", "CentreRight", "BottomLeft", "BottomRight"}
+
+const (
+	LoopForever = "LoopForever"
+	LoopOnce    = "LoopOnce"
+)
+
+// Object is either a Sprite or an Animation.
+// Position is in storyboard coordinates: 640x480.
+type Object struct {
+	Type       string // Sprite or Animation.
+	Layer      string
+	Origin     string
+	Filepath   string
+	X          float64
+	Y          float64
+	FrameCount int     // Animation only.
+	FrameDelay float64 // Animation only.
+	LoopType   string  // Animation only.
+	Commands   []Command
+}
+
+// Command changes a property of an object over time.
+// Shorthand commands are expanded into consecutive commands.
+type Command struct {
+	Type        string // F, M, MX, MY, S, V, R, C, P, L and T.
+	Easing      int
+	StartTime   int
+	EndTime     int
+	StartValues []float64
+	EndValues   []float64
+	Parameter   string    // P only: H, V or A.
+	LoopCount   int       // L only.
+	TriggerName string    // T only.
+	Group       int       // T only.
+	Commands    []Command // L and T only. Times are relative.
+}
+
+// HitSoundTrigger is a condition of trigger named
+// "HitSound[SampleSet][AdditionsSampleSet][Addition][CustomSampleSet]",
+// e.g., HitSoundDrumWhistle. Empty field and "All" match any.
+// Addition "Normal" stands for the normal sound played at every hit.
+type HitSoundTrigger struct {
+	SampleSet   string
+	AdditionSet string
+	Addition    string
+	Index       int // -1 matches any.
+}
+
+var hitSoundTriggerRegexp = regexp.MustCompile(
+	`^HitSound(All|Normal|Soft|Drum)?(All|Normal|Soft|Drum)?(Whistle|Finish|Clap|Normal)?(\d+)?$`)
+
+// ParseHitSoundTrigger returns false when the name is not of HitSound trigger.
+// A hit sound played is also described in the form, with every field filled.
+func ParseHitSoundTrigger(name string) (HitSoundTrigger, bool) {
+	m := hitSoundTriggerRegexp.FindStringSubmatch(name)
+	if m == nil {
+		return HitSoundTrigger{}, false
+	}
+	t := HitSoundTrigger{SampleSet: m[1], AdditionSet: m[2], Addition: m[3], Index: -1}
+	if m[4] != "" {
+		t.Index, _ = strconv.Atoi(m[4])
+	}
+	return t, true
+}
+
+// Match reports whether the played hit sound fires the trigger.
+func (t HitSoundTrigger) Match(played HitSoundTrigger) bool {
+	match := func(cond, v string) bool { return cond == "" || cond == "All" || cond == v }
+	return match(t.SampleSet, played.SampleSet) &&
+		match(t.AdditionSet, played.AdditionSet) &&
+		match(t.Addition, played.Addition) &&
+		(t.Index < 0 || t.Index == played.Index)
+}
+
+// The number of values for each command type.
+var commandValueCounts = map[string]int{
+	"F": 1, "M": 2, "MX": 1, "MY": 1, "S": 1, "V": 2, "R": 1, "C": 3,
+}
+
+// ParseStoryboard parses storyboard objects from either .osb or .osu.
+// Variables declared at [Variables] are substituted in [Events].
+// Malformed lines are skipped; the first error is returned along with the result.
+func ParseStoryboard(dat []byte) (*Storyboard, error) {
+	sb := &Storyboard{Objects: make([]Object, 0)}
+	dat = bytes.ReplaceAll(dat, []byte("\r\n"), []byte("\n"))
+
+	var (
+		firstErr  error
+		section   string
+		vars      []string // Pairs of name and value.
+		object    *Object
+		compound  *Command // Current L or T command.
+		addObject = func() {
+			if object != nil {
+				sb.Objects = append(sb.Objects, *object)
+			}
+		}
+	)
+	for _, l := range bytes.Split(dat, []byte("\n")) {
+		line := strings.TrimRight(string(l), " \t")
+		if isPass(strings.TrimSpace(line)) {
+			continue
+		}
+		if isSection(line) {
+			section = strings.Trim(line, "[]")
+			continue
+		}
+		switch section {
+		case "Variables":
+			kv := strings.SplitN(line, "=", 2)
+			if len(kv) < 2 || !strings.HasPrefix(kv[0], "$") {
+				continue
+			}
+			vars = append(vars, kv[0], kv[1])
+		case "Events":
+			if len(vars) != 0 {
+				line = newVariableReplacer(vars).Replace(line)
+			}
+			depth := len(line) - len(strings.TrimLeft(line, " _"))
+			line = line[depth:]
+			var err error
+			switch depth {
+			case 0:
+				addObject()
+				object, compound = nil, nil
+				var o Object
+				if o, err = newObject(line); err == nil {
+					object = &o
+				} else if errors.Is(err, errNotObject) {
+					err = nil // Background, Video, Break and Sample.
+				}
+			case 1:
+				if object == nil {
+					continue
+				}
+				var cs []Command
+				if cs, err = newCommands(line); err == nil {
+					object.Commands = append(object.Commands, cs...)
+					compound = nil
+					if c := cs[0]; c.Type == "L" || c.Type == "T" {
+						compound = &object.Commands[len(object.Commands)-1]
+					}
+				}
+			default:
+				if compound == nil {
+					continue
+				}
+				var cs []Command
+				if cs, err = newCommands(line); err == nil {
+					if cs[0].Type == "L" || cs[0].Type == "T" {
+						err = errors.New("nested compound command")
+					} else {
+						compound.Commands = append(compound.Commands, cs...)
+					}
+				}
+			}
+			if err != nil && firstErr == nil {
+				firstErr = fmt.Errorf("error at %s: %s", line, err)
+			}
+		}
+	}
+	addObject()
+	return sb, firstErr
+}
+
+// Longer names are replaced first: $ab should not be replaced by $a.
+func newVariableReplacer(vars []string) *strings.Replacer {
+	pairs := make([][2]string, 0, len(vars)/2)
+	for i := 0; i+1 < len(vars); i += 2 {
+		pairs = append(pairs, [2]string{vars[i], vars[i+1]})
+	}
+	sort.SliceStable(pairs, func(i, j int) bool { return len(pairs[i][0]) > len(pairs[j][0]) })
+	oldnew := make([]string, 0, len(vars))
+	for _, p := range pairs {
+		oldnew = append(oldnew, p[0], p[1])
+	}
+	return strings.NewReplacer(oldnew...)
+}
+
+var errNotObject = errors.New("not a storyboard object")
+
+func newObject(line string) (o Object, err error) {
+	vs := strings.Split(line, ",")
+	switch vs[0] {
+	case "4", "Sprite":
+		o.Type = "Sprite"
+		if len(vs) < 6 {
+			return o, errors.New("invalid object: not enough length")
+		}
+	case "6", "Animation":
+		o.Type = "Animation"
+		if len(vs) < 8 {
+			return o, errors.New("invalid object: not enough length")
+		}
+	default:
+		return o, errNotObject
+	}
+	if o.Layer, err = enumValue(vs[1], Layers); err != nil {
+		return
+	}
+	if o.Origin, err = enumValue(vs[2], Origins); err != nil {
+		return
+	}
+	o.Filepath = strings.Trim(vs[3], `"`)
+	if o.X, err = strconv.ParseFloat(vs[4], 64); err != nil {
+		return
+	}
+	if o.Y, err = strconv.ParseFloat(vs[5], 64); err != nil {
+		return
+	}
+	if o.Type == "Animation" {
+		if o.FrameCount, err = strconv.Atoi(vs[6]); err != nil {
+			return
+		}
+		if o.FrameDelay, err = strconv.ParseFloat(vs[7], 64); err != nil {
+			return
+		}
+		o.LoopType = LoopForever
+		if len(vs) >= 9 {
+			if o.LoopType, err = enumValue(vs[8], []string{LoopForever, LoopOnce}); err != nil {
+				return
+			}
+		}
+	}
+	return
+}
+
+// enumValue accepts both a name and its index.
+func enumValue(s string, names []string) (string, error) {
+	for _, name := range names {
+		if s == name {
+			return s, nil
+		}
+	}
+	if i, err := strconv.Atoi(s); err == nil && i >= 0 && i < len(names) {
+		return names[i], nil
+	}
+	return "", fmt.Errorf("unknown value %s", s)
+}
+
+// newCommands returns more than one command when the line is shorthand:
+// each following values are reached in the same duration.
+func newCommands(line string) ([]Command, error) {
+	vs := strings.Split(line, ",")
+	c := Command{Type: vs[0]}
+	switch c.Type {
+	case "L":
+		if len(vs) < 3 {
+			return nil, errors.New("invalid command: not enough length")
+		}
+		t, err := parseTime(vs[1])
+		if err != nil {
+			return nil, err
+		}
+		c.StartTime = t
+		c.EndTime = t
+		if c.LoopCount, err = strconv.Atoi(vs[2]); err != nil {
+			return nil, err
+		}
+		return []Command{c}, nil
+	case "T":
+		if len(vs) < 4 {
+			return nil, errors.New("invalid command: not enough length")
+		}
+		var err error
+		c.TriggerName = vs[1]
+		if c.StartTime, err = parseTime(vs[2]); err != nil {
+			return nil, err
+		}
+		if c.EndTime, err = parseTime(vs[3]); err != nil {
+			return nil, err
+		}
+		if len(vs) >= 5 {
+			if c.Group, err = strconv.Atoi(vs[4]); err != nil {
+				return nil, err
+			}
+		}
+		return []Command{c}, nil
+	}
+
+	if len(vs) < 5 {
+		return nil, errors.New("invalid command: not enough length")
+	}
+	var err error
+	if c.Easing, err = strconv.Atoi(vs[1]); err != nil {
+		return nil, err
+	}
+	if c.StartTime, err = parseTime(vs[2]); err != nil {
+		return nil, err
+	}
+	c.EndTime = c.StartTime
+	if vs[3] != "" {
+		if c.EndTime, err = parseTime(vs[3]); err != nil {
+			return nil, err
+		}
+	}
+	if c.Type == "P" {
+		c.Parameter = vs[4]
+		return []Command{c}, nil
+	}
+	n, ok := commandValueCounts[c.Type]
+	if !ok {
+		return nil, fmt.Errorf("unknown command type %s", c.Type)
+	}
+	values := make([]float64, len(vs)-4)
+	for i, v := range vs[4:] {
+		if values[i], err = strconv.ParseFloat(v, 64); err != nil {
+			return nil, err
+		}
+	}
+	if len(values) < n {
+		return nil, errors.New("invalid command: not enough values")
+	}
+	if len(values) < 2*n { // End values are omitted.
+		c.StartValues = values[:n]
+		c.EndValues = values[:n]
+		return []Command{c}, nil
+	}
+	duration := c.EndTime - c.StartTime
+	cs := make([]Command, 0, len(values)/n-1)
+	for i := 0; (i+2)*n <= len(values); i++ {
+		c2 := c
+		c2.StartTime = c.StartTime + i*duration
+		c2.EndTime = c.EndTime + i*duration
+		c2.StartValues = values[i*n : (i+1)*n]
+		c2.EndValues = values[(i+1)*n : (i+2)*n]
+		cs = append(cs, c2)
+	}
+	return cs, nil
+}
+
+func parseTime(s string) (int, error) {
+	f, err := strconv.ParseFloat(s, 64)
+	return int(f), err
+}
+
+// Unroll returns sub commands of loop with absolute times.
+// An iteration lasts from the earliest start to the latest end of sub commands.
+func (c Command) Unroll() []Command {
+	if c.Type != "L" || len(c.Commands) == 0 {
+		return nil
+	}
+	start, end := c.Commands[0].StartTime, c.Commands[0].EndTime
+	for _, c2 := range c.Commands {
+		if start > c2.StartTime {
+			start = c2.StartTime
+		}
+		if end < c2.EndTime {
+			end = c2.EndTime
+		}
+	}
+	count := c.LoopCount
+	if count < 1 {
+		count = 1
+	}
+	cs := make([]Command, 0, count*len(c.Commands))
+	for i := 0; i < count; i++ {
+		cs = append(cs, c.Shift(c.StartTime+i*(end-start))...)
+	}
+	return cs
+}
+
+// Shift returns sub commands of loop or trigger with times added by offset.
+func (c Command) Shift(offset int) []Command {
+	cs := make([]Command, len(c.Commands))
+	for i, c2 := range c.Commands {
+		c2.StartTime += offset
+		c2.EndTime += offset
+		cs[i] = c2
+	}
+	return cs
+}
+
+// Values returns eased values of the command at given time.
+func (c Command) Values(time int) []float64 {
+	switch {
+	case time < c.StartTime:
+		return c.StartValues
+	case time >= c.EndTime:
+		return c.EndValues
+	}
+	x := Ease(c.Easing, float64(time-c.StartTime)/float64(c.EndTime-c.StartTime))
+	vs := make([]float64, len(c.StartValues))
+	for i := range vs {
+		vs[i] = c.StartValues[i] + (c.EndValues[i]-c.StartValues[i])*x
+	}
+	return vs
+}
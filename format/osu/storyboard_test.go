@@ -0,0 +1,138 @@
+package osu
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseStoryboard(t *testing.T) {
+	const dat = "[Variables]\r\n$a=1000\r\n$ab=2000\r\n" +
+		"[Events]\r\n" +
+		"//Background and Video events\r\n" +
+		"0,0,\"bg.jpg\",0,0\r\n" +
+		"Sprite,Foreground,Centre,\"sb\\star.png\",320,240\r\n" +
+		" F,0,$a,$ab,0,1\r\n" +
+		" M,1,0,100,0,0,10,20,30,40\r\n" +
+		" L,500,2\r\n" +
+		"  S,0,0,100,1,2\r\n" +
+		" T,HitSoundClap,0,5000,1\r\n" +
+		"  C,0,0,100,255,0,0\r\n" +
+		"Animation,Background,TopLeft,\"a.png\",0,0,3,100,LoopOnce\r\n" +
+		" P,0,0,,A\r\n"
+	sb, err := ParseStoryboard([]byte(dat))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(sb.Objects) != 2 {
+		t.Fatalf("%d objects, want 2", len(sb.Objects))
+	}
+
+	o := sb.Objects[0]
+	if o.Type != "Sprite" || o.Layer != LayerForeground || o.Origin != "Centre" ||
+		o.Filepath != `sb\star.png` || o.X != 320 || o.Y != 240 {
+		t.Errorf("object: %+v", o)
+	}
+	want := []Command{
+		{Type: "F", StartTime: 1000, EndTime: 2000, StartValues: []float64{0}, EndValues: []float64{1}},
+		{Type: "M", Easing: 1, StartTime: 0, EndTime: 100, StartValues: []float64{0, 0}, EndValues: []float64{10, 20}},
+		{Type: "M", Easing: 1, StartTime: 100, EndTime: 200, StartValues: []float64{10, 20}, EndValues: []float64{30, 40}},
+		{Type: "L", StartTime: 500, EndTime: 500, LoopCount: 2, Commands: []Command{
+			{Type: "S", StartTime: 0, EndTime: 100, StartValues: []float64{1}, EndValues: []float64{2}},
+		}},
+		{Type: "T", StartTime: 0, EndTime: 5000, TriggerName: "HitSoundClap", Group: 1, Commands: []Command{
+			{Type: "C", StartTime: 0, EndTime: 100, StartValues: []float64{255, 0, 0}, EndValues: []float64{255, 0, 0}},
+		}},
+	}
+	if !reflect.DeepEqual(o.Commands, want) {
+		t.Errorf("commands:\n got %+v\nwant %+v", o.Commands, want)
+	}
+
+	a := sb.Objects[1]
+	if a.Type != "Animation" || a.FrameCount != 3 || a.FrameDelay != 100 || a.LoopType != LoopOnce {
+		t.Errorf("animation: %+v", a)
+	}
+	if len(a.Commands) != 1 || a.Commands[0].Parameter != "A" || a.Commands[0].EndTime != 0 {
+		t.Errorf("parameter: %+v", a.Commands)
+	}
+}
+
+// Malformed lines are skipped, and the rest are parsed.
+func TestParseStoryboardMalformed(t *testing.T) {
+	const dat = "[Events]\n" +
+		"Sprite,Nowhere,Centre,\"a.png\",0,0\n" +
+		" F,0,0,100,0,1\n" + // Command of the skipped object.
+		"Sprite,Pass,Centre,\"b.png\",0,0\n" +
+		" F,0,x,100,0,1\n" +
+		" Z,0,0,100,0\n" +
+		" L,0,1\n" +
+		"  L,0,1\n" +
+		" F,0,0,100,1\n"
+	sb, err := ParseStoryboard([]byte(dat))
+	if err == nil {
+		t.Error("no error for malformed lines")
+	}
+	if len(sb.Objects) != 1 || sb.Objects[0].Filepath != "b.png" {
+		t.Fatalf("objects: %+v", sb.Objects)
+	}
+	cs := sb.Objects[0].Commands
+	if len(cs) != 2 || cs[0].Type != "L" || len(cs[0].Commands) != 0 || cs[1].Type != "F" {
+		t.Errorf("commands: %+v", cs)
+	}
+}
+
+func TestUnroll(t *testing.T) {
+	c := Command{Type: "L", StartTime: 1000, LoopCount: 2, Commands: []Command{
+		{Type: "F", StartTime: 0, EndTime: 100},
+		{Type: "F", StartTime: 100, EndTime: 300},
+	}}
+	var times [][2]int
+	for _, c2 := range c.Unroll() {
+		times = append(times, [2]int{c2.StartTime, c2.EndTime})
+	}
+	want := [][2]int{{1000, 1100}, {1100, 1300}, {1300, 1400}, {1400, 1600}}
+	if !reflect.DeepEqual(times, want) {
+		t.Errorf("got %v, want %v", times, want)
+	}
+}
+
+func TestValues(t *testing.T) {
+	c := Command{Type: "F", StartTime: 100, EndTime: 200, StartValues: []float64{0}, EndValues: []float64{1}}
+	for time, want := range map[int]float64{0: 0, 150: 0.5, 200: 1, 300: 1} {
+		if got := c.Values(time)[0]; got != want {
+			t.Errorf("Values(%d) = %v, want %v", time, got, want)
+		}
+	}
+}
+
+func TestHitSoundTrigger(t *testing.T) {
+	played, ok := ParseHitSoundTrigger("HitSoundSoftDrumClap2")
+	if !ok || played != (HitSoundTrigger{"Soft", "Drum", "Clap", 2}) {
+		t.Fatalf("played: %+v, %v", played, ok)
+	}
+	for name, want := range map[string]bool{
+		"HitSound":              true,
+		"HitSoundClap":          true,
+		"HitSoundWhistle":       false,
+		"HitSoundSoft":          true,
+		"HitSoundNormal":        false, // Sample set, not addition.
+		"HitSoundAllDrumClap":   true,
+		"HitSoundSoftSoft":      false,
+		"HitSoundSoftDrumClap2": true,
+		"HitSoundSoftDrumClap1": false,
+		"HitSoundDrumClap2":     false,
+	} {
+		cond, ok := ParseHitSoundTrigger(name)
+		if !ok {
+			t.Errorf("%s: not parsed", name)
+			continue
+		}
+		if got := cond.Match(played); got != want {
+			t.Errorf("%s: match %v, want %v", name, got, want)
+		}
+	}
+	for _, name := range []string{"Passing", "Failing", "HitSoundLoud", "HitSoundClapSoft"} {
+		if _, ok := ParseHitSoundTrigger(name); ok {
+			t.Errorf("%s: parsed as HitSound trigger", name)
+		}
+	}
+}
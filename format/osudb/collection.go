@@ -0,0 +1,38 @@
+package osudb
+
+import "fmt"
+
+// Collection is an entry of osu!'s collection.db: a named list of beatmaps.
+type Collection struct {
+	Name string
+	MD5s []string // Hex strings of MD5 of .osu files.
+}
+
+// Minimum size of a collection: a name and the number of MD5s.
+const minCollectionSize = 1 + 4
+
+func ParseCollections(dat []byte) ([]Collection, error) {
+	r := newReader(dat)
+	r.int() // Version.
+	count := r.count(minCollectionSize)
+	if r.err != nil {
+		return nil, r.err
+	}
+	cs := make([]Collection, 0, count)
+	for i := int32(0); i < count; i++ {
+		c := Collection{Name: r.string()}
+		n := r.count(1) // An empty string takes a byte.
+		if r.err != nil {
+			return cs, fmt.Errorf("error at collection %d: %s", i, r.err)
+		}
+		c.MD5s = make([]string, 0, n)
+		for j := int32(0); j < n; j++ {
+			c.MD5s = append(c.MD5s, r.string())
+		}
+		if r.err != nil {
+			return cs, fmt.Errorf("error at collection %s: %s", c.Name, r.err)
+		}
+		cs = append(cs, c)
+	}
+	return cs, nil
+}
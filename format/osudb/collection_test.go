@@ -0,0 +1,61 @@
+package osudb
+
+import (
+	"encoding/binary"
+	"reflect"
+	"testing"
+)
+
+func appendInt(b []byte, v int32) []byte {
+	var buf [4]byte
+	binary.LittleEndian.PutUint32(buf[:], uint32(v))
+	return append(b, buf[:]...)
+}
+func appendString(b []byte, s string) []byte {
+	if s == "" {
+		return append(b, 0x00)
+	}
+	b = append(b, 0x0b)
+	var buf [binary.MaxVarintLen64]byte
+	n := binary.PutUvarint(buf[:], uint64(len(s)))
+	b = append(b, buf[:n]...)
+	return append(b, s...)
+}
+
+func TestParseCollections(t *testing.T) {
+	var dat []byte
+	dat = appendInt(dat, 20230101) // Version.
+	dat = appendInt(dat, 2)
+	dat = appendString(dat, "Favorites")
+	dat = appendInt(dat, 2)
+	dat = appendString(dat, "0123456789abcdef0123456789abcdef")
+	dat = appendString(dat, "fedcba9876543210fedcba9876543210")
+	dat = appendString(dat, "Empty")
+	dat = appendInt(dat, 0)
+	cs, err := ParseCollections(dat)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []Collection{
+		{"Favorites", []string{"0123456789abcdef0123456789abcdef", "fedcba9876543210fedcba9876543210"}},
+		{"Empty", []string{}},
+	}
+	if !reflect.DeepEqual(cs, want) {
+		t.Errorf("got %+v, want %+v", cs, want)
+	}
+}
+
+// Counts which the rest of data cannot hold are rejected before allocating.
+func TestParseInvalidCount(t *testing.T) {
+	for _, count := range []int32{-1, 1 << 30} {
+		var dat []byte
+		dat = appendInt(dat, 20230101)
+		dat = appendInt(dat, count)
+		if _, err := ParseCollections(dat); err == nil {
+			t.Errorf("collections: no error for count %d", count)
+		}
+		if _, err := ParseScores(dat); err == nil {
+			t.Errorf("scores: no error for count %d", count)
+		}
+	}
+}
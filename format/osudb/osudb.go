@@ -0,0 +1,133 @@
+package osudb
+
+import "fmt"
+
+// OsuDB is for osu!'s osu!.db, which lists all beatmaps in Songs folder.
+type OsuDB struct {
+	Version     int32
+	FolderCount int32
+	PlayerName  string
+	Beatmaps    []Beatmap
+	Permissions int32
+}
+
+// Beatmap is an entry of osu!.db.
+// Fields not needed for gosu are skipped when parsing.
+type Beatmap struct {
+	Artist        string
+	ArtistUnicode string
+	Title         string
+	TitleUnicode  string
+	Creator       string
+	Version       string // Difficulty name.
+	AudioFilename string
+	MD5           string // Hex string of MD5 of .osu file.
+	Filename      string // Name of .osu file.
+	RankedStatus  uint8
+	LastModified  int64 // In Windows ticks.
+	BeatmapID     int32
+	BeatmapSetID  int32
+	Mode          uint8
+	FolderName    string // Name of folder at Songs folder.
+}
+
+// Format of entry has changed at these versions.
+const (
+	versionFloatDifficulty = 20140609 // Difficulty values are written in float32.
+	versionNoEntrySize     = 20191106 // Size of each entry is not written.
+)
+
+// Minimum sizes of entries in bytes, with empty strings and lists.
+const (
+	minBeatmapSize     = 109
+	minStarRatingSize  = 1 + 4 + 1 + 4
+	minTimingPointSize = 8 + 8 + 1
+)
+
+func ParseOsuDB(dat []byte) (*OsuDB, error) {
+	r := newReader(dat)
+	db := &OsuDB{}
+	db.Version = r.int()
+	db.FolderCount = r.int()
+	r.bool() // Account unlocked.
+	r.long() // Date the account will be unlocked.
+	db.PlayerName = r.string()
+	count := r.count(minBeatmapSize)
+	if r.err != nil {
+		return db, r.err
+	}
+	db.Beatmaps = make([]Beatmap, 0, count)
+	for i := int32(0); i < count; i++ {
+		b := r.beatmap(db.Version)
+		if r.err != nil {
+			return db, fmt.Errorf("error at beatmap %d: %s", i, r.err)
+		}
+		db.Beatmaps = append(db.Beatmaps, b)
+	}
+	db.Permissions = r.int()
+	return db, r.err
+}
+
+func (r *reader) beatmap(version int32) (b Beatmap) {
+	if version < versionNoEntrySize {
+		r.int() // Size of the entry in bytes.
+	}
+	b.Artist = r.string()
+	b.ArtistUnicode = r.string()
+	b.Title = r.string()
+	b.TitleUnicode = r.string()
+	b.Creator = r.string()
+	b.Version = r.string()
+	b.AudioFilename = r.string()
+	b.MD5 = r.string()
+	b.Filename = r.string()
+	b.RankedStatus = r.byte()
+	r.skip(3 * 2) // The numbers of hit circles, sliders and spinners.
+	b.LastModified = r.long()
+	if version < versionFloatDifficulty {
+		r.skip(4 * 1) // AR, CS, HP, OD.
+	} else {
+		r.skip(4 * 4)
+	}
+	r.double() // Slider velocity.
+	if version >= versionFloatDifficulty {
+		for mode := 0; mode < 4; mode++ { // Star ratings of each mode by mods.
+			count := r.count(minStarRatingSize)
+			for i := int32(0); i < count && r.err == nil; i++ {
+				r.byte() // 0x08
+				r.int()  // Mods.
+				if r.byte() == 0x0c {
+					r.single()
+				} else { // 0x0d
+					r.double()
+				}
+			}
+		}
+	}
+	r.skip(3 * 4) // Drain time, total time, preview time.
+	count := r.count(minTimingPointSize)
+	r.skip(int(count) * minTimingPointSize) // Timing points: BPM, offset, inherited.
+	b.BeatmapID = r.int()
+	b.BeatmapSetID = r.int()
+	r.int()       // Thread ID.
+	r.skip(4 * 1) // Grades of each mode.
+	r.short()     // Local offset.
+	r.single()    // Stack leniency.
+	b.Mode = r.byte()
+	r.string() // Source.
+	r.string() // Tags.
+	r.short()  // Online offset.
+	r.string() // Font of title.
+	r.bool()   // Unplayed.
+	r.long()   // Last played time.
+	r.bool()   // Osz2.
+	b.FolderName = r.string()
+	r.long()      // Last checked time.
+	r.skip(5 * 1) // Ignore sound, ignore skin, disable storyboard, disable video, visual override.
+	if version < versionFloatDifficulty {
+		r.short()
+	}
+	r.int()  // Last modified time.
+	r.byte() // Mania scroll speed.
+	return
+}
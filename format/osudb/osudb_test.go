@@ -0,0 +1,128 @@
+package osudb
+
+import (
+	"bytes"
+	"encoding/binary"
+	"reflect"
+	"testing"
+)
+
+func appendLE(b []byte, vs ...any) []byte {
+	var buf bytes.Buffer
+	for _, v := range vs {
+		binary.Write(&buf, binary.LittleEndian, v)
+	}
+	return append(b, buf.Bytes()...)
+}
+
+var testBeatmap = Beatmap{
+	Artist:        "Artist",
+	ArtistUnicode: "アーティスト",
+	Title:         "Title",
+	Creator:       "Mapper",
+	Version:       "4K Hard",
+	AudioFilename: "audio.mp3",
+	MD5:           "0123456789abcdef0123456789abcdef",
+	Filename:      "Artist - Title (Mapper) [4K Hard].osu",
+	RankedStatus:  4,
+	LastModified:  638000000000000000,
+	BeatmapID:     100,
+	BeatmapSetID:  10,
+	Mode:          3,
+	FolderName:    "10 Artist - Title",
+}
+
+// appendBeatmap writes an entry of osu!.db in the format of the version.
+// Each mode has a star rating, whose value header is starRating.
+func appendBeatmap(dat []byte, version int32, starRating byte, b Beatmap) []byte {
+	var e []byte
+	for _, s := range []string{b.Artist, b.ArtistUnicode, b.Title, b.TitleUnicode,
+		b.Creator, b.Version, b.AudioFilename, b.MD5, b.Filename} {
+		e = appendString(e, s)
+	}
+	e = appendLE(e, b.RankedStatus, [3]int16{100, 20, 1}, b.LastModified)
+	if version < versionFloatDifficulty {
+		e = appendLE(e, [4]uint8{9, 4, 8, 8})
+	} else {
+		e = appendLE(e, [4]float32{9, 4, 8, 8})
+	}
+	e = appendLE(e, 1.4)
+	if version >= versionFloatDifficulty {
+		for mode := 0; mode < 4; mode++ {
+			e = appendLE(e, int32(1), uint8(0x08), int32(0), starRating)
+			if starRating == 0x0c {
+				e = appendLE(e, float32(3.5))
+			} else {
+				e = appendLE(e, 3.5)
+			}
+		}
+	}
+	e = appendLE(e, [3]int32{120, 125000, 40000})
+	e = appendLE(e, int32(2), 500.0, 0.0, true, -100.0, 1000.0, false)
+	e = appendLE(e, b.BeatmapID, b.BeatmapSetID, int32(0), [4]uint8{9, 9, 9, 9})
+	e = appendLE(e, int16(0), float32(0.7), b.Mode)
+	e = appendString(e, "Source")
+	e = appendString(e, "tag1 tag2")
+	e = appendLE(e, int16(0))
+	e = appendString(e, "")
+	e = appendLE(e, true, int64(0), false)
+	e = appendString(e, b.FolderName)
+	e = appendLE(e, int64(0), [5]uint8{})
+	if version < versionFloatDifficulty {
+		e = appendLE(e, int16(0))
+	}
+	e = appendLE(e, int32(0), uint8(0))
+	if version < versionNoEntrySize {
+		dat = appendInt(dat, int32(len(e)))
+	}
+	return append(dat, e...)
+}
+
+func testOsuDB(version int32, starRating byte, beatmaps ...Beatmap) []byte {
+	var dat []byte
+	dat = appendLE(dat, version, int32(1), true, int64(0))
+	dat = appendString(dat, "Player")
+	dat = appendInt(dat, int32(len(beatmaps)))
+	for _, b := range beatmaps {
+		dat = appendBeatmap(dat, version, starRating, b)
+	}
+	return appendInt(dat, 1) // Permissions.
+}
+
+func TestParseOsuDB(t *testing.T) {
+	other := testBeatmap
+	other.Version = "7K Normal"
+	other.MD5 = "fedcba9876543210fedcba9876543210"
+	other.BeatmapID = 101
+	for _, tc := range []struct {
+		name       string
+		version    int32
+		starRating byte
+	}{
+		{"byte difficulty", 20140608, 0},
+		{"double star rating", 20140609, 0x0d},
+		{"float star rating with entry size", 20191105, 0x0c},
+		{"double star rating without entry size", 20191106, 0x0d},
+		{"float star rating without entry size", 20250107, 0x0c},
+	} {
+		dat := testOsuDB(tc.version, tc.starRating, testBeatmap, other)
+		db, err := ParseOsuDB(dat)
+		if err != nil {
+			t.Errorf("%s: %s", tc.name, err)
+			continue
+		}
+		want := &OsuDB{
+			Version:     tc.version,
+			FolderCount: 1,
+			PlayerName:  "Player",
+			Beatmaps:    []Beatmap{testBeatmap, other},
+			Permissions: 1,
+		}
+		if !reflect.DeepEqual(db, want) {
+			t.Errorf("%s:\n got %+v\nwant %+v", tc.name, db, want)
+		}
+		if _, err := ParseOsuDB(dat[:len(dat)-5]); err == nil {
+			t.Errorf("%s: cut data: no error", tc.name)
+		}
+	}
+}
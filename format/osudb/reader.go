@@ -0,0 +1,96 @@
+package osudb
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+)
+
+// reader reads values in osu!'s database format: little-endian
+// integers, and strings with 0x0b header and ULEB128 length.
+// The first error is kept and the following reads are skipped.
+type reader struct {
+	src *bytes.Reader
+	r   *bufio.Reader
+	err error
+}
+
+func newReader(dat []byte) *reader {
+	src := bytes.NewReader(dat)
+	return &reader{src: src, r: bufio.NewReader(src)}
+}
+
+// remaining returns the number of bytes not read yet.
+func (r *reader) remaining() int { return r.src.Len() + r.r.Buffered() }
+
+func (r *reader) read(v any) {
+	if r.err != nil {
+		return
+	}
+	r.err = binary.Read(r.r, binary.LittleEndian, v)
+}
+
+func (r *reader) byte() (v uint8)     { r.read(&v); return }
+func (r *reader) bool() bool          { return r.byte() != 0 }
+func (r *reader) short() (v int16)    { r.read(&v); return }
+func (r *reader) int() (v int32)      { r.read(&v); return }
+func (r *reader) long() (v int64)     { r.read(&v); return }
+func (r *reader) single() (v float32) { r.read(&v); return }
+func (r *reader) double() (v float64) { r.read(&v); return }
+
+// count reads the number of following entries. Entries take at least
+// minSize bytes each, hence a count more than the rest of data can hold
+// is an error, which prevents allocating with a corrupted count.
+func (r *reader) count(minSize int) int32 {
+	n := r.int()
+	if r.err != nil {
+		return 0
+	}
+	if n < 0 || int64(n)*int64(minSize) > int64(r.remaining()) {
+		r.err = fmt.Errorf("invalid count: %d", n)
+		return 0
+	}
+	return n
+}
+
+func (r *reader) string() string {
+	switch r.byte() {
+	case 0x00:
+		return ""
+	case 0x0b:
+	default:
+		if r.err == nil {
+			r.err = errors.New("corrupted string header")
+		}
+		return ""
+	}
+	if r.err != nil {
+		return ""
+	}
+	length, err := binary.ReadUvarint(r.r)
+	if err != nil {
+		r.err = err
+		return ""
+	}
+	if length > math.MaxInt32 {
+		r.err = errors.New("too long string")
+		return ""
+	}
+	b := make([]byte, length)
+	if _, err := io.ReadFull(r.r, b); err != nil {
+		r.err = err
+		return ""
+	}
+	return string(b)
+}
+
+func (r *reader) skip(n int) {
+	if r.err != nil {
+		return
+	}
+	_, r.err = r.r.Discard(n)
+}
@@ -0,0 +1,70 @@
+package osudb
+
+import (
+	"fmt"
+
+	"github.com/hndada/gosu/format/osr"
+)
+
+const modTargetPractice = 1 << 23
+
+// Minimum sizes in bytes: a beatmap has MD5 and the number of scores.
+const (
+	minScoreBeatmapSize = 1 + 4
+	minScoreSize        = 1 + 4 + 3*1 + 6*2 + 4 + 2 + 1 + 4 + 1 + 8 + 4 + 8
+)
+
+// ParseScores returns scores grouped by MD5 of beatmap.
+// An entry of osu!'s scores.db is same with the header of replay file.
+// Hence a score is represented as osr.Format without ReplayData.
+func ParseScores(dat []byte) (map[string][]osr.Format, error) {
+	r := newReader(dat)
+	r.int() // Version.
+	count := r.count(minScoreBeatmapSize)
+	if r.err != nil {
+		return nil, r.err
+	}
+	scores := make(map[string][]osr.Format, count)
+	for i := int32(0); i < count; i++ {
+		md5 := r.string()
+		n := r.count(minScoreSize)
+		if r.err != nil {
+			return scores, fmt.Errorf("error at beatmap %d: %s", i, r.err)
+		}
+		ss := make([]osr.Format, 0, n)
+		for j := int32(0); j < n; j++ {
+			ss = append(ss, r.score())
+		}
+		if r.err != nil {
+			return scores, fmt.Errorf("error at beatmap %s: %s", md5, r.err)
+		}
+		scores[md5] = append(scores[md5], ss...)
+	}
+	return scores, nil
+}
+
+func (r *reader) score() (s osr.Format) {
+	s.GameMode = int8(r.byte())
+	s.GameVersion = r.int()
+	s.BeatmapMD5 = r.string()
+	s.PlayerName = r.string()
+	s.ReplayMD5 = r.string()
+	s.Num300 = r.short()
+	s.Num100 = r.short()
+	s.Num50 = r.short()
+	s.NumGeki = r.short()
+	s.NumKatu = r.short()
+	s.NumMiss = r.short()
+	s.Score = r.int()
+	s.Combo = r.short()
+	s.FullCombo = r.bool()
+	s.ModsBits = r.int()
+	s.LifeBar = r.string() // Always empty.
+	s.TimeStamp = r.long()
+	r.int() // Always -1: the size of replay data.
+	s.OnlineID = r.long()
+	if s.ModsBits&modTargetPractice != 0 {
+		r.double() // Additional accuracy.
+	}
+	return
+}
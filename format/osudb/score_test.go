@@ -0,0 +1,75 @@
+package osudb
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/hndada/gosu/format/osr"
+)
+
+func appendScore(dat []byte, s osr.Format, accuracy float64) []byte {
+	dat = appendLE(dat, uint8(s.GameMode), s.GameVersion)
+	dat = appendString(dat, s.BeatmapMD5)
+	dat = appendString(dat, s.PlayerName)
+	dat = appendString(dat, s.ReplayMD5)
+	dat = appendLE(dat, s.Num300, s.Num100, s.Num50, s.NumGeki, s.NumKatu, s.NumMiss,
+		s.Score, s.Combo, s.FullCombo, s.ModsBits)
+	dat = appendString(dat, s.LifeBar)
+	dat = appendLE(dat, s.TimeStamp, int32(-1), s.OnlineID)
+	if s.ModsBits&modTargetPractice != 0 {
+		dat = appendLE(dat, accuracy)
+	}
+	return dat
+}
+
+// Each beatmap has two scores: the latter checks where the former ends.
+func TestParseScores(t *testing.T) {
+	const md5 = "0123456789abcdef0123456789abcdef"
+	for _, tc := range []struct {
+		name string
+		mods int32
+	}{
+		{"no mods", 0},
+		{"hidden", 1 << 3},
+		{"target practice", modTargetPractice},
+	} {
+		s := osr.Format{
+			GameMode:    3,
+			GameVersion: 20230101,
+			BeatmapMD5:  md5,
+			PlayerName:  "Player",
+			ReplayMD5:   "fedcba9876543210fedcba9876543210",
+			Num300:      500,
+			Num100:      20,
+			NumGeki:     300,
+			NumKatu:     10,
+			NumMiss:     1,
+			Score:       900000,
+			Combo:       400,
+			ModsBits:    tc.mods,
+			TimeStamp:   638000000000000000,
+			OnlineID:    12345,
+		}
+		s2 := s
+		s2.PlayerName = "Other"
+		s2.FullCombo = true
+		var dat []byte
+		dat = appendInt(dat, 20230101) // Version.
+		dat = appendInt(dat, 2)
+		dat = appendString(dat, md5)
+		dat = appendInt(dat, 2)
+		dat = appendScore(dat, s, 0.95)
+		dat = appendScore(dat, s2, 0.9)
+		dat = appendString(dat, "")
+		dat = appendInt(dat, 0)
+		scores, err := ParseScores(dat)
+		if err != nil {
+			t.Errorf("%s: %s", tc.name, err)
+			continue
+		}
+		want := map[string][]osr.Format{md5: {s, s2}, "": nil}
+		if !reflect.DeepEqual(scores, want) {
+			t.Errorf("%s:\n got %+v\nwant %+v", tc.name, scores, want)
+		}
+	}
+}
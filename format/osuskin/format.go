@@ -0,0 +1,121 @@
+package osuskin
+
+import "image/color"
+
+// Format is for skin.ini of osu! skin.
+// Positions and sizes are in osu! pixels: the screen is 640x480.
+// https://osu.ppy.sh/wiki/en/Skinning/skin.ini
+type Format struct {
+	General
+	Colours map[string]color.NRGBA // Key is a name, e.g., Combo1.
+	Fonts
+	Manias []Mania // Each section is for a key count.
+}
+
+type General struct {
+	Name               string
+	Author             string
+	Version            string
+	AnimationFramerate float64 // -1 means the framerate is not set.
+}
+
+// Images of digits are named "<Prefix>-<Digit>", e.g., "score-0".
+type Fonts struct {
+	HitCirclePrefix  string
+	HitCircleOverlap float64
+	ScorePrefix      string
+	ScoreOverlap     float64
+	ComboPrefix      string
+	ComboOverlap     float64
+}
+
+// Values of each column are in order of columns.
+// ColumnLineWidth has values of the borders: one more than the columns.
+// ScorePosition and ComboPosition are 0 when not set.
+type Mania struct {
+	Keys                    int
+	ColumnStart             float64
+	ColumnRight             float64
+	ColumnSpacing           []float64
+	ColumnWidth             []float64
+	ColumnLineWidth         []float64
+	BarlineHeight           float64
+	LightingNWidth          []float64
+	LightingLWidth          []float64
+	WidthForNoteHeightScale float64 // 0 means the smallest column width is used.
+	HitPosition             float64
+	LightPosition           float64
+	ScorePosition           float64 // Position of judgments.
+	ComboPosition           float64
+	JudgementLine           bool
+	UpsideDown              bool
+
+	Colours map[string]color.NRGBA // e.g., Colour1, ColourLight1, ColourJudgementLine.
+	Values  map[string]string      // Image names and other values, e.g., NoteImage0H, LightingN.
+}
+
+const (
+	DefaultColumnStart     = 136
+	DefaultColumnRight     = 19
+	DefaultColumnWidth     = 30
+	DefaultColumnLineWidth = 2
+	DefaultBarlineHeight   = 1.2
+	DefaultHitPosition     = 402
+	DefaultLightPosition   = 413
+)
+
+func newFormat() *Format {
+	return &Format{
+		General: General{
+			Version:            "1.0",
+			AnimationFramerate: -1,
+		},
+		Colours: make(map[string]color.NRGBA),
+		Fonts: Fonts{
+			HitCirclePrefix:  "default",
+			HitCircleOverlap: -2,
+			ScorePrefix:      "score",
+			ScoreOverlap:     0,
+			ComboPrefix:      "score",
+			ComboOverlap:     0,
+		},
+	}
+}
+
+func newMania() Mania {
+	return Mania{
+		ColumnStart:   DefaultColumnStart,
+		ColumnRight:   DefaultColumnRight,
+		BarlineHeight: DefaultBarlineHeight,
+		HitPosition:   DefaultHitPosition,
+		LightPosition: DefaultLightPosition,
+		Colours:       make(map[string]color.NRGBA),
+		Values:        make(map[string]string),
+	}
+}
+
+// Mania returns the section for the key count.
+func (f Format) Mania(keys int) (Mania, bool) {
+	for _, m := range f.Manias {
+		if m.Keys == keys {
+			return m, true
+		}
+	}
+	return Mania{}, false
+}
+
+// Value returns the value of the key or the default value when not set.
+func (m Mania) Value(key, def string) string {
+	if v, ok := m.Values[key]; ok && v != "" {
+		return v
+	}
+	return def
+}
+
+// Colour returns the colour of the key or the default colour when not set.
+func (m Mania) Colour(key string, def color.NRGBA) color.NRGBA {
+	if c, ok := m.Colours[key]; ok {
+		return c
+	}
+	return def
+}
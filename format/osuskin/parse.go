@@ -0,0 +1,198 @@
+package osuskin
+
+import (
+	"bytes"
+	"fmt"
+	"image/color"
+	"strconv"
+	"strings"
+)
+
+// Parse returns the default values for empty data,
+// since skin.ini is optional for osu! skin.
+// Malformed lines are skipped; the first error is returned along with the result.
+func Parse(dat []byte) (*Format, error) {
+	f := newFormat()
+	var firstErr error
+	dat = bytes.ReplaceAll(dat, []byte("\r\n"), []byte("\n"))
+	dat = bytes.TrimPrefix(dat, []byte("\ufeff"))
+
+	var section string
+	for _, l := range bytes.Split(dat, []byte("\n")) {
+		line := strings.TrimSpace(string(l))
+		if line == "" || strings.HasPrefix(line, "//") {
+			continue
+		}
+		if line[0] == '[' && line[len(line)-1] == ']' {
+			section = line[1 : len(line)-1]
+			if section == "Mania" {
+				f.Manias = append(f.Manias, newMania())
+			}
+			continue
+		}
+		kv := strings.SplitN(line, ":", 2)
+		if len(kv) < 2 {
+			continue
+		}
+		k, v := strings.TrimSpace(kv[0]), strings.TrimSpace(kv[1])
+		var err error
+		switch section {
+		case "General":
+			err = f.setGeneral(k, v)
+		case "Colours":
+			var c color.NRGBA
+			if c, err = parseColour(v); err == nil {
+				f.Colours[k] = c
+			}
+		case "Fonts":
+			err = f.setFonts(k, v)
+		case "Mania":
+			err = f.Manias[len(f.Manias)-1].set(k, v)
+		}
+		if err != nil && firstErr == nil {
+			firstErr = fmt.Errorf("error at %s: %s", line, err)
+		}
+	}
+	for i := range f.Manias {
+		f.Manias[i].fill()
+	}
+	return f, firstErr
+}
+
+func (f *Format) setGeneral(k, v string) (err error) {
+	switch k {
+	case "Name":
+		f.Name = v
+	case "Author":
+		f.Author = v
+	case "Version":
+		f.Version = v
+	case "AnimationFramerate":
+		f.AnimationFramerate, err = strconv.ParseFloat(v, 64)
+	}
+	return
+}
+
+func (f *Format) setFonts(k, v string) (err error) {
+	switch k {
+	case "HitCirclePrefix":
+		f.HitCirclePrefix = v
+	case "HitCircleOverlap":
+		f.HitCircleOverlap, err = strconv.ParseFloat(v, 64)
+	case "ScorePrefix":
+		f.ScorePrefix = v
+	case "ScoreOverlap":
+		f.ScoreOverlap, err = strconv.ParseFloat(v, 64)
+	case "ComboPrefix":
+		f.ComboPrefix = v
+	case "ComboOverlap":
+		f.ComboOverlap, err = strconv.ParseFloat(v, 64)
+	}
+	return
+}
+
+func (m *Mania) set(k, v string) (err error) {
+	switch k {
+	case "Keys":
+		m.Keys, err = strconv.Atoi(v)
+	case "ColumnStart":
+		m.ColumnStart, err = strconv.ParseFloat(v, 64)
+	case "ColumnRight":
+		m.ColumnRight, err = strconv.ParseFloat(v, 64)
+	case "ColumnSpacing":
+		m.ColumnSpacing, err = parseFloats(v)
+	case "ColumnWidth":
+		m.ColumnWidth, err = parseFloats(v)
+	case "ColumnLineWidth":
+		m.ColumnLineWidth, err = parseFloats(v)
+	case "BarlineHeight":
+		m.BarlineHeight, err = strconv.ParseFloat(v, 64)
+	case "LightingNWidth":
+		m.LightingNWidth, err = parseFloats(v)
+	case "LightingLWidth":
+		m.LightingLWidth, err = parseFloats(v)
+	case "WidthForNoteHeightScale":
+		m.WidthForNoteHeightScale, err = strconv.ParseFloat(v, 64)
+	case "HitPosition":
+		m.HitPosition, err = strconv.ParseFloat(v, 64)
+	case "LightPosition":
+		m.LightPosition, err = strconv.ParseFloat(v, 64)
+	case "ScorePosition":
+		m.ScorePosition, err = strconv.ParseFloat(v, 64)
+	case "ComboPosition":
+		m.ComboPosition, err = strconv.ParseFloat(v, 64)
+	case "JudgementLine":
+		m.JudgementLine = v == "1"
+	case "UpsideDown":
+		m.UpsideDown = v == "1"
+	default:
+		if strings.HasPrefix(k, "Colour") {
+			var c color.NRGBA
+			if c, err = parseColour(v); err == nil {
+				m.Colours[k] = c
+			}
+			break
+		}
+		m.Values[k] = v
+	}
+	return
+}
+
+// fill completes values of each column with the last given or default value.
+func (m *Mania) fill() {
+	m.ColumnWidth = fillFloats(m.ColumnWidth, m.Keys, DefaultColumnWidth)
+	m.ColumnSpacing = fillFloats(m.ColumnSpacing, m.Keys-1, 0)
+	m.ColumnLineWidth = fillFloats(m.ColumnLineWidth, m.Keys+1, DefaultColumnLineWidth)
+	m.LightingNWidth = fillFloats(m.LightingNWidth, m.Keys, 0)
+	m.LightingLWidth = fillFloats(m.LightingLWidth, m.Keys, 0)
+}
+
+func fillFloats(vs []float64, n int, def float64) []float64 {
+	if n < 0 {
+		n = 0
+	}
+	if len(vs) >= n {
+		return vs[:n]
+	}
+	for len(vs) < n {
+		v := def
+		if len(vs) > 0 {
+			v = vs[len(vs)-1]
+		}
+		vs = append(vs, v)
+	}
+	return vs
+}
+
+func parseFloats(s string) ([]float64, error) {
+	ss := strings.Split(s, ",")
+	vs := make([]float64, 0, len(ss))
+	for _, s := range ss {
+		s = strings.TrimSpace(s)
+		if s == "" {
+			continue
+		}
+		v, err := strconv.ParseFloat(s, 64)
+		if err != nil {
+			return vs, err
+		}
+		vs = append(vs, v)
+	}
+	return vs, nil
+}
+
+// Colour is either "R,G,B" or "R,G,B,A".
+func parseColour(s string) (c color.NRGBA, err error) {
+	vs, err := parseFloats(s)
+	if err != nil {
+		return
+	}
+	if len(vs) < 3 {
+		return c, fmt.Errorf("invalid colour: %s", s)
+	}
+	c = color.NRGBA{uint8(vs[0]), uint8(vs[1]), uint8(vs[2]), 255}
+	if len(vs) >= 4 {
+		c.A = uint8(vs[3])
+	}
+	return
+}
@@ -0,0 +1,96 @@
+package osuskin
+
+import (
+	"image/color"
+	"reflect"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	const dat = "\ufeff[General]\r\nName: Skin\r\nAnimationFramerate: 30\r\n" +
+		"// comment\r\n" +
+		"[Colours]\r\nCombo1: 255,0,0\r\n" +
+		"[Fonts]\r\nComboPrefix: combo\r\nComboOverlap: 3\r\n" +
+		"[Mania]\r\nKeys: 4\r\nColumnWidth: 40,45\r\nColumnSpacing: 1\r\n" +
+		"LightingNWidth: 50\r\nHitPosition: 420\r\nUpsideDown: 1\r\n" +
+		"ColourLight1: 1,2,3,4\r\nNoteImage0: note\r\n" +
+		"[Mania]\r\nKeys: 7\r\n"
+	f, err := Parse([]byte(dat))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if f.Name != "Skin" || f.AnimationFramerate != 30 || f.Version != "1.0" {
+		t.Errorf("general: %+v", f.General)
+	}
+	if c := f.Colours["Combo1"]; c != (color.NRGBA{255, 0, 0, 255}) {
+		t.Errorf("colour: %v", c)
+	}
+	if f.ComboPrefix != "combo" || f.ComboOverlap != 3 || f.ScorePrefix != "score" {
+		t.Errorf("fonts: %+v", f.Fonts)
+	}
+	if len(f.Manias) != 2 {
+		t.Fatalf("%d mania sections, want 2", len(f.Manias))
+	}
+	m, ok := f.Mania(4)
+	if !ok {
+		t.Fatal("no section for 4 keys")
+	}
+	for _, tc := range []struct {
+		name      string
+		got, want []float64
+	}{
+		{"ColumnWidth", m.ColumnWidth, []float64{40, 45, 45, 45}},
+		{"ColumnSpacing", m.ColumnSpacing, []float64{1, 1, 1}},
+		{"ColumnLineWidth", m.ColumnLineWidth, []float64{2, 2, 2, 2, 2}},
+		{"LightingNWidth", m.LightingNWidth, []float64{50, 50, 50, 50}},
+		{"LightingLWidth", m.LightingLWidth, []float64{0, 0, 0, 0}},
+	} {
+		if !reflect.DeepEqual(tc.got, tc.want) {
+			t.Errorf("%s: %v, want %v", tc.name, tc.got, tc.want)
+		}
+	}
+	if m.HitPosition != 420 || m.LightPosition != DefaultLightPosition || !m.UpsideDown {
+		t.Errorf("mania: %+v", m)
+	}
+	if c := m.Colour("ColourLight1", color.NRGBA{}); c != (color.NRGBA{1, 2, 3, 4}) {
+		t.Errorf("mania colour: %v", c)
+	}
+	if v := m.Value("NoteImage0", "mania-note1"); v != "note" {
+		t.Errorf("NoteImage0: %s", v)
+	}
+	if v := m.Value("NoteImage1", "mania-note2"); v != "mania-note2" {
+		t.Errorf("NoteImage1: %s", v)
+	}
+	if m, _ := f.Mania(7); len(m.ColumnWidth) != 7 || m.ColumnWidth[6] != DefaultColumnWidth {
+		t.Errorf("7 keys: %v", m.ColumnWidth)
+	}
+}
+
+// Malformed lines are skipped, and the rest are parsed.
+func TestParseMalformed(t *testing.T) {
+	const dat = "[Mania]\nKeys: 4\nHitPosition: x\nColumnWidth: 10\nColourBarline: 1,2\n"
+	f, err := Parse([]byte(dat))
+	if err == nil {
+		t.Error("no error for malformed lines")
+	}
+	m, ok := f.Mania(4)
+	if !ok {
+		t.Fatal("no section for 4 keys")
+	}
+	if m.ColumnWidth[3] != 10 {
+		t.Errorf("mania: %+v", m)
+	}
+	if _, ok := m.Colours["ColourBarline"]; ok {
+		t.Error("malformed colour is set")
+	}
+}
+
+func TestParseEmpty(t *testing.T) {
+	f, err := Parse(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if f.Version != "1.0" || f.AnimationFramerate != -1 || len(f.Manias) != 0 {
+		t.Errorf("default: %+v", f)
+	}
+}
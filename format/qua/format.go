@@ -0,0 +1,167 @@
+package qua
+
+import (
+	"bufio"
+	"os"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Format is for Quaver's .qua file, which is written in YAML.
+// Fields with default value are often omitted.
+type Format struct {
+	AudioFile                      string              `yaml:"AudioFile"`
+	SongPreviewTime                int                 `yaml:"SongPreviewTime"`
+	BackgroundFile                 string              `yaml:"BackgroundFile"`
+	BannerFile                     string              `yaml:"BannerFile"`
+	MapId                          int                 `yaml:"MapId"`
+	MapSetId                       int                 `yaml:"MapSetId"`
+	Mode                           string              `yaml:"Mode"` // Keys4 or Keys7.
+	Title                          string              `yaml:"Title"`
+	Artist                         string              `yaml:"Artist"`
+	Source                         string              `yaml:"Source"`
+	Tags                           string              `yaml:"Tags"`
+	Creator                        string              `yaml:"Creator"`
+	DifficultyName                 string              `yaml:"DifficultyName"`
+	Description                    string              `yaml:"Description"`
+	Genre                          string              `yaml:"Genre"`
+	BPMDoesNotAffectScrollVelocity bool                `yaml:"BPMDoesNotAffectScrollVelocity"`
+	InitialScrollVelocity          *float64            `yaml:"InitialScrollVelocity"`
+	HasScratchKey                  bool                `yaml:"HasScratchKey"`
+	CustomAudioSamples             []CustomAudioSample `yaml:"CustomAudioSamples"`
+	SoundEffects                   []SoundEffect       `yaml:"SoundEffects"`
+	TimingPoints                   []TimingPoint       `yaml:"TimingPoints"`
+	SliderVelocities               []SliderVelocity    `yaml:"SliderVelocities"`
+	HitObjects                     []HitObject         `yaml:"HitObjects"`
+}
+
+type CustomAudioSample struct {
+	Path             string `yaml:"Path"`
+	UnaffectedByRate bool   `yaml:"UnaffectedByRate"`
+}
+
+// SoundEffect is played automatically at StartTime.
+// Sample is 1-based index of CustomAudioSamples.
+type SoundEffect struct {
+	StartTime float64 `yaml:"StartTime"`
+	Sample    int     `yaml:"Sample"`
+	Volume    int     `yaml:"Volume"`
+}
+
+// Signature is either a name such as "Quadruple" or a number.
+type TimingPoint struct {
+	StartTime float64 `yaml:"StartTime"`
+	Bpm       float64 `yaml:"Bpm"`
+	Signature string  `yaml:"Signature"`
+	Hidden    bool    `yaml:"Hidden"`
+}
+
+type SliderVelocity struct {
+	StartTime  float64 `yaml:"StartTime"`
+	Multiplier float64 `yaml:"Multiplier"`
+}
+
+// Lane is 1-based. EndTime is non-zero for long notes.
+type HitObject struct {
+	StartTime int        `yaml:"StartTime"`
+	Lane      int        `yaml:"Lane"`
+	EndTime   int        `yaml:"EndTime"`
+	HitSound  string     `yaml:"HitSound"`
+	KeySounds []KeySound `yaml:"KeySounds"`
+}
+
+// Sample is 1-based index of CustomAudioSamples.
+type KeySound struct {
+	Sample int `yaml:"Sample"`
+	Volume int `yaml:"Volume"`
+}
+
+func Parse(dat []byte) (*Format, error) {
+	var f Format
+	if err := yaml.Unmarshal(dat, &f); err != nil {
+		return nil, err
+	}
+	return &f, nil
+}
+
+// Meter returns the number of beats in a measure.
+func (tp TimingPoint) Meter() int {
+	switch tp.Signature {
+	case "Triple", "3":
+		return 3
+	default:
+		return 4
+	}
+}
+
+// ScrollVelocity returns initial scroll velocity. Default value is 1.
+func (f Format) ScrollVelocity() float64 {
+	if f.InitialScrollVelocity == nil {
+		return 1
+	}
+	return *f.InitialScrollVelocity
+}
+
+// KeyCount returns the number of keys including scratch key.
+func (f Format) KeyCount() int {
+	count := keyCount(f.Mode)
+	if f.HasScratchKey {
+		count++
+	}
+	return count
+}
+
+func keyCount(mode string) int {
+	switch mode {
+	case "Keys4":
+		return 4
+	case "Keys7":
+		return 7
+	}
+	return 0
+}
+
+// KeyCount reads key count from the file without parsing whole file.
+// Scratch key is counted as Format.KeyCount does. It returns 0 when failed.
+func KeyCount(path string) int {
+	f, err := os.Open(path)
+	if err != nil {
+		return 0
+	}
+	defer f.Close()
+	var (
+		count   int
+		scratch bool
+	)
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if strings.HasPrefix(line, "HitObjects:") { // Header fields are placed before.
+			break
+		}
+		kv := strings.SplitN(line, ":", 2)
+		if len(kv) < 2 {
+			continue
+		}
+		switch v := strings.TrimSpace(kv[1]); kv[0] {
+		case "Mode":
+			count = keyCount(v)
+		case "HasScratchKey":
+			scratch, _ = strconv.ParseBool(v)
+		}
+	}
+	if count > 0 && scratch {
+		count++
+	}
+	return count
+}
+
+// Sample returns a path of the sample with 1-based index.
+func (f Format) Sample(index int) string {
+	if index < 1 || index > len(f.CustomAudioSamples) {
+		return ""
+	}
+	return f.CustomAudioSamples[index-1].Path
+}
@@ -0,0 +1,36 @@
+package qua
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// KeyCount from the file should agree with the parsed one.
+func TestKeyCount(t *testing.T) {
+	dir := t.TempDir()
+	for _, tc := range []struct {
+		dat  string
+		want int
+	}{
+		{"Mode: Keys4\nHasScratchKey: true\nHitObjects:\n- StartTime: 0\n  Lane: 1\n", 5},
+		{"HasScratchKey: false\nMode: Keys7\nHitObjects: []\n", 7},
+		{"Mode: Keys7\r\nHasScratchKey: true\r\n", 8},
+		{"Mode: Keys5\n", 0},
+	} {
+		path := filepath.Join(dir, "a.qua")
+		if err := os.WriteFile(path, []byte(tc.dat), 0644); err != nil {
+			t.Fatal(err)
+		}
+		if got := KeyCount(path); got != tc.want {
+			t.Errorf("%q: KeyCount %d, want %d", tc.dat, got, tc.want)
+		}
+		f, err := Parse([]byte(tc.dat))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := f.KeyCount(); got != tc.want {
+			t.Errorf("%q: Format.KeyCount %d, want %d", tc.dat, got, tc.want)
+		}
+	}
+}
@@ -0,0 +1,144 @@
+package sm
+
+import "sort"
+
+// Format is for StepMania's .sm and .ssc file.
+// A file has multiple charts. In .ssc, each chart may have own timing.
+type Format struct {
+	Header
+	Timing
+	Charts []Chart
+}
+
+type Header struct {
+	Title            string
+	SubTitle         string
+	Artist           string
+	TitleTranslit    string
+	SubTitleTranslit string
+	ArtistTranslit   string
+	Genre            string
+	Credit           string
+	Banner           string
+	Background       string
+	Music            string
+	SampleStart      float64 // In seconds.
+	SampleLength     float64
+}
+
+// Timing has values at beats. Stops and Delays are in seconds.
+// Difference between Stop and Delay is that
+// notes at the beat of Delay come after the pause.
+type Timing struct {
+	Offset  float64 // In seconds. Time of beat 0 is -Offset.
+	BPMs    []BeatValue
+	Stops   []BeatValue
+	Delays  []BeatValue
+	Scrolls []BeatValue // .ssc only.
+	Speeds  []Speed     // .ssc only.
+}
+
+type BeatValue struct {
+	Beat  float64
+	Value float64
+}
+
+// Speed changes scroll speed to Ratio gradually through Duration.
+// Duration is in beats when Unit is 0, in seconds otherwise.
+type Speed struct {
+	Beat     float64
+	Ratio    float64
+	Duration float64
+	Unit     int
+}
+
+type Chart struct {
+	StepsType    string
+	Description  string
+	Difficulty   string
+	Meter        int
+	Credit       string
+	Timing              // The song's timing is copied when the chart has no own timing.
+	Notes        []Note // Sorted by Time.
+	MeasureCount int
+}
+
+// Note types.
+const (
+	Tap = iota
+	Hold
+	Roll
+)
+
+type Note struct {
+	Beat     float64
+	Time     float64 // In milliseconds.
+	Key      int
+	Type     int
+	Duration float64 // In milliseconds. Non-zero for holds and rolls.
+}
+
+// Single is a Format with one chart.
+type Single struct {
+	Header
+	Chart
+}
+
+func (f Format) Single(i int) *Single {
+	return &Single{
+		Header: f.Header,
+		Chart:  f.Charts[i],
+	}
+}
+
+var keyCounts = map[string]int{
+	"dance-single": 4,
+	"dance-double": 8,
+	"dance-couple": 8,
+	"dance-solo":   6,
+	"pump-single":  5,
+	"pump-double":  10,
+	"kb7-single":   7,
+}
+
+// KeyCount returns 0 when the steps type is unknown.
+func (c Chart) KeyCount() int { return keyCounts[c.StepsType] }
+
+// Time returns time of the beat in milliseconds.
+// Stops at the beat are not applied, while delays at the beat are applied.
+// Todo: support negative BPMs and warps
+func (t Timing) Time(beat float64) float64 {
+	time := -t.Offset * 1000
+	if len(t.BPMs) == 0 {
+		return time
+	}
+	var last float64
+	bpm := t.BPMs[0].Value
+	for _, b := range t.BPMs[1:] {
+		if b.Beat >= beat {
+			break
+		}
+		time += (b.Beat - last) * 60000 / bpm
+		last = b.Beat
+		bpm = b.Value
+	}
+	time += (beat - last) * 60000 / bpm
+	for _, s := range t.Stops {
+		if s.Beat < beat {
+			time += s.Value * 1000
+		}
+	}
+	for _, d := range t.Delays {
+		if d.Beat <= beat {
+			time += d.Value * 1000
+		}
+	}
+	return time
+}
+
+func (t *Timing) sort() {
+	for _, vs := range [][]BeatValue{t.BPMs, t.Stops, t.Delays, t.Scrolls} {
+		sort.SliceStable(vs, func(i, j int) bool { return vs[i].Beat < vs[j].Beat })
+	}
+	sort.SliceStable(t.Speeds, func(i, j int) bool { return t.Speeds[i].Beat < t.Speeds[j].Beat })
+}
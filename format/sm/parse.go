@@ -0,0 +1,285 @@
+package sm
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+type chartBuilder struct {
+	Chart
+	data      string // Raw note data.
+	hasOffset bool
+	err       error // The chart is skipped when it has an error.
+}
+
+// Parse parses both .sm and .ssc.
+// In .ssc, tags after #NOTEDATA belong to the chart.
+func Parse(dat []byte) (*Format, error) {
+	f := &Format{Charts: make([]Chart, 0)}
+	builders := make([]*chartBuilder, 0)
+	var cb *chartBuilder // Current chart of .ssc.
+	for _, tag := range splitTags(removeComments(string(dat))) {
+		kv := strings.SplitN(tag, ":", 2)
+		name := strings.ToUpper(strings.TrimSpace(kv[0]))
+		var value string
+		if len(kv) == 2 {
+			value = strings.TrimSpace(kv[1])
+		}
+		timing := &f.Timing
+		if cb != nil {
+			timing = &cb.Timing
+		}
+		var err error
+		switch name {
+		case "TITLE":
+			f.Title = value
+		case "SUBTITLE":
+			f.SubTitle = value
+		case "ARTIST":
+			f.Artist = value
+		case "TITLETRANSLIT":
+			f.TitleTranslit = value
+		case "SUBTITLETRANSLIT":
+			f.SubTitleTranslit = value
+		case "ARTISTTRANSLIT":
+			f.ArtistTranslit = value
+		case "GENRE":
+			f.Genre = value
+		case "BANNER":
+			f.Banner = value
+		case "BACKGROUND":
+			f.Background = value
+		case "MUSIC":
+			f.Music = value
+		case "SAMPLESTART":
+			f.SampleStart, err = parseFloat(value)
+		case "SAMPLELENGTH":
+			f.SampleLength, err = parseFloat(value)
+		case "CREDIT":
+			if cb != nil {
+				cb.Credit = value
+			} else {
+				f.Credit = value
+			}
+
+		case "OFFSET":
+			timing.Offset, err = parseFloat(value)
+			if cb != nil {
+				cb.hasOffset = true
+			}
+		case "BPMS":
+			timing.BPMs, err = parseBeatValues(value)
+		case "STOPS", "FREEZES":
+			timing.Stops, err = parseBeatValues(value)
+		case "DELAYS":
+			timing.Delays, err = parseBeatValues(value)
+		case "SCROLLS":
+			timing.Scrolls, err = parseBeatValues(value)
+		case "SPEEDS":
+			timing.Speeds, err = parseSpeeds(value)
+
+		case "NOTEDATA":
+			cb = &chartBuilder{}
+			builders = append(builders, cb)
+		case "STEPSTYPE":
+			if cb != nil {
+				cb.StepsType = value
+			}
+		case "DESCRIPTION":
+			if cb != nil {
+				cb.Description = value
+			}
+		case "DIFFICULTY":
+			if cb != nil {
+				cb.Difficulty = value
+			}
+		case "METER":
+			if cb != nil {
+				cb.Meter, cb.err = strconv.Atoi(value)
+			}
+		case "NOTES":
+			if cb != nil { // .ssc
+				cb.data = value
+				continue
+			}
+			// .sm: #NOTES:<type>:<description>:<difficulty>:<meter>:<radar values>:<data>;
+			vs := strings.SplitN(value, ":", 6)
+			if len(vs) != 6 {
+				return f, fmt.Errorf("error at NOTES: %d fields", len(vs))
+			}
+			b := &chartBuilder{data: vs[5]}
+			b.StepsType = strings.TrimSpace(vs[0])
+			b.Description = strings.TrimSpace(vs[1])
+			b.Difficulty = strings.TrimSpace(vs[2])
+			b.Meter, _ = strconv.Atoi(strings.TrimSpace(vs[3]))
+			builders = append(builders, b)
+		}
+		if err != nil {
+			return f, fmt.Errorf("error at %s: %s", name, err)
+		}
+	}
+	f.Timing.sort()
+	for _, b := range builders {
+		if b.err != nil {
+			continue
+		}
+		b.inherit(f.Timing)
+		if len(b.BPMs) == 0 {
+			return f, fmt.Errorf("no BPMs in %s chart", b.Difficulty)
+		}
+		b.setNotes()
+		f.Charts = append(f.Charts, b.Chart)
+	}
+	return f, nil
+}
+
+func removeComments(s string) string {
+	lines := strings.Split(s, "\n")
+	for i, line := range lines {
+		if pos := strings.Index(line, "//"); pos != -1 {
+			lines[i] = line[:pos]
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+// splitTags returns texts of tags: #<text>;
+func splitTags(s string) []string {
+	tags := make([]string, 0)
+	for {
+		start := strings.IndexByte(s, '#')
+		if start == -1 {
+			break
+		}
+		s = s[start+1:]
+		end := strings.IndexByte(s, ';')
+		if end == -1 {
+			tags = append(tags, s)
+			break
+		}
+		tags = append(tags, s[:end])
+		s = s[end+1:]
+	}
+	return tags
+}
+
+func parseFloat(s string) (float64, error) {
+	if s == "" {
+		return 0, nil
+	}
+	return strconv.ParseFloat(s, 64)
+}
+
+// Format: <beat>=<value>,<beat>=<value>,...
+func parseBeatValues(s string) ([]BeatValue, error) {
+	vs := make([]BeatValue, 0)
+	for _, pair := range strings.Split(s, ",") {
+		if strings.TrimSpace(pair) == "" {
+			continue
+		}
+		kv := strings.Split(pair, "=")
+		if len(kv) < 2 {
+			return vs, fmt.Errorf("invalid pair: %s", pair)
+		}
+		beat, err := parseFloat(strings.TrimSpace(kv[0]))
+		if err != nil {
+			return vs, err
+		}
+		value, err := parseFloat(strings.TrimSpace(kv[1]))
+		if err != nil {
+			return vs, err
+		}
+		vs = append(vs, BeatValue{beat, value})
+	}
+	return vs, nil
+}
+
+// Format: <beat>=<ratio>=<duration>=<unit>,...
+func parseSpeeds(s string) ([]Speed, error) {
+	speeds := make([]Speed, 0)
+	for _, group := range strings.Split(s, ",") {
+		if strings.TrimSpace(group) == "" {
+			continue
+		}
+		fs := strings.Split(group, "=")
+		if len(fs) < 3 {
+			return speeds, fmt.Errorf("invalid speed: %s", group)
+		}
+		var vs [4]float64
+		for i := 0; i < len(fs) && i < 4; i++ {
+			v, err := parseFloat(strings.TrimSpace(fs[i]))
+			if err != nil {
+				return speeds, err
+			}
+			vs[i] = v
+		}
+		speeds = append(speeds, Speed{vs[0], vs[1], vs[2], int(vs[3])})
+	}
+	return speeds, nil
+}
+
+// inherit copies the song's timing to what the chart doesn't have.
+func (b *chartBuilder) inherit(t Timing) {
+	if !b.hasOffset {
+		b.Offset = t.Offset
+	}
+	if b.BPMs == nil {
+		b.BPMs = t.BPMs
+	}
+	if b.Stops == nil {
+		b.Stops = t.Stops
+	}
+	if b.Delays == nil {
+		b.Delays = t.Delays
+	}
+	if b.Scrolls == nil {
+		b.Scrolls = t.Scrolls
+	}
+	if b.Speeds == nil {
+		b.Speeds = t.Speeds
+	}
+	b.Timing.sort()
+}
+
+// Measures are separated by comma. Each measure has rows in same interval.
+// Characters of a row: 0 for empty, 1 for tap, 2 for hold head,
+// 3 for hold and roll tail, 4 for roll head, M for mine, L for lift, F for fake.
+func (b *chartBuilder) setNotes() {
+	b.Notes = make([]Note, 0)
+	heads := make(map[int]int) // Index of pending head for each key.
+	measures := strings.Split(b.data, ",")
+	b.MeasureCount = len(measures)
+	for m, measure := range measures {
+		rows := strings.Fields(measure)
+		for i, row := range rows {
+			beat := 4 * (float64(m) + float64(i)/float64(len(rows)))
+			for k, c := range row {
+				n := Note{Beat: beat, Key: k}
+				switch c {
+				case '1', 'L':
+					n.Type = Tap
+				case '2':
+					n.Type = Hold
+					heads[k] = len(b.Notes)
+				case '4':
+					n.Type = Roll
+					heads[k] = len(b.Notes)
+				case '3':
+					if j, ok := heads[k]; ok {
+						head := &b.Notes[j]
+						head.Duration = b.Time(beat) - b.Time(head.Beat)
+						delete(heads, k)
+					}
+					continue
+				default: // Mines and fakes are not supported.
+					continue
+				}
+				n.Time = b.Time(beat)
+				b.Notes = append(b.Notes, n)
+			}
+		}
+	}
+	sort.SliceStable(b.Notes, func(i, j int) bool { return b.Notes[i].Time < b.Notes[j].Time })
+}
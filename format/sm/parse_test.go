@@ -0,0 +1,45 @@
+package sm
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseNotes(t *testing.T) {
+	const dat = "#TITLE:Song;\n#OFFSET:0;\n#BPMS:0=120;\n" +
+		"#NOTES:dance-single:Someone:Hard:9:0,0,0,0,0:\n" +
+		"1000\n2040\n0000\n3030\n,\n0000\n0000\n0000\nM00F\n;"
+	f, err := Parse([]byte(dat))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(f.Charts) != 1 {
+		t.Fatalf("charts: %d", len(f.Charts))
+	}
+	c := f.Charts[0]
+	if c.Difficulty != "Hard" || c.Meter != 9 || c.MeasureCount != 2 {
+		t.Errorf("chart: %s %d, %d measures", c.Difficulty, c.Meter, c.MeasureCount)
+	}
+	want := []Note{
+		{Beat: 0, Time: 0, Key: 0, Type: Tap},
+		{Beat: 1, Time: 500, Key: 0, Type: Hold, Duration: 1000},
+		{Beat: 1, Time: 500, Key: 2, Type: Roll, Duration: 1000},
+	}
+	if !reflect.DeepEqual(c.Notes, want) {
+		t.Errorf("notes:\n got %+v\nwant %+v", c.Notes, want)
+	}
+}
+
+// A chart with invalid meter is skipped while others are kept.
+func TestParseInvalidMeter(t *testing.T) {
+	const dat = "#TITLE:Song;\n#BPMS:0=120;\n" +
+		"#NOTEDATA:;\n#STEPSTYPE:dance-single;\n#DIFFICULTY:Easy;\n#METER:?;\n#NOTES:1000\n;\n" +
+		"#NOTEDATA:;\n#STEPSTYPE:dance-single;\n#DIFFICULTY:Hard;\n#METER:10;\n#NOTES:0100\n;"
+	f, err := Parse([]byte(dat))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(f.Charts) != 1 || f.Charts[0].Difficulty != "Hard" || f.Charts[0].Meter != 10 {
+		t.Errorf("charts: %+v", f.Charts)
+	}
+}
@@ -0,0 +1,69 @@
+package tja
+
+// Format is for Taiko Jiro's .tja file. A file has multiple courses.
+// Times of all objects are calculated in milliseconds when parsing.
+type Format struct {
+	Header
+	Courses []Course
+}
+
+type Header struct {
+	Title     string
+	SubTitle  string
+	Genre     string
+	Wave      string  // Music file name.
+	BPM       float64 // Initial BPM.
+	Offset    float64 // In seconds. Time of the first measure is -Offset.
+	DemoStart float64 // In seconds.
+}
+
+type Course struct {
+	Course   int // Index of Courses.
+	Level    int
+	Balloons []int // Required hits of each balloon in order.
+	Notes    []Note
+	Points   []Point
+}
+
+var Courses = []string{"Easy", "Normal", "Hard", "Oni", "Edit"}
+
+// Note types.
+const (
+	Don = iota
+	Ka
+	Roll
+	Balloon
+)
+
+type Note struct {
+	Time     float64
+	Type     int
+	Big      bool
+	Duration float64 // For Roll and Balloon.
+	Hits     int     // For Balloon.
+}
+
+// Point is a snapshot of states. A new point is made whenever a state changes.
+// Stop is true while #DELAY goes.
+type Point struct {
+	Time       float64
+	BPM        float64
+	Scroll     float64
+	GoGo       bool
+	Stop       bool
+	NewMeasure bool
+	Length     float64 // Length of measure: ratio to 4/4.
+}
+
+// Single is a Format with one course.
+type Single struct {
+	Header
+	Course
+}
+
+func (f Format) Single(i int) *Single {
+	return &Single{
+		Header: f.Header,
+		Course: f.Courses[i],
+	}
+}
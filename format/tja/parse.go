@@ -0,0 +1,336 @@
+package tja
+
+import (
+	"bytes"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Only the master branch is parsed in a branched section.
+// Todo: support Shift-JIS encoded texts
+// Todo: support double play
+func Parse(dat []byte) (*Format, error) {
+	f := &Format{
+		Header:  Header{BPM: 120},
+		Courses: make([]Course, 0),
+	}
+	var (
+		c  = Course{Course: 3} // Oni is the default course.
+		p  *courseParser
+		no int // Line number.
+	)
+	dat = bytes.ReplaceAll(dat, []byte("\r\n"), []byte("\n"))
+	for _, l := range bytes.Split(dat, []byte("\n")) {
+		no++
+		line := string(l)
+		if pos := strings.Index(line, "//"); pos != -1 {
+			line = line[:pos]
+		}
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+		if p != nil { // Inside of #START and #END.
+			if strings.HasPrefix(strings.ToUpper(line), "#END") {
+				if !p.skip {
+					f.Courses = append(f.Courses, p.course())
+				}
+				c = Course{Course: c.Course, Level: c.Level}
+				p = nil
+				continue
+			}
+			if err := p.parseLine(line); err != nil {
+				return f, fmt.Errorf("error at line %d: %s", no, err)
+			}
+			continue
+		}
+		if strings.HasPrefix(strings.ToUpper(line), "#START") {
+			if strings.Contains(line[len("#START"):], "P2") { // Todo: double play
+				p = &courseParser{skip: true}
+				continue
+			}
+			p = newCourseParser(f.Header, c)
+			continue
+		}
+
+		kv := strings.SplitN(line, ":", 2)
+		if len(kv) < 2 {
+			continue
+		}
+		key := strings.ToUpper(strings.TrimSpace(kv[0]))
+		value := strings.TrimSpace(kv[1])
+		var err error
+		switch key {
+		case "TITLE":
+			f.Title = value
+		case "SUBTITLE":
+			f.SubTitle = value
+		case "GENRE":
+			f.Genre = value
+		case "WAVE":
+			f.Wave = value
+		case "BPM":
+			f.BPM, err = strconv.ParseFloat(value, 64)
+		case "OFFSET":
+			f.Offset, err = strconv.ParseFloat(value, 64)
+		case "DEMOSTART":
+			f.DemoStart, err = strconv.ParseFloat(value, 64)
+		case "COURSE":
+			c.Course = parseCourse(value)
+		case "LEVEL":
+			c.Level, err = strconv.Atoi(value)
+		case "BALLOON":
+			c.Balloons = make([]int, 0)
+			for _, s := range strings.Split(value, ",") {
+				if s = strings.TrimSpace(s); s == "" {
+					continue
+				}
+				v, err := strconv.Atoi(s)
+				if err != nil {
+					return f, fmt.Errorf("error at line %d: %s", no, err)
+				}
+				c.Balloons = append(c.Balloons, v)
+			}
+		}
+		if err != nil {
+			return f, fmt.Errorf("error at line %d: %s", no, err)
+		}
+	}
+	return f, nil
+}
+
+func parseCourse(s string) int {
+	if v, err := strconv.Atoi(s); err == nil {
+		return v
+	}
+	for i, name := range Courses {
+		if strings.EqualFold(name, s) {
+			return i
+		}
+	}
+	if strings.EqualFold(s, "Ura") {
+		return 4
+	}
+	return 3
+}
+
+type item struct {
+	char    byte
+	command string
+}
+
+type courseParser struct {
+	Course
+	skip       bool
+	skipBranch bool   // Lines in normal and expert branches are skipped.
+	items      []item // Items of a measure being read.
+	balloon    int    // Index of next balloon.
+	pending    *Note  // Roll or Balloon not ended yet.
+
+	time   float64
+	bpm    float64
+	scroll float64
+	gogo   bool
+	length float64
+}
+
+func newCourseParser(h Header, c Course) *courseParser {
+	p := &courseParser{
+		Course: c,
+		time:   -h.Offset * 1000,
+		bpm:    h.BPM,
+		scroll: 1,
+		length: 1,
+	}
+	p.Notes = make([]Note, 0)
+	p.Points = make([]Point, 0)
+	return p
+}
+
+func (p *courseParser) course() Course {
+	if len(p.items) > 0 { // Measure without comma at the end.
+		p.flush()
+	}
+	return p.Course
+}
+
+func (p *courseParser) parseLine(line string) error {
+	if p.skip {
+		return nil
+	}
+	if fs := strings.Fields(line[1:]); line[0] == '#' && len(fs) > 0 {
+		switch strings.ToUpper(fs[0]) {
+		case "N", "E":
+			p.skipBranch = true
+			return nil
+		case "M", "BRANCHSTART", "BRANCHEND":
+			p.skipBranch = false
+			return nil
+		}
+	}
+	if p.skipBranch {
+		return nil
+	}
+	if line[0] == '#' {
+		p.items = append(p.items, item{command: line[1:]})
+		return nil
+	}
+	for i := 0; i < len(line); i++ {
+		switch c := line[i]; {
+		case c == ',':
+			if err := p.flush(); err != nil {
+				return err
+			}
+		case c >= '0' && c <= '9' || c >= 'A' && c <= 'Z':
+			p.items = append(p.items, item{char: c})
+		}
+	}
+	return nil
+}
+
+// flush processes items of a measure.
+// Commands before the first note take effect at the start of the measure.
+func (p *courseParser) flush() error {
+	defer func() { p.items = p.items[:0] }()
+	var i int
+	for ; i < len(p.items) && p.items[i].command != ""; i++ {
+		if err := p.command(p.items[i].command); err != nil {
+			return err
+		}
+	}
+	var count int
+	for _, it := range p.items {
+		if it.command == "" {
+			count++
+		}
+	}
+	p.addPoint(true, false)
+	beats := 4 * p.length
+	if count == 0 {
+		p.time += beats * 60000 / p.bpm
+		return nil
+	}
+	step := beats / float64(count)
+	for ; i < len(p.items); i++ {
+		it := p.items[i]
+		if it.command != "" {
+			if err := p.command(it.command); err != nil {
+				return err
+			}
+			continue
+		}
+		p.note(it.char)
+		p.time += step * 60000 / p.bpm
+	}
+	return nil
+}
+
+func (p *courseParser) command(cmd string) error {
+	kv := strings.Fields(cmd)
+	if len(kv) == 0 {
+		return nil
+	}
+	var (
+		v   float64
+		err error
+	)
+	switch strings.ToUpper(kv[0]) {
+	case "BPMCHANGE":
+		if v, err = parseValue(kv); err != nil || v <= 0 {
+			return err
+		}
+		p.bpm = v
+	case "SCROLL":
+		if v, err = parseValue(kv); err != nil {
+			return err
+		}
+		p.scroll = v
+	case "MEASURE":
+		if len(kv) < 2 {
+			return nil
+		}
+		var a, b float64
+		if _, err := fmt.Sscanf(kv[1], "%g/%g", &a, &b); err != nil {
+			return fmt.Errorf("invalid measure: %s", kv[1])
+		}
+		if a > 0 && b > 0 {
+			p.length = a / b
+		}
+		return nil // Length is applied from the start of a measure.
+	case "DELAY":
+		if v, err = parseValue(kv); err != nil || v <= 0 {
+			return err
+		}
+		p.addPoint(false, true)
+		p.time += v * 1000
+	case "GOGOSTART":
+		p.gogo = true
+	case "GOGOEND":
+		p.gogo = false
+	default: // Todo: #BARLINEOFF, #BARLINEON
+		return nil
+	}
+	p.addPoint(false, false)
+	return nil
+}
+
+func parseValue(kv []string) (float64, error) {
+	if len(kv) < 2 {
+		return 0, fmt.Errorf("no value at %s", kv[0])
+	}
+	return strconv.ParseFloat(kv[1], 64)
+}
+
+func (p *courseParser) addPoint(newMeasure, stop bool) {
+	p.Points = append(p.Points, Point{
+		Time:       p.time,
+		BPM:        p.bpm,
+		Scroll:     p.scroll,
+		GoGo:       p.gogo,
+		Stop:       stop,
+		NewMeasure: newMeasure,
+		Length:     p.length,
+	})
+}
+
+// 1: Don, 2: Ka, 3: big Don, 4: big Ka, 5: Roll, 6: big Roll,
+// 7: Balloon, 8: end of Roll and Balloon, 9: big Balloon (Kusudama).
+// A and B are big Don and big Ka hit with both hands.
+func (p *courseParser) note(c byte) {
+	n := Note{Time: p.time}
+	switch c {
+	case '1', '3', 'A':
+		n.Type = Don
+	case '2', '4', 'B':
+		n.Type = Ka
+	case '5', '6':
+		n.Type = Roll
+	case '7', '9':
+		n.Type = Balloon
+		if p.balloon < len(p.Balloons) {
+			n.Hits = p.Balloons[p.balloon]
+		}
+		p.balloon++
+	case '8':
+		if p.pending != nil {
+			p.pending.Duration = p.time - p.pending.Time
+			p.Notes = append(p.Notes, *p.pending)
+			p.pending = nil
+		}
+		return
+	default:
+		return
+	}
+	switch c {
+	case '3', '4', 'A', 'B', '6', '9':
+		n.Big = true
+	}
+	if n.Type == Roll || n.Type == Balloon {
+		if p.pending == nil {
+			p.pending = &n
+		}
+		return
+	}
+	p.Notes = append(p.Notes, n)
+}
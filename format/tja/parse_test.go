@@ -0,0 +1,90 @@
+package tja
+
+import (
+	"reflect"
+	"testing"
+)
+
+// A measure at 120 BPM in 4/4 lasts 2000ms.
+func TestParseNotes(t *testing.T) {
+	for _, tc := range []struct {
+		name   string
+		header string
+		notes  string
+		want   []Note
+	}{
+		{"don and ka", "", "1020,", []Note{
+			{Time: 0, Type: Don}, {Time: 1000, Type: Ka}}},
+		{"big", "", "3A4B,", []Note{
+			{Time: 0, Type: Don, Big: true}, {Time: 500, Type: Don, Big: true},
+			{Time: 1000, Type: Ka, Big: true}, {Time: 1500, Type: Ka, Big: true}}},
+		{"roll", "", "6008,", []Note{
+			{Time: 0, Type: Roll, Big: true, Duration: 1500}}},
+		{"balloon", "BALLOON:5,8\n", "7080,\n9080,", []Note{
+			{Time: 0, Type: Balloon, Duration: 1000, Hits: 5},
+			{Time: 2000, Type: Balloon, Big: true, Duration: 1000, Hits: 8}}},
+		{"offset", "OFFSET:1\n", "1,", []Note{
+			{Time: -1000, Type: Don}}},
+		{"bpm change", "", "1,\n#BPMCHANGE 240\n1,\n1,", []Note{
+			{Time: 0, Type: Don}, {Time: 2000, Type: Don}, {Time: 3000, Type: Don}}},
+		{"bpm change in measure", "", "1\n#BPMCHANGE 240\n1,\n1,", []Note{
+			{Time: 0, Type: Don}, {Time: 1000, Type: Don}, {Time: 1500, Type: Don}}},
+		{"measure", "", "#MEASURE 3/4\n11,\n1,", []Note{
+			{Time: 0, Type: Don}, {Time: 750, Type: Don}, {Time: 1500, Type: Don}}},
+		{"delay", "", "1,\n#DELAY 0.5\n1,", []Note{
+			{Time: 0, Type: Don}, {Time: 2500, Type: Don}}},
+		{"empty measure", "", "1,\n,\n1", []Note{
+			{Time: 0, Type: Don}, {Time: 4000, Type: Don}}},
+		{"branch", "", "1,\n#BRANCHSTART p,50,80\n#N\n#BPMCHANGE 60\n1,\n#E\n11,\n#M\n2,\n#BRANCHEND\n1,", []Note{
+			{Time: 0, Type: Don}, {Time: 2000, Type: Ka}, {Time: 4000, Type: Don}}},
+		{"comment", "", "1, // 2,", []Note{
+			{Time: 0, Type: Don}}},
+	} {
+		dat := "BPM:120\nOFFSET:0\n" + tc.header + "#START\n" + tc.notes + "\n#END\n"
+		f, err := Parse([]byte(dat))
+		if err != nil {
+			t.Errorf("%s: %s", tc.name, err)
+			continue
+		}
+		if len(f.Courses) != 1 {
+			t.Errorf("%s: %d courses", tc.name, len(f.Courses))
+			continue
+		}
+		if got := f.Courses[0].Notes; !reflect.DeepEqual(got, tc.want) {
+			t.Errorf("%s:\n got %+v\nwant %+v", tc.name, got, tc.want)
+		}
+	}
+}
+
+// Course and level are kept until changed. Double play is skipped.
+func TestParseCourses(t *testing.T) {
+	const dat = "BPM:120\nCOURSE:Hard\nLEVEL:7\n#START\n1,\n#END\n" +
+		"#START P2\n1,\n#END\n" +
+		"COURSE:Ura\n#START\n1,\n#END\n"
+	f, err := Parse([]byte(dat))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got [][2]int
+	for _, c := range f.Courses {
+		got = append(got, [2]int{c.Course, c.Level})
+	}
+	if want := [][2]int{{2, 7}, {4, 7}}; !reflect.DeepEqual(got, want) {
+		t.Errorf("courses = %v; want %v", got, want)
+	}
+}
+
+func TestParseInvalid(t *testing.T) {
+	for _, dat := range []string{
+		"BPM:x\n",
+		"LEVEL:x\n",
+		"BALLOON:5,x\n",
+		"#START\n#BPMCHANGE x\n1,\n#END\n",
+		"#START\n#MEASURE x\n1,\n#END\n",
+		"#START\n#DELAY\n1,\n#END\n",
+	} {
+		if _, err := Parse([]byte(dat)); err == nil {
+			t.Errorf("Parse(%q): no error", dat)
+		}
+	}
+}
@@ -1,7 +1,9 @@
 package gosu
 
 import (
+	"fmt"
 	"runtime/debug"
+	"time"
 
 	"github.com/hajimehoshi/ebiten/v2"
 	"github.com/hndada/gosu/format/osr"
@@ -32,6 +34,7 @@ func NewGame(props []ModeProp) *Game {
 	modeProps = props
 	g := &Game{}
 	SetKeySettings(props)
+	ImportArchives(MusicRoot)
 	// 1. Load chart info and score data
 	// 2. Check removed chart
 	// 3. Check added chart
@@ -39,9 +42,17 @@ func NewGame(props []ModeProp) *Game {
 	LoadChartInfosSet(props)
 	TidyChartInfosSet(props)
 	for i, prop := range modeProps {
+		now := time.Now()
 		modeProps[i].ChartInfos = prop.LoadNewChartInfos(MusicRoot)
+		modeProps[i].LastUpdateTime = now
 	}
 	SaveChartInfosSet(props) // 4. Save chart infos to local file
+	_ = LoadCollections()
+	if OsuRoot != "" {
+		if err := ImportOsu(OsuRoot, modeProps); err != nil {
+			fmt.Printf("error at importing from osu!: %s\n", err)
+		}
+	}
 	LoadGeneralSkin()
 	for _, mode := range modeProps {
 		mode.LoadSkin()
@@ -103,10 +114,33 @@ func (g *Game) Update() (err error) {
 		ebiten.SetFPSMode(ebiten.FPSModeVsyncOffMaximum)
 		debug.SetGCPercent(0)
 		prop := modeProps[currentMode]
-		g.Scene, err = prop.NewScenePlay(args.Path, args.Replay)
+		g.Scene, err = prop.NewScenePlay(args.Path, args.Mods, args.Replay)
 		if err != nil {
 			return
 		}
+		if args.Demo {
+			g.Scene, err = NewSceneDemo(g.Scene)
+			return
+		}
+		if args.Practice && PracticeOpponent {
+			mods := args.Mods
+			mods.Auto = true
+			if o, err := prop.NewScenePlay(args.Path, mods, nil); err != nil {
+				fmt.Printf("error at loading opponent: %s\n", err)
+			} else if g.Scene, err = NewSceneOpponent(g.Scene, o); err != nil {
+				return err
+			}
+		}
+		g.Scene, err = NewScenePause(g.Scene)
+		if err != nil {
+			return
+		}
+		if args.Practice {
+			g.Scene, err = NewScenePractice(g.Scene)
+			if err != nil {
+				return
+			}
+		}
 	}
 	return
 }
@@ -119,9 +153,11 @@ func (g *Game) Layout(outsideWidth, outsideHeight int) (screenWidth, screenHeigh
 
 type SelectToPlayArgs struct {
 	// Mode int
-	// Mods   Mods
-	Path   string
-	Replay *osr.Format
+	Path     string
+	Mods     Mods
+	Replay   *osr.Format
+	Practice bool
+	Demo     bool // Auto plays the chart without pause menu.
 }
 
 type PlayToResultArgs struct {
@@ -0,0 +1,77 @@
+package gosu
+
+// Gauge types. Normal and Easy gauges are cleared when the value is
+// at least the clear value at the end. Others fail when the value runs out.
+const (
+	GaugeNormal = iota
+	GaugeEasy
+	GaugeHard
+	GaugeSuddenDeath
+	GaugePerfectOnly
+)
+
+var GaugeNames = []string{"Normal", "Easy", "Hard", "SuddenDeath", "PerfectOnly"}
+
+// Initial values and clear values of each gauge type.
+var (
+	GaugeInits  = []float64{0.2, 0.2, 1, 1, 1}
+	GaugeClears = []float64{0.8, 0.6, 0, 0, 0}
+)
+
+// Gauge is a health of the play. Each mode has a table of changes
+// by judgment for each gauge type, in the order of its judgments.
+// The play keeps going after failing with NoFail.
+type Gauge struct {
+	Type    int
+	Table   []float64
+	Value   float64 // Range is [0, 1].
+	NoFail  bool
+	Failed  bool
+	History []float64 // Value after each judgment.
+}
+
+func NewGauge(gaugeType int, tables [][]float64, noFail bool) Gauge {
+	g := Gauge{
+		Type:   gaugeType,
+		Table:  tables[gaugeType],
+		NoFail: noFail,
+	}
+	g.Reset()
+	return g
+}
+
+func (g *Gauge) Reset() {
+	g.Value = GaugeInits[g.Type]
+	g.Failed = false
+	g.History = nil
+}
+
+// Update changes the value with the judgment of given index.
+func (g *Gauge) Update(judgment int) {
+	if judgment < 0 || judgment >= len(g.Table) {
+		return
+	}
+	g.Value += g.Table[judgment]
+	switch {
+	case g.Value < 0:
+		g.Value = 0
+	case g.Value > 1:
+		g.Value = 1
+	}
+	g.History = append(g.History, g.Value)
+	if g.Type >= GaugeHard && g.Value == 0 {
+		g.Failed = true
+	}
+}
+
+// Cleared reports whether the gauge is enough to clear.
+// A play is cleared only when the chart has been played to the end.
+func (g Gauge) Cleared() bool {
+	if g.Failed {
+		return false
+	}
+	return g.Value >= GaugeClears[g.Type]
+}
+
+// Stopped reports whether the play should stop due to failing.
+func (g Gauge) Stopped() bool { return g.Failed && !g.NoFail }
@@ -13,7 +13,6 @@ require (
 	github.com/ebitengine/purego v0.2.0-alpha.0.20220915044048-aa1b0f680473 // indirect
 	github.com/modern-go/concurrent v0.0.0-20180306012644-bacd9c7ef1dd // indirect
 	github.com/modern-go/reflect2 v1.0.2 // indirect
-	gopkg.in/yaml.v3 v3.0.1 // indirect
 )
 
 require (
@@ -29,4 +28,5 @@ require (
 	github.com/vmihailenco/tagparser/v2 v2.0.0 // indirect
 	golang.org/x/exp/shiny v0.0.0-20220916125017-b168a2c6b86b // indirect
 	golang.org/x/mobile v0.0.0-20220928052126-fa6bcb076835 // indirect
+	gopkg.in/yaml.v3 v3.0.1
 )
@@ -15,6 +15,7 @@ var (
 
 	Offset int = -65
 	isFullScreen bool = false
+	isPractice   bool = false
 )
 var (
 	modeHandler    ctrl.IntHandler
@@ -35,12 +36,21 @@ var (
 	
 	fullScreenHandler    ctrl.BoolHandler
 	FullScreenKeyHandler ctrl.KeyHandler
+
+	practiceHandler    ctrl.BoolHandler
+	PracticeKeyHandler ctrl.KeyHandler
 )
 var (
 	speedScaleHandlers   []ctrl.FloatHandler
 	SpeedScaleKeyHandler ctrl.KeyHandler
 )
 
+// Mods are toggled at SceneSelect.
+var (
+	currentMods    = NewMods()
+	ModKeyHandlers []ctrl.KeyHandler
+)
+
 const (
 	SortByName = iota
 	SortByLevel
@@ -157,4 +167,70 @@ func LoadHandlers(props []ModeProp) {
 		Sounds:    [2][]byte{TapSound, TapSound},
 		Volume:    &EffectVolume,
 	}
+
+	practiceHandler = ctrl.BoolHandler{
+		Value: &isPractice,
+	}
+	PracticeKeyHandler = ctrl.KeyHandler{
+		Handler:   practiceHandler,
+		Modifiers: []ebiten.Key{},
+		Keys:      [2]ebiten.Key{-1, ebiten.KeyF3},
+		Sounds:    [2][]byte{TapSound, TapSound},
+		Volume:    &EffectVolume,
+	}
+
+	ModKeyHandlers = []ctrl.KeyHandler{{
+		Handler: ctrl.FloatHandler{
+			Value: &currentMods.Rate,
+			Min:   0.5,
+			Max:   2,
+			Unit:  0.05,
+		},
+		Modifiers: []ebiten.Key{},
+		Keys:      [2]ebiten.Key{ebiten.KeyMinus, ebiten.KeyEqual},
+		Sounds:    [2][]byte{ToggleSounds[0], ToggleSounds[1]},
+		Volume:    &EffectVolume,
+	}, {
+		Handler: ctrl.IntHandler{
+			Value: &currentMods.Random,
+			Min:   RandomNone,
+			Max:   RandomSuper,
+			Loop:  true,
+		},
+		Modifiers: []ebiten.Key{},
+		Keys:      [2]ebiten.Key{-1, ebiten.KeyR},
+		Sounds:    [2][]byte{TapSound, TapSound},
+		Volume:    &EffectVolume,
+	}, {
+		Handler: ctrl.IntHandler{
+			Value: &currentMods.Gauge,
+			Min:   GaugeNormal,
+			Max:   GaugePerfectOnly,
+			Loop:  true,
+		},
+		Modifiers: []ebiten.Key{},
+		Keys:      [2]ebiten.Key{-1, ebiten.KeyG},
+		Sounds:    [2][]byte{TapSound, TapSound},
+		Volume:    &EffectVolume,
+	}}
+	for _, mod := range []struct {
+		value *bool
+		key   ebiten.Key
+	}{
+		{&currentMods.Mirror, ebiten.KeyM},
+		{&currentMods.NoFail, ebiten.KeyN},
+		{&currentMods.Auto, ebiten.KeyA},
+		{&currentMods.Hidden, ebiten.KeyH},
+		{&currentMods.FadeIn, ebiten.KeyI},
+		{&currentMods.Flashlight, ebiten.KeyL},
+		{&currentMods.Nightcore, ebiten.KeyC},
+	} {
+		ModKeyHandlers = append(ModKeyHandlers, ctrl.KeyHandler{
+			Handler:   ctrl.BoolHandler{Value: mod.value},
+			Modifiers: []ebiten.Key{},
+			Keys:      [2]ebiten.Key{-1, mod.key},
+			Sounds:    [2][]byte{TapSound, TapSound},
+			Volume:    &EffectVolume,
+		})
+	}
 }
@@ -0,0 +1,25 @@
+package gosu
+
+const (
+	HitObjectNote = iota
+	HitObjectLongNote
+	HitObjectRoll
+	HitObjectShake
+)
+
+// HitObject is a note of ChartFile. Each mode reads fields of its own:
+// piano reads Column or Pitch, drum reads Kat, Big and Hits,
+// and karaoke reads Text and Line.
+type HitObject struct {
+	Time     int64
+	Type     int
+	Duration int64 // For long note, roll and shake.
+	Column   int   // -1 means the chart has no lanes; lanes are assigned by Pitch.
+	Pitch    int   // 60 is the middle C.
+	Kat      bool  // Drum note is Don unless Kat.
+	Big      bool
+	Hits     int      // Required hits of shake. 0 means it is up to the mode.
+	Samples  []Sample // Played all at once. osu! hit sound has additions.
+	Text     string   // A syllable of lyrics.
+	Line     int      // Index of a line of lyrics.
+}
@@ -1,13 +1,18 @@
 package gosu
 
 import (
+	"fmt"
+	"os"
 	"path/filepath"
+	"strconv"
 	"strings"
 	"time"
 
 	"github.com/hndada/gosu/ctrl"
+	"github.com/hndada/gosu/format/ojn"
 	"github.com/hndada/gosu/format/osr"
-	"github.com/hndada/gosu/format/osu"
+	"github.com/hndada/gosu/format/sm"
+	"github.com/hndada/gosu/format/tja"
 	"github.com/hndada/gosu/input"
 )
 
@@ -32,29 +37,101 @@ type ModeProp struct {
 	SpeedKeyHandler ctrl.KeyHandler
 	SpeedScale      *float64
 	NewChartInfo    func(string) (ChartInfo, error)
-	NewScenePlay    func(cpath string, rf *osr.Format) (Scene, error)
+	NewScenePlay    func(cpath string, mods Mods, rf *osr.Format) (Scene, error)
 	ExposureTime    func(float64) float64
 	KeySettings     map[int][]input.Key
 }
 
-// Mode determines a mode of chart file by its path.
+// ChartFileMode determines a mode of chart by its path without converting it.
+// Index of chart path matters for MIDI, whose key count is the index.
 func ChartFileMode(fpath string) int {
+	fpath, index := SplitChartPath(fpath)
+	format, ok := chartFormats[strings.ToLower(filepath.Ext(fpath))]
+	if !ok {
+		return ModeNone
+	}
+	return format.mode(fpath, index)
+}
+
+// Some chart files have multiple charts, e.g., .ojn has 3 difficulties.
+// Each chart in such file is addressed by a path with a index suffix:
+// "<file path>|<index>". The separator is not allowed at file names on Windows.
+const chartIndexSeparator = "|"
+
+func ChartPath(fpath string, i int) string {
+	return fmt.Sprintf("%s%s%d", fpath, chartIndexSeparator, i)
+}
+
+// SplitChartPath returns a file path and an index of chart in the file.
+// Index is -1 when the path has no index suffix.
+func SplitChartPath(cpath string) (fpath string, i int) {
+	pos := strings.LastIndex(cpath, chartIndexSeparator)
+	if pos == -1 {
+		return cpath, -1
+	}
+	i, err := strconv.Atoi(cpath[pos+1:])
+	if err != nil {
+		return cpath, -1
+	}
+	return cpath[:pos], i
+}
+
+// ChartPaths returns paths of all charts in the file.
+func ChartPaths(fpath string) ([]string, error) {
 	switch strings.ToLower(filepath.Ext(fpath)) {
-	case ".osu":
-		mode, keyCount := osu.Mode(fpath)
-		switch mode {
-		case osu.ModeMania:
-			if keyCount <= 4 || keyCount == 6 {
-				return ModePiano4
+	case ".ojn":
+		dat, err := os.ReadFile(fpath)
+		if err != nil {
+			return nil, err
+		}
+		h, err := ojn.ParseHeader(dat)
+		if err != nil {
+			return nil, err
+		}
+		cpaths := make([]string, 0, 3)
+		for i, count := range h.NoteCounts {
+			if count > 0 {
+				cpaths = append(cpaths, ChartPath(fpath, i))
+			}
+		}
+		return cpaths, nil
+	case ".sm", ".ssc":
+		dat, err := os.ReadFile(fpath)
+		if err != nil {
+			return nil, err
+		}
+		f, err := sm.Parse(dat)
+		if err != nil {
+			return nil, err
+		}
+		cpaths := make([]string, 0, len(f.Charts))
+		for i, c := range f.Charts {
+			if c.StepsType == "dance-single" {
+				cpaths = append(cpaths, ChartPath(fpath, i))
 			}
-			return ModePiano7
-		case osu.ModeTaiko:
-			return ModeDrum
-		default:
-			return ModeNone
-		}
-	case ".ojn", ".bms":
-		return ModePiano7
+		}
+		return cpaths, nil
+	case ".tja":
+		dat, err := os.ReadFile(fpath)
+		if err != nil {
+			return nil, err
+		}
+		f, err := tja.Parse(dat)
+		if err != nil {
+			return nil, err
+		}
+		cpaths := make([]string, len(f.Courses))
+		for i := range f.Courses {
+			cpaths[i] = ChartPath(fpath, i)
+		}
+		return cpaths, nil
+	case ".mid", ".midi":
+		// MIDI has no lanes. Index stands for key count of generated chart.
+		cpaths := make([]string, len(MIDIKeyCounts))
+		for i, keyCount := range MIDIKeyCounts {
+			cpaths[i] = ChartPath(fpath, keyCount)
+		}
+		return cpaths, nil
 	}
-	return ModeNone
+	return []string{fpath}, nil
 }
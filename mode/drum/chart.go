@@ -1,14 +1,10 @@
 package drum
 
 import (
-	"crypto/md5"
 	"fmt"
-	"os"
-	"path/filepath"
-	"strings"
+	"sort"
 
 	"github.com/hndada/gosu"
-	"github.com/hndada/gosu/format/osu"
 )
 
 type Floater struct {
@@ -42,23 +38,21 @@ var (
 
 // NewChart takes file path as input for starting with parsing.
 // Chart data should not rely on the ChartInfo; users may have modified it.
-func NewChart(cpath string) (c *Chart, err error) {
-	var f any
-	dat, err := os.ReadFile(cpath)
+// Rate of mods is applied to the chart.
+func NewChart(cpath string, mods gosu.Mods) (c *Chart, err error) {
+	f, err := gosu.LoadChartFile(cpath)
 	if err != nil {
 		return
 	}
-	switch strings.ToLower(filepath.Ext(cpath)) {
-	case ".osu":
-		f, err = osu.Parse(dat)
-		if err != nil {
-			return
-		}
+	if f.Mode != gosu.ModeDrum {
+		err = fmt.Errorf("not a drum chart")
+		return
 	}
+	f.SetRate(mods.Rate)
 	c = new(Chart)
-	c.ChartHeader = gosu.NewChartHeader(f)
-	c.MD5 = md5.Sum(dat)
-	c.TransPoints = gosu.NewTransPoints(f)
+	c.ChartHeader = f.ChartHeader
+	c.MD5 = f.MD5
+	c.TransPoints = gosu.NewTransPoints(f.TimingPoints)
 	if len(c.TransPoints) == 0 {
 		err = fmt.Errorf("no TransPoints in the chart")
 		return
@@ -69,7 +63,7 @@ func NewChart(cpath string) (c *Chart, err error) {
 		tp.Speed *= bpmScale
 	}
 
-	c.Notes, c.Rolls, c.Shakes = NewNotes(f)
+	c.Notes, c.Rolls, c.Shakes = NewNotes(f.HitObjects)
 	var tp *gosu.TransPoint
 	for _, ns := range [][]*Note{c.Notes, c.Rolls, c.Shakes} {
 		tp = c.TransPoints[0]
@@ -79,24 +73,22 @@ func NewChart(cpath string) (c *Chart, err error) {
 			}
 			n.Speed = tp.Speed
 			bpm := ScaledBPM(tp.BPM)
-			if n.Type == Roll {
-				switch f := f.(type) {
-				case *osu.Format:
-					// speedFactor := c.TransPoints[0].BPM / 60000 * (f.SliderMultiplier * 100)
-					speed := tp.BPM * (tp.Speed / bpmScale) / 60000 * f.SliderMultiplier * 100 // Unit is osupixel / 100ms.
-					n.Duration = int64(n.length / speed)
-				}
-			}
 			switch n.Type {
 			case Roll:
 				n.Tick = int(float64(n.Duration)*bpm/60000*DotDensity+0.1) + 1
 			case Shake:
+				if n.Tick > 0 { // Some formats specify the number of shakes.
+					break
+				}
 				n.Tick = int(float64(n.Duration)*bpm/60000*ShakeDensity+0.1) + 1
 			}
 		}
 	}
 	c.Dots = NewDots(c.Rolls)
 	c.Bars = NewBars(c.TransPoints, c.Duration())
+	if len(c.Breaks) == 0 {
+		c.Breaks = gosu.NewBreaks(c.intervals())
+	}
 	tp = c.TransPoints[0]
 	for _, b := range c.Bars {
 		for tp.Next != nil && b.Time >= tp.Next.Time {
@@ -113,6 +105,27 @@ const (
 	MinScaledBPM = 60  // 128
 )
 
+// intervals returns time intervals of all kinds of notes sorted by time.
+func (c Chart) intervals() [][2]int64 {
+	ivs := make([][2]int64, 0, len(c.Notes)+len(c.Rolls)+len(c.Shakes))
+	for _, ns := range [][]*Note{c.Notes, c.Rolls, c.Shakes} {
+		for _, n := range ns {
+			ivs = append(ivs, [2]int64{n.Time, n.Time + n.Duration})
+		}
+	}
+	sort.Slice(ivs, func(i, j int) bool { return ivs[i][0] < ivs[j][0] })
+	return ivs
+}
+
+// FirstTime returns the time of the first note of all kinds.
+func (c Chart) FirstTime() int64 {
+	ivs := c.intervals()
+	if len(ivs) == 0 {
+		return 0
+	}
+	return ivs[0][0]
+}
+
 func (c Chart) Duration() (last int64) {
 	for _, ns := range [][]*Note{c.Notes, c.Rolls, c.Shakes} {
 		if len(ns) == 0 {
@@ -136,15 +149,15 @@ func (c Chart) BPMs() (main, min, max float64) {
 	return gosu.BPMs(c.TransPoints, c.Duration())
 }
 func NewChartInfo(cpath string) (info gosu.ChartInfo, err error) {
-	c, err := NewChart(cpath)
+	c, err := NewChart(cpath, gosu.NewMods())
 	if err != nil {
 		return
 	}
 	mode := gosu.ModeDrum
 	main, min, max := c.BPMs()
 	info = gosu.ChartInfo{
-		Path: cpath,
-		// Mods:       mods,
+		Path:        cpath,
+		MD5:         c.MD5,
 		ChartHeader: c.ChartHeader,
 		Mode:        mode,
 		SubMode:     0,
@@ -160,6 +160,7 @@ type NoteDarwer struct {
 	Shakes         []*Note
 	NoteSprites    [2][4]draws.Sprite
 	OverlayDrawers [2]draws.AnimationDrawer
+	Hidden         bool
 }
 
 func (d *NoteDarwer) Update(time int64, bpm float64) {
@@ -205,6 +206,9 @@ func (d NoteDarwer) Draw(screen *ebiten.Image) {
 					op.ColorM.Scale(1, 1, 1, 0)
 				}
 			}
+			if d.Hidden && mode != modeShake {
+				op.ColorM.Scale(1, 1, 1, gosu.Fade(pos, HiddenPositions[0], HiddenPositions[1]))
+			}
 			note.Move(pos, 0)
 			note.Draw(screen, op)
 			// if mode == modeShake {
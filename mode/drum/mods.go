@@ -0,0 +1,62 @@
+package drum
+
+import (
+	"sort"
+
+	"github.com/hndada/gosu"
+)
+
+// Notes fade out between the positions with Hidden.
+// Positions are distances from the hit position.
+var HiddenPositions = [2]float64{screenSizeX * 0.15, screenSizeX * 0.3}
+
+// AutoHoldTime is how long Auto holds a key for a hit.
+const AutoHoldTime = 20
+
+// NewAutoListener hits notes at the exact time unless humanized.
+// Regular notes are hit by each hand in turn, and Big notes by both hands.
+// Dots are hit with Red, and Shakes with Red and Blue in turn.
+// Hands alternate in order of time over notes, dots and shakes.
+func NewAutoListener(c *Chart, h *gosu.Humanizer, timer *gosu.Timer) func() []bool {
+	type hit struct {
+		time  int64
+		color int
+		big   bool
+	}
+	hits := make([]hit, 0, len(c.Notes)+len(c.Dots))
+	for _, n := range c.Notes {
+		hits = append(hits, hit{n.Time, n.Color, n.Size == Big})
+	}
+	for _, d := range c.Dots {
+		hits = append(hits, hit{d.Time, Red, false})
+	}
+	for _, n := range c.Shakes {
+		var step int64
+		if n.Tick >= 2 {
+			step = n.Duration / int64(n.Tick-1)
+		}
+		for tick := 0; tick < n.Tick; tick++ {
+			hits = append(hits, hit{n.Time + step*int64(tick), []int{Red, Blue}[tick%2], false})
+		}
+	}
+	sort.SliceStable(hits, func(i, j int) bool { return hits[i].time < hits[j].time })
+
+	keys := [2][2]int{Red: {LeftRed, RightRed}, Blue: {LeftBlue, RightBlue}}
+	ivs := make(gosu.KeyIntervals, 4)
+	var hand int
+	for _, ht := range hits {
+		if h.Miss() {
+			continue
+		}
+		t := ht.time + h.Error()
+		if ht.big {
+			for _, k := range keys[ht.color] {
+				ivs.Add(k, t, t+AutoHoldTime)
+			}
+			continue
+		}
+		ivs.Add(keys[ht.color][hand], t, t+AutoHoldTime)
+		hand = 1 - hand
+	}
+	return ivs.Listener(timer)
+}
@@ -5,7 +5,6 @@ import (
 	"sort"
 
 	"github.com/hndada/gosu"
-	"github.com/hndada/gosu/format/osu"
 )
 
 // Drum note has 3 components: Color, Size, Type(Note, Roll, Shake).
@@ -34,9 +33,8 @@ type Note struct {
 	Color    int
 	Size     int
 	Duration int64
-	length   float64 // For compatibility with osu!.
-	Tick     int     // The number of ticks in Roll or Shake.
-	gosu.Sample
+	Tick     int // The number of ticks in Roll or Shake.
+	Samples  []gosu.Sample
 
 	Marked  bool
 	HitTick int // The number of ticks being hit.
@@ -44,53 +42,48 @@ type Note struct {
 	Prev    *Note
 }
 
-func NewNote(f any) (n *Note) {
-	switch f := f.(type) {
-	case osu.HitObject:
-		n = &Note{
-			Sample: gosu.NewSample(f),
-		}
-		n.Time = int64(f.Time)
-		switch {
-		case f.NoteType&osu.HitTypeSlider != 0:
-			n.Type = Roll
-			n.length = f.SliderLength() // For calculating duration of Roll.
-			n.Color = Yellow
-		case f.NoteType&osu.HitTypeSpinner != 0:
-			n.Type = Shake
-			n.Duration = int64(f.EndTime) - n.Time
-			n.Color = Purple
-		default:
-			n.Type = Normal
-			if osu.IsDon(f) {
-				n.Color = Red
-			} else {
-				n.Color = Blue
-			}
-		}
-		if osu.IsBig(f) {
-			n.Size = Big
+func NewNote(h gosu.HitObject) (n *Note) {
+	n = &Note{
+		Duration: h.Duration,
+		Samples:  h.Samples,
+	}
+	n.Time = h.Time
+	switch h.Type {
+	case gosu.HitObjectRoll:
+		n.Type = Roll
+		n.Color = Yellow
+	case gosu.HitObjectShake:
+		n.Type = Shake
+		n.Tick = h.Hits
+		n.Color = Purple
+	default:
+		n.Type = Normal
+		n.Duration = 0
+		if h.Kat {
+			n.Color = Blue
 		} else {
-			n.Size = Regular
+			n.Color = Red
 		}
 	}
+	if h.Big {
+		n.Size = Big
+	} else {
+		n.Size = Regular
+	}
 	return
 }
 
-func NewNotes(f any) (notes, rolls, shakes []*Note) {
-	switch f := f.(type) {
-	case *osu.Format:
-		notes = make([]*Note, 0, len(f.HitObjects)*2)
-		for _, ho := range f.HitObjects {
-			n := NewNote(ho)
-			switch n.Type {
-			case Normal:
-				notes = append(notes, n)
-			case Roll:
-				rolls = append(rolls, n)
-			case Shake:
-				shakes = append(shakes, n)
-			}
+func NewNotes(hs []gosu.HitObject) (notes, rolls, shakes []*Note) {
+	notes = make([]*Note, 0, len(hs))
+	for _, h := range hs {
+		n := NewNote(h)
+		switch n.Type {
+		case Normal:
+			notes = append(notes, n)
+		case Roll:
+			rolls = append(rolls, n)
+		case Shake:
+			shakes = append(shakes, n)
 		}
 	}
 	// Sort notes only with their time.
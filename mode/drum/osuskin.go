@@ -0,0 +1,142 @@
+package drum
+
+import (
+	"fmt"
+	"image/color"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hndada/gosu"
+	"github.com/hndada/gosu/draws"
+	"github.com/hndada/gosu/format/osuskin"
+)
+
+// loadOsuSkin replaces sprites with osu!taiko elements.
+// Replaced sprites keep positions and heights of default sprites,
+// except judgments and dancers which keep sizes as in osu!.
+// Sprites of elements not found remain as they are.
+// https://osu.ppy.sh/wiki/en/Skinning/osu%21taiko
+func loadOsuSkin(skin *Skin, f *osuskin.Format) {
+	skin.FieldSprite = newOsuSkinSprite("taiko-bar-right", skin.FieldSprite, true)
+	skin.KeyFieldSprite = newOsuSkinSprite("taiko-bar-left", skin.KeyFieldSprite, true)
+	for i, name := range []string{"taikohitcircle", "taikobigcircle"} {
+		if src, _ := gosu.NewOsuSkinImage(name); src != nil {
+			for j, clr := range []color.NRGBA{ColorRed, ColorBlue, ColorYellow, ColorPurple} {
+				base := skin.NoteSprites[i][j]
+				s := draws.NewSpriteFromImage(newColoredImage(src, clr))
+				s.SetScale(base.H() / s.H())
+				s.SetPosition(base.X(), base.Y(), base.Origin())
+				skin.NoteSprites[i][j] = s
+			}
+		}
+		if len(skin.OverlaySprites[i]) == 0 {
+			continue
+		}
+		base := skin.OverlaySprites[i][0]
+		var overlays []draws.Sprite
+		for j := 0; ; j++ {
+			s := newOsuSkinSprite(fmt.Sprintf("%soverlay-%d", name, j), base, false)
+			if s == base {
+				break
+			}
+			overlays = append(overlays, s)
+		}
+		if len(overlays) == 0 {
+			if s := newOsuSkinSprite(name+"overlay", base, false); s != base {
+				overlays = append(overlays, s)
+			}
+		}
+		if len(overlays) > 0 {
+			skin.OverlaySprites[i] = overlays
+		}
+	}
+	if src, _ := gosu.NewOsuSkinImage("taiko-roll-end"); src != nil {
+		for i := range skin.TailSprites {
+			skin.TailSprites[i] = replaceImage(src, skin.TailSprites[i])
+			skin.HeadSprites[i] = replaceImage(draws.NewXFlippedImage(src), skin.HeadSprites[i])
+		}
+	}
+	for i := range skin.BodySprites {
+		skin.BodySprites[i] = newOsuSkinSprite("taiko-roll-middle", skin.BodySprites[i], false)
+	}
+	skin.DotSprite = newOsuSkinSprite("sliderscorepoint", skin.DotSprite, false)
+	skin.ShakeSprite = newOsuSkinSprite("spinner-circle", skin.ShakeSprite, false)
+	skin.ShakeBorderSprite = newOsuSkinSprite("spinner-approachcircle", skin.ShakeBorderSprite, false)
+
+	// Left half of drum is drawn at left, and flipped one at right.
+	if src, _ := gosu.NewOsuSkinImage("taiko-drum-inner"); src != nil {
+		skin.KeySprites[LeftRed] = replaceImage(src, skin.KeySprites[LeftRed])
+		skin.KeySprites[RightRed] = replaceImage(draws.NewXFlippedImage(src), skin.KeySprites[RightRed])
+	}
+	if src, _ := gosu.NewOsuSkinImage("taiko-drum-outer"); src != nil {
+		skin.KeySprites[LeftBlue] = replaceImage(src, skin.KeySprites[LeftBlue])
+		skin.KeySprites[RightBlue] = replaceImage(draws.NewXFlippedImage(src), skin.KeySprites[RightBlue])
+	}
+
+	for i, names := range [][3]string{
+		{"taiko-hit300", "taiko-hit100", "taiko-hit0"},
+		{"taiko-hit300k", "taiko-hit100k", "taiko-hit0"},
+	} {
+		for j, name := range names {
+			base := skin.JudgmentSprites[i][j]
+			s := gosu.NewOsuSkinSprite(name)
+			if !s.IsValid() {
+				continue
+			}
+			s.SetPosition(base.X(), base.Y(), base.Origin())
+			skin.JudgmentSprites[i][j] = s
+		}
+	}
+	for i, name := range []string{"pippidonidle", "pippidonclear", "pippidonfail", "pippidonkiai"} {
+		var ss []draws.Sprite
+		for j := 0; ; j++ {
+			s := gosu.NewOsuSkinSprite(fmt.Sprintf("%s%d", name, j))
+			if !s.IsValid() {
+				break
+			}
+			s.SetPosition(DancerPositionX, DancerPositionY, draws.OriginCenterMiddle)
+			ss = append(ss, s)
+		}
+		if len(ss) > 0 {
+			skin.DancerSprites[i] = ss
+		}
+	}
+	if ss, ok := gosu.NewOsuSkinNumberSprites(f.ComboPrefix); ok {
+		for i, s := range ss {
+			base := skin.ComboSprites[i]
+			s.SetScale(base.H() / s.H())
+			s.SetPosition(base.X(), base.Y(), base.Origin())
+			skin.ComboSprites[i] = s
+		}
+	}
+}
+
+// newOsuSkinSprite returns base itself when the element is not found.
+// Stretched sprite fits to the size of base, otherwise to the height.
+func newOsuSkinSprite(name string, base draws.Sprite, stretch bool) draws.Sprite {
+	src, _ := gosu.NewOsuSkinImage(name)
+	if src == nil {
+		return base
+	}
+	if !stretch {
+		return replaceImage(src, base)
+	}
+	s := draws.NewSpriteFromImage(src)
+	s.SetScaleXY(base.W()/s.W(), base.H()/s.H(), ebiten.FilterLinear)
+	s.SetPosition(base.X(), base.Y(), base.Origin())
+	return s
+}
+
+func replaceImage(src *ebiten.Image, base draws.Sprite) draws.Sprite {
+	s := draws.NewSpriteFromImage(src)
+	s.SetScale(base.H() / s.H())
+	s.SetPosition(base.X(), base.Y(), base.Origin())
+	return s
+}
+
+func newColoredImage(src *ebiten.Image, clr color.NRGBA) *ebiten.Image {
+	img := ebiten.NewImage(src.Size())
+	op := &ebiten.DrawImageOptions{}
+	op.ColorM.ScaleWithColor(clr)
+	img.DrawImage(src, op)
+	return img
+}
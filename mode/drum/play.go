@@ -2,6 +2,7 @@ package drum
 
 import (
 	"fmt"
+	"time"
 
 	"github.com/hajimehoshi/ebiten/v2"
 	"github.com/hajimehoshi/ebiten/v2/ebitenutil"
@@ -13,10 +14,12 @@ import (
 
 type ScenePlay struct {
 	Chart *Chart
+	Mods  gosu.Mods
 	gosu.Timer
 	// time int64 // Just a cache.
 	gosu.MusicPlayer
-	SoundEffectBytes [2][2][]byte // No custom hitsound at Drum mode.
+	Samples audios.SoundMap // Default sounds and custom hit sounds of the chart.
+	Breaks  []gosu.Break    // Intro is included.
 	gosu.KeyLogger
 	KeyActions [2]int
 
@@ -34,6 +37,8 @@ type ScenePlay struct {
 	// Skin may be applied some custom settings: on/off some sprites
 	Skin
 	BackgroundDrawer gosu.BackgroundDrawer
+	StoryboardDrawer gosu.StoryboardDrawer
+	BreakDrawer      gosu.BreakDrawer
 	StageDrawer      StageDrawer
 	BarDrawer        BarDrawer
 	JudgmentDrawer   JudgmentDrawer
@@ -49,36 +54,49 @@ type ScenePlay struct {
 	MeterDrawer  gosu.MeterDrawer
 }
 
-// Todo: actual auto replay generator for gimmick charts
 // Todo: support mods: show Piano's ScenePlay during Drum's ScenePlay
-func NewScenePlay(cpath string, rf *osr.Format) (scene gosu.Scene, err error) {
+func NewScenePlay(cpath string, mods gosu.Mods, rf *osr.Format) (scene gosu.Scene, err error) {
 	s := new(ScenePlay)
-	s.Chart, err = NewChart(cpath)
+	if mods.Auto && mods.Seed == 0 {
+		mods.Seed = time.Now().UnixNano()
+	}
+	s.Mods = mods
+	s.Chart, err = NewChart(cpath, mods)
 	if err != nil {
 		return
 	}
 	c := s.Chart
 	gosu.SetTitle(c.ChartHeader)
-	s.Timer = gosu.NewTimer(c.Duration())
-	if path, ok := c.MusicPath(cpath); ok {
-		s.MusicPlayer, err = gosu.NewMusicPlayer(path, &s.Timer)
+	s.Timer = gosu.NewTimer(c.Duration(), mods.Rate)
+	path, hasMusic := c.MusicPath(cpath)
+	if hasMusic {
+		s.MusicPlayer, err = gosu.NewMusicPlayer(path, &s.Timer, mods.Nightcore)
 		if err != nil {
 			return
 		}
 	}
-	for i, colorName := range []string{"regular", "big"} {
-		for j, sizeName := range []string{"red", "blue"} {
-			path := fmt.Sprintf("skin/drum/sound/%s/%s.wav", colorName, sizeName)
-			b, err := audios.NewBytes(path)
-			if err != nil {
-				panic(err)
-			}
-			s.SoundEffectBytes[i][j] = b
-		}
+	s.Breaks = gosu.PlayBreaks(c.Breaks, c.FirstTime())
+	s.Samples = audios.NewSoundMap(&gosu.EffectVolume)
+	switch {
+	case !hasMusic: // Samples are the music itself.
+		s.Samples = s.Samples.WithRate(mods.Rate, mods.Nightcore)
+	case c.SamplesMatchPlaybackRate:
+		s.Samples = s.Samples.WithRate(mods.Rate, true)
+	}
+	if err := LoadSamples(s.Samples, cpath, c); err != nil {
+		fmt.Printf("error at loading samples: %s\n", err)
 	}
 	s.KeyLogger = gosu.NewKeyLogger(KeySettings[4][:])
-	if rf != nil {
-		s.KeyLogger.FetchPressed = NewReplayListener(rf, &s.Timer)
+	switch {
+	case rf != nil:
+		s.KeyLogger.FetchPressed = NewReplayListener(rf, mods, &s.Timer)
+		s.KeyLogger.Actions = nil
+	case mods.Auto:
+		h := gosu.NewHumanizer(mods.Seed)
+		s.KeyLogger.FetchPressed = NewAutoListener(c, h, &s.Timer)
+		if !gosu.ExportAutoReplay {
+			s.KeyLogger.Actions = nil
+		}
 	}
 
 	s.TransPoint = c.TransPoints[0]
@@ -86,6 +104,7 @@ func NewScenePlay(cpath string, rf *osr.Format) (scene gosu.Scene, err error) {
 	s.SetSpeed()
 	s.Scorer = gosu.NewScorer(c.ScoreFactors)
 	s.JudgmentCounts = make([]int, len(JudgmentCountKinds))
+	s.Gauge = gosu.NewGauge(mods.Gauge, GaugeTables, mods.NoFail)
 	// s.FlowMarks = make([]float64, 0, c.Duration()/1000)
 	for _, n := range c.Notes {
 		s.MaxWeights[gosu.Flow] += n.Weight()
@@ -116,6 +135,8 @@ func NewScenePlay(cpath string, rf *osr.Format) (scene gosu.Scene, err error) {
 	if bg := gosu.NewBackground(c.BackgroundPath(cpath)); bg.IsValid() {
 		s.BackgroundDrawer.Sprite = bg
 	}
+	s.StoryboardDrawer = gosu.NewStoryboardDrawer(cpath)
+	s.BreakDrawer = gosu.NewBreakDrawer(s.Breaks, c.LetterboxInBreaks)
 	s.StageDrawer = StageDrawer{
 		Hightlight:  s.Highlight,
 		FieldSprite: s.FieldSprite,
@@ -153,6 +174,7 @@ func NewScenePlay(cpath string, rf *osr.Format) (scene gosu.Scene, err error) {
 		Rolls:       c.Rolls,
 		Shakes:      c.Shakes,
 		NoteSprites: s.NoteSprites,
+		Hidden:      mods.Hidden,
 	}
 	for i, sprites := range s.OverlaySprites {
 		s.NoteDrawer.OverlayDrawers[i] = draws.AnimationDrawer{
@@ -215,7 +237,12 @@ func (s *ScenePlay) Update() any {
 	defer s.Ticker()
 	if s.IsDone() {
 		s.MusicPlayer.Close()
-		return gosu.PlayToResultArgs{Result: s.NewResult(s.Chart.MD5)}
+		if s.Actions != nil && !s.Quit { // Quit plays are not exported.
+			if err := gosu.ExportReplay(s.NewReplay(), s.Chart.ChartHeader); err != nil {
+				fmt.Printf("error at exporting replay: %s\n", err)
+			}
+		}
+		return gosu.PlayToResultArgs{Result: s.NewResult(s.Chart.MD5, s.Mods)}
 	}
 	// if s.Now == 0 {
 	// 	s.MusicPlayer.Play()
@@ -223,16 +250,19 @@ func (s *ScenePlay) Update() any {
 	// if s.Now == 150 {
 	// 	s.MusicPlayer.Player.Seek(time.Duration(s.Now) * time.Millisecond)
 	// }
+	gosu.Skip(s.Breaks, &s.Timer, &s.MusicPlayer)
 	s.MusicPlayer.Update()
 	// fmt.Printf("game: %dms music: %s\n", s.Now, s.MusicPlayer.Player.Current())
 
 	s.LastPressed = s.Pressed
 	s.Pressed = s.FetchPressed()
+	s.Record(s.Now, NewReplayAction(s.Pressed))
 	s.UpdateKeyActions()
 
 	var (
 		judgment gosu.Judgment
 		big      bool
+		hit      *Note // Custom hit sounds of the note replace the default.
 	)
 	if s.StagedJudgment.Valid() {
 		n := s.StagedNote
@@ -246,25 +276,28 @@ func (s *ScenePlay) Update() any {
 		if td := jTime - s.Now; td < -MaxBigHitDuration {
 			flush = true
 		}
-		if td := n.Time - s.Now; td < -Miss.Window {
+		if td := s.Mods.MusicTime(n.Time - s.Now); td < -Miss.Window {
 			flush = true
 		}
 		if flush {
 			for _, key := range [][]int{{1, 2}, {0, 3}}[n.Color] {
 				s.LastHitTimes[key] = -gosu.Wait
 			}
-			td := n.Time - jTime
+			td := s.Mods.MusicTime(n.Time - jTime)
 			s.MarkNote(n, j, false)
 			s.MeterDrawer.AddMark(int(td), 0)
 			judgment = j
 			big = false
+			hit = n
 			s.StagedJudgment = gosu.Judgment{}
 		}
 	}
 	if n := s.StagedNote; n != nil {
-		td := n.Time - s.Now // A negative value means late hit.
+		// A negative value means late hit. Judgment windows are of music.
+		td := s.Mods.MusicTime(n.Time - s.Now)
 		if j, b := VerdictNote(n, s.KeyActions, td); j.Window != 0 {
 			if n.Size == Big && !b {
+				hit = n
 				s.StagedJudgment = j
 				s.StagedJudgmentTime = s.Now
 			} else {
@@ -272,6 +305,7 @@ func (s *ScenePlay) Update() any {
 				s.MeterDrawer.AddMark(int(td), 0)
 				judgment = j
 				big = b
+				hit = n
 				if s.StagedJudgment.Valid() {
 					s.StagedJudgment = gosu.Judgment{}
 				}
@@ -279,7 +313,7 @@ func (s *ScenePlay) Update() any {
 		}
 	}
 	if n := s.StagedDot; n != nil {
-		td := n.Time - s.Now
+		td := s.Mods.MusicTime(n.Time - s.Now)
 		if marked := VerdictDot(n, s.KeyActions, td); marked != DotReady {
 			s.MarkDot(n, marked)
 			s.MeterDrawer.AddMark(int(td), 1)
@@ -304,16 +338,22 @@ func (s *ScenePlay) Update() any {
 		}
 	}()
 
-	// Todo: apply effect volume change from changer
-	for i, size := range s.KeyActions {
+	for color, size := range s.KeyActions {
 		if size == SizeNone {
 			continue
 		}
-		vol := s.TransPoint.Volume
-		p := audios.Context.NewPlayerFromBytes(s.SoundEffectBytes[i][size])
-		p.SetVolume(vol * gosu.EffectVolume)
-		p.Play()
+		if hit != nil && !judgment.Is(Miss) && hit.Color == color {
+			if gosu.PlaySamples(s.Samples, hit.Samples, s.TransPoint.Volume) {
+				s.StoryboardDrawer.TriggerHitSound(hit.Samples, s.Mods.MusicTime(s.Now))
+				hit = nil // Hit sounds are played once at a hit.
+				continue
+			}
+		}
+		s.Samples.PlayWithVolume(DefaultSampleNames[color][size], s.TransPoint.Volume)
+		s.StoryboardDrawer.Trigger("HitSound", s.Mods.MusicTime(s.Now))
 	}
+	s.StoryboardDrawer.SetFailing(!s.Gauge.Cleared(), s.Mods.MusicTime(s.Now))
+	s.StoryboardDrawer.Update(s.Mods.MusicTime(s.Now))
 	s.StageDrawer.Update(s.Highlight)
 	s.BarDrawer.Update(s.Now)
 	s.JudgmentDrawer.Update(judgment, big)
@@ -328,6 +368,7 @@ func (s *ScenePlay) Update() any {
 	s.ScoreDrawer.Update(s.Scores[gosu.Total])
 	s.ComboDrawer.Update(s.Combo)
 	s.MeterDrawer.Update()
+	s.BreakDrawer.Update(s.Now)
 
 	// Changed speed should be applied after positions are calculated.
 	s.UpdateTransPoint()
@@ -339,6 +380,7 @@ func (s *ScenePlay) Update() any {
 func (s ScenePlay) Draw(screen *ebiten.Image) {
 	// screen.Fill(color.NRGBA{0, 255, 0, 255}) // Chroma-key
 	s.BackgroundDrawer.Draw(screen)
+	s.StoryboardDrawer.Draw(screen)
 	s.StageDrawer.Draw(screen)
 	s.BarDrawer.Draw(screen)
 	s.JudgmentDrawer.Draw(screen)
@@ -352,15 +394,16 @@ func (s ScenePlay) Draw(screen *ebiten.Image) {
 	s.ScoreDrawer.Draw(screen)
 	s.ComboDrawer.Draw(screen)
 	s.MeterDrawer.Draw(screen)
+	s.BreakDrawer.Draw(screen)
 	s.DebugPrint(screen)
 }
 
 func (s ScenePlay) DebugPrint(screen *ebiten.Image) {
 	ebitenutil.DebugPrint(screen, fmt.Sprintf(
 		"\n\n\n\n\n\n\n\n\n\n\n\n\n\n\n\n\n\n\n\n\n\n\n\n\n\n\n\n\n\n"+
-			"Press ESC to select a song.\nPress TAB to pause.\n\n"+
+			"Press ESC or TAB to pause.\nPress Enter to skip intro and breaks.\n\n"+
 			"FPS: %.2f\nTPS: %.2f\nTime: %.3fs/%.0fs\n\n"+
-			"Score: %.0f | %.0f \nFlow: %.0f/100\nCombo: %d\n\n"+
+			"Score: %.0f | %.0f \nFlow: %.0f/100\nCombo: %d\nGauge (%s): %.0f%%\n\n"+
 			"Flow rate: %.2f%%\nAccuracy: %.2f%%\nExtra: %.2f%%\n"+
 			"Judgment counts: %v\nPartial counts: %v\nTick counts: %v\n\n"+
 			"Speed scale (PageUp/Down): %.0f (x%.2f)\n(Exposure time: %.fms)\n\n"+
@@ -368,6 +411,7 @@ func (s ScenePlay) DebugPrint(screen *ebiten.Image) {
 			"Offset (Shift+ Left/Right): %dms\n",
 		ebiten.ActualFPS(), ebiten.ActualTPS(), float64(s.Now)/1000, float64(s.Chart.Duration())/1000,
 		s.Scores[gosu.Total], s.ScoreBounds[gosu.Total], s.Flow*100, s.Combo,
+		gosu.GaugeNames[s.Gauge.Type], s.Gauge.Value*100,
 		s.Ratios[0]*100, s.Ratios[1]*100, s.Ratios[2]*100,
 		s.JudgmentCounts[:3], s.JudgmentCounts[3:5], s.JudgmentCounts[5:],
 		s.SpeedScale*100, s.SpeedScale/s.TransPoint.Speed, ExposureTime(s.CurrentSpeed()),
@@ -386,6 +430,8 @@ func (s *ScenePlay) UpdateTransPoint() {
 func (s ScenePlay) Speed()                { s.CurrentSpeed() }
 func (s ScenePlay) CurrentSpeed() float64 { return s.TransPoint.Speed * s.SpeedScale }
 
+// DefaultSampleNames are keys of default sounds in the sound map.
+// Indices are color and size in order.
 var DefaultSampleNames = [2][2]string{
 	{"red-regular", "red-big"},
 	{"blue-regular", "blue-big"},
@@ -0,0 +1,61 @@
+package drum
+
+import "github.com/hndada/gosu"
+
+// Reset unmarks notes, dots and shakes from the time.
+// A shake is judged again unless it has ended before the time.
+func (s *ScenePlay) Reset(from int64) {
+	c := s.Chart
+	s.StagedNote = nil
+	for i := len(c.Notes) - 1; i >= 0; i-- {
+		n := c.Notes[i]
+		n.Marked = n.Time < from
+		if !n.Marked {
+			s.StagedNote = n
+		}
+	}
+	s.StagedDot = nil
+	for i := len(c.Dots) - 1; i >= 0; i-- {
+		d := c.Dots[i]
+		d.Marked = DotReady
+		if d.Time < from {
+			d.Marked = DotHit
+		} else {
+			s.StagedDot = d
+		}
+	}
+	s.StagedShake = nil
+	for i := len(c.Shakes) - 1; i >= 0; i-- {
+		n := c.Shakes[i]
+		n.Marked = n.Time+n.Duration < from
+		if !n.Marked {
+			n.HitTick = 0
+			s.StagedShake = n
+		}
+	}
+	s.StagedJudgment = gosu.Judgment{}
+	for k := range s.LastHitTimes {
+		s.LastHitTimes[k] = -gosu.Wait
+	}
+	s.ShakeWaitingColor = Red
+	s.TransPoint = c.TransPoints[0].FetchByTime(s.Now)
+}
+
+// Mute closes the music and silences samples.
+func (s *ScenePlay) Mute() {
+	s.MusicPlayer.Close()
+	s.MusicPlayer.Player = nil
+	s.Samples = s.Samples.WithVolume(new(float64))
+}
+
+func (s *ScenePlay) PracticeFields() gosu.PracticeFields {
+	return gosu.PracticeFields{
+		Timer:       &s.Timer,
+		MusicPlayer: &s.MusicPlayer,
+		Scorer:      &s.Scorer,
+		KeyLogger:   &s.KeyLogger,
+		ChartHeader: s.Chart.ChartHeader,
+		TransPoints: s.Chart.TransPoints,
+		Breaks:      s.Breaks,
+	}
+}
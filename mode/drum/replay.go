@@ -3,17 +3,23 @@ package drum
 import (
 	"github.com/hndada/gosu"
 	"github.com/hndada/gosu/format/osr"
+	"github.com/hndada/gosu/format/osu"
 )
 
 // ReplayListener supposes closure function is called every 1 ms.
 // ReplayListener supposes the first the time of replay data is 0ms and no any inputs.
 // Todo: Make sure to ReplayListener time is independent of Game's update tick
-func NewReplayListener(f *osr.Format, timer *gosu.Timer) func() []bool {
-	actions := append(f.ReplayData, osr.Action{W: 2e9})
+func NewReplayListener(f *osr.Format, mods gosu.Mods, timer *gosu.Timer) func() []bool {
+	actions := append(gosu.ReplayActions(f, mods), osr.Action{W: 2e9})
 
 	var i int
 	var next int64 = actions[0].W + actions[1].W // +1
+	last := timer.Now
 	return func() []bool {
+		if timer.Now < last { // Replay starts over when the timer has moved backward.
+			i, next = 0, actions[0].W+actions[1].W
+		}
+		last = timer.Now
 		for timer.Now >= next { // There might be negative values on actions in a row.
 			i++
 			next += actions[i+1].W
@@ -28,3 +34,24 @@ func NewReplayListener(f *osr.Format, timer *gosu.Timer) func() []bool {
 		return pressed
 	}
 }
+
+// NewReplayAction is an inverse of ReplayListener.
+func NewReplayAction(pressed []bool) osr.Action {
+	var z int64
+	for k, v := range []int64{2, 1, 4, 8} {
+		if pressed[k] {
+			z |= v
+		}
+	}
+	return osr.Action{Z: z}
+}
+
+func (s ScenePlay) NewReplay() *osr.Format {
+	f := gosu.NewReplay(osu.ModeTaiko, s.NewResult(s.Chart.MD5, s.Mods), s.Actions)
+	counts := s.JudgmentCounts
+	f.Num300 = int16(counts[Cools])
+	f.Num100 = int16(counts[Goods])
+	f.NumMiss = int16(counts[Misses])
+	f.FullCombo = counts[Misses] == 0
+	return f
+}
@@ -0,0 +1,34 @@
+package drum
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/hndada/gosu"
+	"github.com/hndada/gosu/audios"
+)
+
+// LoadSamples registers default sounds of skin and custom hit sounds of the chart.
+// Default hit sounds of other modes are not loaded: Drum has its own.
+func LoadSamples(sm audios.SoundMap, cpath string, c *Chart) error {
+	for size, sizeName := range []string{"regular", "big"} {
+		for color, colorName := range []string{"red", "blue"} {
+			path := fmt.Sprintf("skin/drum/sound/%s/%s.wav", sizeName, colorName)
+			b, err := os.ReadFile(path)
+			if err != nil {
+				return err
+			}
+			if err := sm.RegisterBytes(DefaultSampleNames[color][size], b, ".wav"); err != nil {
+				return err
+			}
+		}
+	}
+	fpath, _ := gosu.SplitChartPath(cpath)
+	var samples []gosu.Sample
+	for _, n := range c.Notes {
+		samples = append(samples, n.Samples...)
+	}
+	gosu.LoadSamples(sm, filepath.Dir(fpath), samples, nil)
+	return nil
+}
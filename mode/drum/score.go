@@ -17,6 +17,16 @@ var (
 )
 var Judgments = []gosu.Judgment{Cool, Good, Miss}
 
+// Gauge changes by judgment for each gauge type.
+// Rolls and shakes do not change the gauge.
+var GaugeTables = [][]float64{
+	{0.01, 0.005, -0.05},  // Normal
+	{0.012, 0.006, -0.04}, // Easy
+	{0.0016, 0, -0.08},    // Hard
+	{0, 0, -1},            // Sudden death
+	{0, -1, -1},           // Perfect only
+}
+
 var JudgmentColors = []color.NRGBA{
 	gosu.ColorCool,
 	gosu.ColorGood,
@@ -121,16 +131,19 @@ func (s *ScenePlay) MarkNote(n *Note, j gosu.Judgment, big bool) {
 	switch j.Window {
 	case Cool.Window:
 		s.JudgmentCounts[Cools]++
+		s.Gauge.Update(0)
 		if n.Size == Big && !big {
 			s.JudgmentCounts[CoolPartials]++
 		}
 	case Good.Window:
 		s.JudgmentCounts[Goods]++
+		s.Gauge.Update(1)
 		if n.Size == Big && !big {
 			s.JudgmentCounts[GoodPartials]++
 		}
 	case Miss.Window:
 		s.JudgmentCounts[Misses]++
+		s.Gauge.Update(2)
 	}
 	n.Marked = true
 	s.StagedNote = s.StagedNote.Next
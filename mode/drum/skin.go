@@ -282,4 +282,7 @@ func LoadSkin() {
 		s.SetPosition(keyCenter, FieldPosition, draws.OriginCenterMiddle)
 		skin.ComboSprites[i] = s
 	}
+	if f, ok := gosu.LoadOsuSkin(); ok {
+		loadOsuSkin(&skin, f)
+	}
 }
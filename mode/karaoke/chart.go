@@ -0,0 +1,97 @@
+package karaoke
+
+import (
+	"fmt"
+
+	"github.com/hndada/gosu"
+)
+
+type Chart struct {
+	gosu.ChartHeader
+	MD5         [16]byte
+	TransPoints []*gosu.TransPoint
+	Notes       []*Note
+	Lines       []Line
+
+	Level        float64
+	ScoreFactors [3]float64
+}
+
+// NewChart takes file path as input for starting with parsing.
+// Chart data should not rely on the ChartInfo; users may have modified it.
+// Rate of mods is applied to the chart.
+func NewChart(cpath string, mods gosu.Mods) (c *Chart, err error) {
+	f, err := gosu.LoadChartFile(cpath)
+	if err != nil {
+		return
+	}
+	if f.Mode != gosu.ModeKaraoke {
+		err = fmt.Errorf("not a karaoke chart")
+		return
+	}
+	f.SetRate(mods.Rate)
+	c = new(Chart)
+	c.ChartHeader = f.ChartHeader
+	c.MD5 = f.MD5
+	c.TransPoints = gosu.NewTransPoints(f.TimingPoints)
+	if len(c.TransPoints) == 0 {
+		err = fmt.Errorf("no TransPoints in the chart")
+		return
+	}
+	c.Notes = NewNotes(f.HitObjects)
+	if len(c.Notes) == 0 {
+		err = fmt.Errorf("no syllables in the chart")
+		return
+	}
+	c.Lines = NewLines(c.Notes)
+	if len(c.Breaks) == 0 {
+		c.Breaks = gosu.NewBreaks(c.intervals())
+	}
+	c.Level, c.ScoreFactors = gosu.Level(c)
+	return
+}
+
+// intervals returns time intervals of lines.
+func (c Chart) intervals() [][2]int64 {
+	ivs := make([][2]int64, 0, len(c.Lines))
+	for _, l := range c.Lines {
+		ivs = append(ivs, [2]int64{l.Time(), l.EndTime()})
+	}
+	return ivs
+}
+
+func (c Chart) FirstTime() int64 { return c.Notes[0].Time }
+
+func (c Chart) Duration() (last int64) {
+	for _, n := range c.Notes {
+		if last2 := n.Time + n.Duration; last < last2 {
+			last = last2
+		}
+	}
+	return
+}
+func (c Chart) NoteCounts() []int { return []int{len(c.Notes)} }
+func (c Chart) BPMs() (main, min, max float64) {
+	return gosu.BPMs(c.TransPoints, c.Duration())
+}
+func NewChartInfo(cpath string) (info gosu.ChartInfo, err error) {
+	c, err := NewChart(cpath, gosu.NewMods())
+	if err != nil {
+		return
+	}
+	main, min, max := c.BPMs()
+	info = gosu.ChartInfo{
+		Path:        cpath,
+		MD5:         c.MD5,
+		ChartHeader: c.ChartHeader,
+		Mode:        gosu.ModeKaraoke,
+		SubMode:     0,
+		Level:       c.Level,
+		Duration:    c.Duration(),
+		NoteCounts:  c.NoteCounts(),
+		MainBPM:     main,
+		MinBPM:      min,
+		MaxBPM:      max,
+	}
+	return
+}
@@ -0,0 +1,184 @@
+package karaoke
+
+import (
+	"image"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/text"
+	"github.com/hndada/gosu"
+	"github.com/hndada/gosu/draws"
+)
+
+// StageDrawer flashes the hit line when a key is hit.
+type StageDrawer struct {
+	draws.BaseDrawer
+	FieldSprite   draws.Sprite
+	HitLineSprite draws.Sprite
+}
+
+func (d *StageDrawer) Update(hit bool) {
+	if d.Countdown > 0 {
+		d.Countdown--
+	}
+	if hit {
+		d.Countdown = d.MaxCountdown
+	}
+}
+func (d StageDrawer) Draw(screen *ebiten.Image) {
+	d.FieldSprite.Draw(screen, nil)
+	op := &ebiten.DrawImageOptions{}
+	if d.Countdown > 0 {
+		op.ColorM.Scale(1, 1, 0.5, 1)
+	}
+	d.HitLineSprite.Draw(screen, op)
+}
+
+// NoteDrawer draws syllables scrolling to the hit line.
+// A syllable has a body which lasts as long as it is sung.
+type NoteDrawer struct {
+	Time       int64
+	Speed      float64
+	Notes      []*Note
+	NoteSprite draws.Sprite
+	BodySprite draws.Sprite
+	Hidden     bool
+}
+
+func (d *NoteDrawer) Update(time int64, speed float64) {
+	d.Time = time
+	d.Speed = speed
+}
+func (d NoteDrawer) Draw(screen *ebiten.Image) {
+	max := len(d.Notes) - 1
+	for i := range d.Notes {
+		n := d.Notes[max-i]
+		pos := n.Position(d.Time, d.Speed)
+		length := float64(n.Duration) * d.Speed
+		if pos > maxPosition || pos+length < minPosition {
+			continue
+		}
+		op := &ebiten.DrawImageOptions{}
+		if d.Hidden {
+			op.ColorM.Scale(1, 1, 1, gosu.Fade(pos, HiddenPositions[0], HiddenPositions[1]))
+		}
+		if length > 0 {
+			body := d.BodySprite
+			body.SetScaleXY(length/body.W(), 1, ebiten.FilterNearest)
+			body.Move(pos, 0)
+			body.Draw(screen, op)
+		}
+		clr := ColorNote
+		if n.Marked {
+			clr = ColorSung
+		}
+		op.ColorM.ScaleWithColor(clr)
+		note := d.NoteSprite
+		note.Move(pos, 0)
+		note.Draw(screen, op)
+
+		b := text.BoundString(gosu.Face20, n.Text)
+		x := HitPosition + pos - float64(b.Dx())/2
+		y := FieldPosition + NoteHeight - float64(b.Min.Y)
+		op.GeoM.Translate(x, y)
+		text.DrawWithOptions(screen, n.Text, gosu.Face20, op)
+	}
+}
+
+// LyricsDrawer shows the current line of lyrics and the next one.
+// Syllables of the current line are colored as being sung.
+type LyricsDrawer struct {
+	Time   int64
+	Lines  []Line
+	Cursor int // Index of the current line.
+}
+
+func (d *LyricsDrawer) Update(time int64) {
+	d.Time = time
+	for d.Cursor < len(d.Lines)-1 && time >= d.Lines[d.Cursor].EndTime() &&
+		time >= d.Lines[d.Cursor+1].Time()-LyricsPreviewTime {
+		d.Cursor++
+	}
+}
+
+// LyricsPreviewTime is how early the next line goes current.
+const LyricsPreviewTime = 1000
+
+func (d LyricsDrawer) Draw(screen *ebiten.Image) {
+	if d.Cursor >= len(d.Lines) {
+		return
+	}
+	d.drawLine(screen, d.Lines[d.Cursor], LyricsPosition, true)
+	if d.Cursor+1 < len(d.Lines) {
+		d.drawLine(screen, d.Lines[d.Cursor+1], LyricsPosition+LyricsGap, false)
+	}
+}
+
+// Sung part of a syllable is colored proportionally to the time.
+func (d LyricsDrawer) drawLine(screen *ebiten.Image, l Line, y float64, current bool) {
+	face := gosu.Face24
+	var w int
+	for _, n := range l.Notes {
+		w += text.BoundString(face, n.Text).Dx()
+	}
+	x := int(screenSizeX/2) - w/2
+	for _, n := range l.Notes {
+		b := text.BoundString(face, n.Text)
+		if !current {
+			text.Draw(screen, n.Text, face, x-b.Min.X, int(y), ColorNext)
+			x += b.Dx()
+			continue
+		}
+		text.Draw(screen, n.Text, face, x-b.Min.X, int(y), ColorUnsung)
+		rate := 1.0
+		if n.Duration > 0 {
+			rate = float64(d.Time-n.Time) / float64(n.Duration)
+		} else if d.Time < n.Time {
+			rate = 0
+		}
+		if rate > 0 {
+			if rate > 1 {
+				rate = 1
+			}
+			// Drawing on sub-image is clipped by its bounds.
+			r := image.Rect(x, int(y)+b.Min.Y, x+int(float64(b.Dx())*rate), int(y)+b.Max.Y)
+			sung := screen.SubImage(r).(*ebiten.Image)
+			text.Draw(sung, n.Text, face, x-b.Min.X, int(y), ColorSung)
+		}
+		x += b.Dx()
+	}
+}
+
+type JudgmentDrawer struct {
+	draws.BaseDrawer
+	Sprites  [3]draws.Sprite
+	Judgment gosu.Judgment
+}
+
+func (d *JudgmentDrawer) Update(j gosu.Judgment) {
+	if d.Countdown <= 0 {
+		d.Judgment = gosu.Judgment{}
+	} else {
+		d.Countdown--
+	}
+	if j.Valid() {
+		d.Judgment = j
+		d.Countdown = d.MaxCountdown
+	}
+}
+func (d JudgmentDrawer) Draw(screen *ebiten.Image) {
+	if d.Countdown <= 0 || !d.Judgment.Valid() {
+		return
+	}
+	var sprite draws.Sprite
+	for i, j := range Judgments {
+		if d.Judgment.Is(j) {
+			sprite = d.Sprites[i]
+			break
+		}
+	}
+	op := &ebiten.DrawImageOptions{}
+	if age := d.Age(); age > 0.75 {
+		op.ColorM.Scale(1, 1, 1, 1-(age-0.75)/0.25)
+	}
+	sprite.Draw(screen, op)
+}
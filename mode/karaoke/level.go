@@ -0,0 +1,17 @@
+package karaoke
+
+// Difficulty goes with the density of syllables.
+// Todo: implement actual calculating chart difficulties
+func (c Chart) Difficulties() []float64 {
+	const sectionDuration = 800
+	sectionCount := c.Duration()/sectionDuration + 1
+	ds := make([]float64, sectionCount)
+	for _, n := range c.Notes {
+		i := n.Time / sectionDuration
+		if i < 0 {
+			i = 0
+		}
+		ds[i] += n.Weight()
+	}
+	return ds
+}
@@ -0,0 +1,21 @@
+package karaoke
+
+import (
+	"time"
+
+	"github.com/hndada/gosu"
+)
+
+var ModeKaraoke = gosu.ModeProp{
+	Name:           "Karaoke",
+	Mode:           gosu.ModeKaraoke,
+	ChartInfos:     make([]gosu.ChartInfo, 0),      // Zero value.
+	Results:        make(map[[16]byte]gosu.Result), // Zero value.
+	LastUpdateTime: time.Time{},                    // Zero value.
+	LoadSkin:       LoadSkin,
+	SpeedScale:     &SpeedScale,
+	NewChartInfo:   NewChartInfo,
+	NewScenePlay:   NewScenePlay,
+	ExposureTime:   ExposureTime,
+	KeySettings:    KeySettings,
+}
@@ -0,0 +1,25 @@
+package karaoke
+
+import "github.com/hndada/gosu"
+
+// Syllables fade out between the positions with Hidden.
+// Positions are distances from the hit position.
+var HiddenPositions = [2]float64{screenSizeX * 0.15, screenSizeX * 0.3}
+
+// AutoHoldTime is how long Auto holds a key for a tap.
+const AutoHoldTime = 30
+
+// NewAutoListener taps syllables at the exact time with each key in turn,
+// unless humanized.
+func NewAutoListener(c *Chart, h *gosu.Humanizer, timer *gosu.Timer) func() []bool {
+	keyCount := len(KeySettings[2])
+	ivs := make(gosu.KeyIntervals, keyCount)
+	for i, n := range c.Notes {
+		if h.Miss() {
+			continue
+		}
+		t := n.Time + h.Error()
+		ivs.Add(i%keyCount, t, t+AutoHoldTime)
+	}
+	return ivs.Listener(timer)
+}
@@ -0,0 +1,80 @@
+package karaoke
+
+import (
+	"strings"
+
+	"github.com/hndada/gosu"
+)
+
+// Note is a syllable of lyrics. Syllable is tapped when starts to be sung.
+type Note struct {
+	Time     int64
+	Duration int64 // How long the syllable is sung.
+	Text     string
+	Line     int
+
+	Marked bool
+	Next   *Note
+	Prev   *Note
+}
+
+// Blank syllables are not notes; their texts go to previous notes.
+func NewNotes(hs []gosu.HitObject) []*Note {
+	ns := make([]*Note, 0, len(hs))
+	var prev *Note
+	for _, h := range hs {
+		if strings.TrimSpace(h.Text) == "" {
+			if prev != nil && prev.Line == h.Line {
+				prev.Text += h.Text
+			}
+			continue
+		}
+		n := &Note{
+			Time:     h.Time,
+			Duration: h.Duration,
+			Text:     h.Text,
+			Line:     h.Line,
+			Prev:     prev,
+		}
+		if prev != nil {
+			prev.Next = n
+		}
+		ns = append(ns, n)
+		prev = n
+	}
+	return ns
+}
+
+// Position is a distance from the hit position at the speed.
+func (n Note) Position(time int64, speed float64) float64 {
+	return float64(n.Time-time) * speed
+}
+
+func (n Note) Weight() float64 { return 1 }
+
+// Line is a line of lyrics, which is shown while being sung.
+type Line struct {
+	Notes []*Note
+}
+
+// NewLines groups notes by lines in order of appearance.
+func NewLines(ns []*Note) []Line {
+	var ls []Line
+	index := make(map[int]int) // Line of note to index of lines.
+	for _, n := range ns {
+		i, ok := index[n.Line]
+		if !ok {
+			i = len(ls)
+			index[n.Line] = i
+			ls = append(ls, Line{})
+		}
+		ls[i].Notes = append(ls[i].Notes, n)
+	}
+	return ls
+}
+
+func (l Line) Time() int64 { return l.Notes[0].Time }
+func (l Line) EndTime() int64 {
+	n := l.Notes[len(l.Notes)-1]
+	return n.Time + n.Duration
+}
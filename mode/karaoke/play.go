@@ -0,0 +1,218 @@
+package karaoke
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/ebitenutil"
+	"github.com/hndada/gosu"
+	"github.com/hndada/gosu/draws"
+	"github.com/hndada/gosu/format/osr"
+	"github.com/hndada/gosu/input"
+)
+
+type ScenePlay struct {
+	Chart *Chart
+	Mods  gosu.Mods
+	gosu.Timer
+	gosu.MusicPlayer
+	Breaks []gosu.Break // Intro is included.
+	gosu.KeyLogger
+
+	*gosu.TransPoint
+	Staged *Note
+	gosu.Scorer
+
+	Skin
+	BackgroundDrawer gosu.BackgroundDrawer
+	StoryboardDrawer gosu.StoryboardDrawer
+	BreakDrawer      gosu.BreakDrawer
+	StageDrawer      StageDrawer
+	NoteDrawer       NoteDrawer
+	LyricsDrawer     LyricsDrawer
+	JudgmentDrawer   JudgmentDrawer
+
+	ScoreDrawer gosu.ScoreDrawer
+	ComboDrawer gosu.NumberDrawer
+	MeterDrawer gosu.MeterDrawer
+}
+
+func NewScenePlay(cpath string, mods gosu.Mods, rf *osr.Format) (scene gosu.Scene, err error) {
+	s := new(ScenePlay)
+	if mods.Auto && mods.Seed == 0 {
+		mods.Seed = time.Now().UnixNano()
+	}
+	s.Mods = mods
+	s.Chart, err = NewChart(cpath, mods)
+	if err != nil {
+		return
+	}
+	c := s.Chart
+	gosu.SetTitle(c.ChartHeader)
+	s.Timer = gosu.NewTimer(c.Duration(), mods.Rate)
+	if path, ok := c.MusicPath(cpath); ok {
+		s.MusicPlayer, err = gosu.NewMusicPlayer(path, &s.Timer, mods.Nightcore)
+		if err != nil {
+			return
+		}
+	}
+	s.Breaks = gosu.PlayBreaks(c.Breaks, c.FirstTime())
+	s.KeyLogger = gosu.NewKeyLogger(KeySettings[2])
+	switch {
+	case rf != nil:
+		s.KeyLogger.FetchPressed = NewReplayListener(rf, mods, &s.Timer)
+		s.KeyLogger.Actions = nil
+	case mods.Auto:
+		h := gosu.NewHumanizer(mods.Seed)
+		s.KeyLogger.FetchPressed = NewAutoListener(c, h, &s.Timer)
+		if !gosu.ExportAutoReplay {
+			s.KeyLogger.Actions = nil
+		}
+	}
+
+	s.TransPoint = c.TransPoints[0]
+	s.Staged = c.Notes[0]
+	s.Scorer = gosu.NewScorer(c.ScoreFactors)
+	s.JudgmentCounts = make([]int, len(Judgments))
+	s.Gauge = gosu.NewGauge(mods.Gauge, GaugeTables, mods.NoFail)
+	for _, n := range c.Notes {
+		s.MaxWeights[gosu.Flow] += n.Weight()
+	}
+	s.MaxWeights[gosu.Acc] = s.MaxWeights[gosu.Flow]
+	s.SetMaxScores()
+
+	s.Skin = DefaultSkin
+	s.BackgroundDrawer = gosu.BackgroundDrawer{
+		Brightness: &gosu.BackgroundBrightness,
+		Sprite:     gosu.DefaultBackground,
+	}
+	if bg := gosu.NewBackground(c.BackgroundPath(cpath)); bg.IsValid() {
+		s.BackgroundDrawer.Sprite = bg
+	}
+	s.StoryboardDrawer = gosu.NewStoryboardDrawer(cpath)
+	s.BreakDrawer = gosu.NewBreakDrawer(s.Breaks, c.LetterboxInBreaks)
+	s.StageDrawer = StageDrawer{
+		BaseDrawer: draws.BaseDrawer{
+			MaxCountdown: gosu.TimeToTick(75),
+		},
+		FieldSprite:   s.FieldSprite,
+		HitLineSprite: s.HitLineSprite,
+	}
+	s.NoteDrawer = NoteDrawer{
+		Time:       s.Now,
+		Speed:      SpeedScale,
+		Notes:      c.Notes,
+		NoteSprite: s.NoteSprite,
+		BodySprite: s.BodySprite,
+		Hidden:     mods.Hidden,
+	}
+	s.LyricsDrawer = LyricsDrawer{
+		Time:  s.Now,
+		Lines: c.Lines,
+	}
+	s.JudgmentDrawer = JudgmentDrawer{
+		BaseDrawer: draws.BaseDrawer{
+			MaxCountdown: gosu.TimeToTick(600),
+		},
+		Sprites: s.JudgmentSprites,
+	}
+	s.ScoreDrawer = gosu.NewScoreDrawer()
+	s.ComboDrawer = gosu.NumberDrawer{
+		BaseDrawer: draws.BaseDrawer{
+			MaxCountdown: gosu.TimeToTick(2000),
+		},
+		Sprites:    s.ComboSprites,
+		DigitWidth: s.ComboSprites[0].W(),
+		DigitGap:   ComboDigitGap,
+		Bounce:     0.85,
+	}
+	s.MeterDrawer = gosu.NewMeterDrawer(Judgments, JudgmentColors)
+	return s, nil
+}
+
+func (s *ScenePlay) Update() any {
+	defer s.Ticker()
+	if s.IsDone() {
+		s.MusicPlayer.Close()
+		if s.Actions != nil && !s.Quit { // Quit plays are not exported.
+			if err := gosu.ExportReplay(s.NewReplay(), s.Chart.ChartHeader); err != nil {
+				fmt.Printf("error at exporting replay: %s\n", err)
+			}
+		}
+		return gosu.PlayToResultArgs{Result: s.NewResult(s.Chart.MD5, s.Mods)}
+	}
+	gosu.Skip(s.Breaks, &s.Timer, &s.MusicPlayer)
+	s.MusicPlayer.Update()
+
+	s.LastPressed = s.Pressed
+	s.Pressed = s.FetchPressed()
+	s.Record(s.Now, NewReplayAction(s.Pressed))
+	a := s.TapAction()
+	var judgment gosu.Judgment
+	if n := s.Staged; n != nil {
+		// A negative value means late hit. Judgment windows are of music.
+		td := s.Mods.MusicTime(n.Time - s.Now)
+		if j := gosu.Verdict(Judgments, a, td); j.Valid() {
+			s.MarkNote(n, j)
+			if !j.Is(Miss) {
+				s.MeterDrawer.AddMark(int(td), 0)
+			}
+			judgment = j
+		}
+	}
+	if a == input.Hit {
+		s.StoryboardDrawer.Trigger("HitSound", s.Mods.MusicTime(s.Now))
+	}
+	s.StoryboardDrawer.SetFailing(!s.Gauge.Cleared(), s.Mods.MusicTime(s.Now))
+	s.StoryboardDrawer.Update(s.Mods.MusicTime(s.Now))
+	s.StageDrawer.Update(a == input.Hit)
+	s.NoteDrawer.Update(s.Now, SpeedScale)
+	s.LyricsDrawer.Update(s.Now)
+	s.JudgmentDrawer.Update(judgment)
+	s.ScoreDrawer.Update(s.Scores[gosu.Total])
+	s.ComboDrawer.Update(s.Combo)
+	s.MeterDrawer.Update()
+	s.BreakDrawer.Update(s.Now)
+	s.TransPoint = s.TransPoint.FetchByTime(s.Now)
+	return nil
+}
+func (s ScenePlay) Draw(screen *ebiten.Image) {
+	s.BackgroundDrawer.Draw(screen)
+	s.StoryboardDrawer.Draw(screen)
+	s.StageDrawer.Draw(screen)
+	s.NoteDrawer.Draw(screen)
+	s.LyricsDrawer.Draw(screen)
+	s.JudgmentDrawer.Draw(screen)
+	s.ScoreDrawer.Draw(screen)
+	s.ComboDrawer.Draw(screen)
+	s.MeterDrawer.Draw(screen)
+	s.BreakDrawer.Draw(screen)
+	s.DebugPrint(screen)
+}
+
+func (s ScenePlay) DebugPrint(screen *ebiten.Image) {
+	ebitenutil.DebugPrint(screen, fmt.Sprintf(
+		"\n\n\n\n\n\n\n\n\n\n\n\n\n\n\n\n\n\n\n\n\n\n\n\n\n\n\n\n\n\n"+
+			"Press ESC or TAB to pause.\nPress Enter to skip intro and breaks.\n\n"+
+			"FPS: %.2f\nTPS: %.2f\nTime: %.3fs/%.0fs\n\n"+
+			"Score: %.0f | %.0f \nFlow: %.0f/100\nCombo: %d\nGauge (%s): %.0f%%\n\n"+
+			"Flow rate: %.2f%%\nAccuracy: %.2f%%\n"+
+			"Judgment counts: %v\n\n"+
+			"Speed scale (PageUp/Down): %.0f\n(Exposure time: %.fms)\n\n"+
+			"Music volume (Alt+ Left/Right): %.0f%%\nEffect volume (Ctrl+ Left/Right): %.0f%%\n\n"+
+			"Offset (Shift+ Left/Right): %dms\n",
+		ebiten.ActualFPS(), ebiten.ActualTPS(), float64(s.Now)/1000, float64(s.Chart.Duration())/1000,
+		s.Scores[gosu.Total], s.ScoreBounds[gosu.Total], s.Flow*100, s.Combo,
+		gosu.GaugeNames[s.Gauge.Type], s.Gauge.Value*100,
+		s.Ratios[0]*100, s.Ratios[1]*100,
+		s.JudgmentCounts,
+		SpeedScale*100, ExposureTime(SpeedScale),
+		gosu.MusicVolume*100, gosu.EffectVolume*100,
+		gosu.Offset))
+}
+
+// 1 pixel is 1 millisecond at speed scale 1.
+func ExposureTime(speedScale float64) float64 {
+	return (screenSizeX - HitPosition) / speedScale
+}
@@ -0,0 +1,36 @@
+package karaoke
+
+import "github.com/hndada/gosu"
+
+// Reset unmarks syllables from the time.
+func (s *ScenePlay) Reset(from int64) {
+	c := s.Chart
+	s.Staged = nil
+	for i := len(c.Notes) - 1; i >= 0; i-- {
+		n := c.Notes[i]
+		n.Marked = n.Time < from
+		if !n.Marked {
+			s.Staged = n
+		}
+	}
+	s.LyricsDrawer.Cursor = 0 // Cursor only goes forward at Update.
+	s.TransPoint = c.TransPoints[0].FetchByTime(s.Now)
+}
+
+// Mute closes the music.
+func (s *ScenePlay) Mute() {
+	s.MusicPlayer.Close()
+	s.MusicPlayer.Player = nil
+}
+
+func (s *ScenePlay) PracticeFields() gosu.PracticeFields {
+	return gosu.PracticeFields{
+		Timer:       &s.Timer,
+		MusicPlayer: &s.MusicPlayer,
+		Scorer:      &s.Scorer,
+		KeyLogger:   &s.KeyLogger,
+		ChartHeader: s.Chart.ChartHeader,
+		TransPoints: s.Chart.TransPoints,
+		Breaks:      s.Breaks,
+	}
+}
@@ -0,0 +1,56 @@
+package karaoke
+
+import (
+	"github.com/hndada/gosu"
+	"github.com/hndada/gosu/format/osr"
+	"github.com/hndada/gosu/format/osu"
+)
+
+// Replay of Karaoke is written in the same way as Piano's:
+// X of an action is a bit mask of pressed keys.
+func NewReplayListener(f *osr.Format, mods gosu.Mods, timer *gosu.Timer) func() []bool {
+	actions := append(gosu.ReplayActions(f, mods), osr.Action{W: 2e9})
+	keyCount := len(KeySettings[2])
+
+	var i int
+	var next int64 = actions[0].W + actions[1].W
+	last := timer.Now
+	return func() []bool {
+		if timer.Now < last { // Replay starts over when the timer has moved backward.
+			i, next = 0, actions[0].W+actions[1].W
+		}
+		last = timer.Now
+		for timer.Now >= next {
+			i++
+			next += actions[i+1].W
+		}
+		pressed := make([]bool, keyCount)
+		x := int(actions[i].X)
+		for k := range pressed {
+			pressed[k] = x&(1<<k) != 0
+		}
+		return pressed
+	}
+}
+
+// NewReplayAction is an inverse of ReplayListener.
+func NewReplayAction(pressed []bool) osr.Action {
+	var x int
+	for k, p := range pressed {
+		if p {
+			x |= 1 << k
+		}
+	}
+	return osr.Action{X: float64(x)}
+}
+
+// Karaoke has no game mode in osu!. Replay goes as mania's.
+func (s ScenePlay) NewReplay() *osr.Format {
+	f := gosu.NewReplay(osu.ModeMania, s.NewResult(s.Chart.MD5, s.Mods), s.Actions)
+	counts := s.JudgmentCounts
+	f.Num300 = int16(counts[Cools])
+	f.Num100 = int16(counts[Goods])
+	f.NumMiss = int16(counts[Misses])
+	f.FullCombo = counts[Misses] == 0
+	return f
+}
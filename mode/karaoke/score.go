@@ -0,0 +1,77 @@
+package karaoke
+
+import (
+	"image/color"
+
+	"github.com/hndada/gosu"
+	"github.com/hndada/gosu/input"
+)
+
+// Judgment windows are wider than other modes':
+// syllables are tapped along with singing.
+var (
+	Cool = gosu.Judgment{Flow: 0.01, Acc: 1, Window: 40}
+	Good = gosu.Judgment{Flow: 0.01, Acc: 0.5, Window: 80}
+	Miss = gosu.Judgment{Flow: -1, Acc: 0, Window: 120}
+)
+var Judgments = []gosu.Judgment{Cool, Good, Miss}
+
+// Gauge changes by judgment for each gauge type.
+var GaugeTables = [][]float64{
+	{0.01, 0.005, -0.04},   // Normal
+	{0.012, 0.006, -0.032}, // Easy
+	{0.0016, 0, -0.06},     // Hard
+	{0, 0, -1},             // Sudden death
+	{0, -1, -1},            // Perfect only
+}
+
+var JudgmentColors = []color.NRGBA{
+	gosu.ColorCool,
+	gosu.ColorGood,
+	gosu.ColorBad,
+}
+
+const (
+	Cools = iota // Stands for Cool counts.
+	Goods
+	Misses
+)
+
+// TapAction is Hit when any key is hit.
+func (s ScenePlay) TapAction() input.KeyAction {
+	for k := range s.Pressed {
+		if a := s.KeyLogger.KeyAction(k); a == input.Hit {
+			return a
+		}
+	}
+	return input.Idle
+}
+
+func (s *ScenePlay) MarkNote(n *Note, j gosu.Judgment) {
+	if j.Is(Miss) {
+		s.BreakCombo()
+	} else {
+		s.AddCombo()
+	}
+	s.CalcScore(gosu.Flow, j.Flow, n.Weight())
+	s.CalcScore(gosu.Acc, j.Acc, n.Weight())
+	for i, j2 := range Judgments {
+		if j.Is(j2) {
+			s.JudgmentCounts[i]++
+			s.Gauge.Update(i)
+			break
+		}
+	}
+	n.Marked = true
+	s.Staged = n.Next
+}
+
+// Karaoke has no Extra score.
+// Its max score is distributed to Flow and Acc score by 7:3.
+func (s *ScenePlay) SetMaxScores() {
+	extra := gosu.DefaultMaxScores[gosu.Extra]
+	s.MaxScores[gosu.Flow] += extra * 0.7
+	s.MaxScores[gosu.Acc] += extra * 0.3
+	s.MaxScores[gosu.Extra] = 0
+	s.Scorer.SetMaxScores(s.MaxScores)
+}
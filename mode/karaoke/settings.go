@@ -0,0 +1,44 @@
+package karaoke
+
+import (
+	"github.com/hndada/gosu"
+	"github.com/hndada/gosu/input"
+)
+
+// Logical size of in-game screen.
+const (
+	screenSizeX = gosu.ScreenSizeX
+	screenSizeY = gosu.ScreenSizeY
+)
+
+var SpeedScale float64 = 0.5
+
+// Any of the keys taps a syllable.
+var KeySettings = map[int][]input.Key{
+	2: {input.KeyF, input.KeyJ},
+}
+
+const PositionMargin = 100
+
+// Syllables scroll along the lane from right to left,
+// and lyrics are shown under the lane.
+var (
+	FieldDarkness float64 = 0.7
+	FieldPosition float64 = screenSizeY * 0.35
+	FieldHeight   float64 = screenSizeY * 0.18
+	NoteHeight    float64 = FieldHeight * 0.4
+	BodyHeight    float64 = NoteHeight * 0.5
+
+	HitPosition    float64 = screenSizeX * 0.2
+	minPosition    float64 = -HitPosition - PositionMargin
+	maxPosition    float64 = -HitPosition + screenSizeX + PositionMargin
+	LyricsPosition float64 = screenSizeY * 0.65
+	LyricsGap      float64 = screenSizeY * 0.08 // Between the current line and the next.
+)
+
+// Skin-dependent settings.
+var (
+	JudgmentScale float64 = 1
+	ComboScale    float64 = 0.75
+	ComboDigitGap float64 = screenSizeX * -0.001
+)
@@ -0,0 +1,107 @@
+package karaoke
+
+import (
+	"fmt"
+	"image/color"
+	_ "image/jpeg"
+	_ "image/png"
+	"strings"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/text"
+	"github.com/hndada/gosu"
+	"github.com/hndada/gosu/draws"
+)
+
+var (
+	ColorNote   = color.NRGBA{255, 255, 255, 255}
+	ColorSung   = color.NRGBA{255, 170, 0, 255} // Sung syllables of lyrics.
+	ColorUnsung = color.NRGBA{255, 255, 255, 255}
+	ColorNext   = color.NRGBA{160, 160, 160, 255} // The next line of lyrics.
+)
+
+var DefaultSkin Skin
+
+// Karaoke needs no image files: a sprite is drawn in code
+// unless its file is found at skin/karaoke.
+type Skin struct {
+	FieldSprite     draws.Sprite
+	HitLineSprite   draws.Sprite
+	JudgmentSprites [3]draws.Sprite
+
+	NoteSprite draws.Sprite
+	BodySprite draws.Sprite
+
+	ScoreSprites [10]draws.Sprite
+	ComboSprites [10]draws.Sprite
+}
+
+func LoadSkin() {
+	var skin Skin
+	defer func() { DefaultSkin = skin }()
+	{
+		src := ebiten.NewImage(screenSizeX, int(FieldHeight))
+		src.Fill(color.NRGBA{0, 0, 0, uint8(255 * FieldDarkness)})
+		s := draws.NewSpriteFromImage(src)
+		s.SetPosition(0, FieldPosition, draws.OriginLeftMiddle)
+		skin.FieldSprite = s
+	}
+	{
+		s := newSprite("skin/karaoke/hit-line.png", func() *ebiten.Image {
+			src := ebiten.NewImage(4, int(FieldHeight))
+			src.Fill(color.NRGBA{255, 255, 255, 192})
+			return src
+		})
+		s.SetScaleXY(1, FieldHeight/s.H(), ebiten.FilterLinear)
+		s.SetPosition(HitPosition, FieldPosition, draws.OriginCenterMiddle)
+		skin.HitLineSprite = s
+	}
+	for i, name := range []string{"Cool", "Good", "Miss"} {
+		path := fmt.Sprintf("skin/karaoke/judgment/%s.png", strings.ToLower(name))
+		s := newSprite(path, func() *ebiten.Image {
+			return newTextImage(name, JudgmentColors[i])
+		})
+		s.SetScale(JudgmentScale)
+		s.SetPosition(HitPosition, FieldPosition-FieldHeight/2, draws.OriginCenterBottom)
+		skin.JudgmentSprites[i] = s
+	}
+	{
+		s := newSprite("skin/karaoke/note.png", func() *ebiten.Image {
+			src := ebiten.NewImage(int(NoteHeight), int(NoteHeight))
+			src.Fill(color.White)
+			return src
+		})
+		s.SetScale(NoteHeight / s.H())
+		s.SetPosition(HitPosition, FieldPosition, draws.OriginCenterMiddle)
+		skin.NoteSprite = s
+	}
+	{
+		src := ebiten.NewImage(1, int(BodyHeight))
+		src.Fill(color.NRGBA{255, 255, 255, 128})
+		s := draws.NewSpriteFromImage(src)
+		s.SetPosition(HitPosition, FieldPosition, draws.OriginLeftMiddle)
+		skin.BodySprite = s
+	}
+	skin.ScoreSprites = gosu.ScoreSprites
+	for i := 0; i < 10; i++ {
+		s := draws.NewSprite(fmt.Sprintf("skin/combo/%d.png", i))
+		s.SetScale(ComboScale)
+		s.SetPosition(HitPosition, FieldPosition+FieldHeight/2, draws.OriginCenterTop)
+		skin.ComboSprites[i] = s
+	}
+}
+
+// newSprite loads a sprite from the path, or draws one when failed.
+func newSprite(path string, draw func() *ebiten.Image) draws.Sprite {
+	if img := draws.NewImage(path); img != nil {
+		return draws.NewSpriteFromImage(img)
+	}
+	return draws.NewSpriteFromImage(draw())
+}
+
+func newTextImage(s string, clr color.Color) *ebiten.Image {
+	b := text.BoundString(gosu.Face24, s)
+	img := ebiten.NewImage(b.Dx(), b.Dy())
+	text.Draw(img, s, gosu.Face24, -b.Min.X, -b.Min.Y, clr)
+	return img
+}
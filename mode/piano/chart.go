@@ -1,14 +1,9 @@
 package piano
 
 import (
-	"crypto/md5"
 	"fmt"
-	"os"
-	"path/filepath"
-	"strings"
 
 	"github.com/hndada/gosu"
-	"github.com/hndada/gosu/format/osu"
 )
 
 // Level, ScoreFactors, MD5 will not exported to file.
@@ -19,6 +14,7 @@ type Chart struct {
 	TransPoints []*gosu.TransPoint
 	Notes       []*Note
 	Bars        []*Bar
+	BGMs        []gosu.BGM
 
 	Level        float64
 	ScoreFactors [3]float64
@@ -28,33 +24,51 @@ type Chart struct {
 // Positions of notes and bars at time = 0 are calculated in advance.
 // In every Update(), only current cursor's Position is calculated.
 // Notes and bars are drawn based on the difference between their positions and cursor's.
-func NewChart(cpath string) (c *Chart, err error) {
-	var f any
-	dat, err := os.ReadFile(cpath)
+// Rate and column mods are applied to the chart.
+func NewChart(cpath string, mods gosu.Mods) (c *Chart, err error) {
+	f, err := gosu.LoadChartFile(cpath)
 	if err != nil {
 		return
 	}
-	switch strings.ToLower(filepath.Ext(cpath)) {
-	case ".osu":
-		f, err = osu.Parse(dat)
-		if err != nil {
-			return
-		}
+	if f.Mode != gosu.ModePiano4 && f.Mode != gosu.ModePiano7 {
+		err = fmt.Errorf("not a piano chart")
+		return
 	}
+	f.SetRate(mods.Rate)
 	c = new(Chart)
-	c.ChartHeader = gosu.NewChartHeader(f)
-	c.MD5 = md5.Sum(dat)
-	switch f := f.(type) {
-	case *osu.Format:
-		c.KeyCount = int(f.CircleSize)
+	c.ChartHeader = f.ChartHeader
+	c.MD5 = f.MD5
+	c.KeyCount = f.KeyCount
+	switch f.Scratch {
+	case gosu.ScratchLeft:
+		c.KeyCount |= LeftScratch
+	case gosu.ScratchRight:
+		c.KeyCount |= RightScratch
+	case gosu.ScratchBoth:
+		c.KeyCount |= LeftScratch | RightScratch
+	}
+	if _, ok := FingerMap[c.KeyCount]; !ok {
+		err = fmt.Errorf("invalid key count: %d", f.KeyCount)
+		return
 	}
-	c.TransPoints = gosu.NewTransPoints(f)
+	c.TransPoints = gosu.NewTransPoints(f.TimingPoints)
 	if len(c.TransPoints) == 0 {
 		err = fmt.Errorf("no TransPoints in the chart")
 		return
 	}
-	c.Notes = NewNotes(f, c.KeyCount)
+	c.Notes = NewNotes(f.HitObjects, c.KeyCount)
+	c.Notes = applyColumnMods(c.Notes, c.KeyCount, mods)
+	c.BGMs = f.BGMs
 	c.Bars = NewBars(c.TransPoints, c.Duration())
+	if len(c.Breaks) == 0 {
+		ivs := make([][2]int64, 0, len(c.Notes))
+		for _, n := range c.Notes {
+			if n.Type != Tail {
+				ivs = append(ivs, [2]int64{n.Time, n.Time + n.Duration})
+			}
+		}
+		c.Breaks = gosu.NewBreaks(ivs)
+	}
 
 	// Calculate positions. Position calculation is based on TransPoints.
 	mainBPM, _, _ := c.BPMs()
@@ -93,6 +107,13 @@ func (c Chart) Duration() int64 {
 	return last.Time + last.Duration
 }
 
+func (c Chart) FirstTime() int64 {
+	if len(c.Notes) == 0 {
+		return 0
+	}
+	return c.Notes[0].Time
+}
+
 func (c Chart) NoteCounts() (vs []int) {
 	vs = make([]int, 2)
 	for _, n := range c.Notes {
@@ -113,21 +134,21 @@ func (c Chart) BPMs() (main, min, max float64) {
 	return gosu.BPMs(c.TransPoints, c.Duration())
 }
 func NewChartInfo(cpath string) (info gosu.ChartInfo, err error) {
-	c, err := NewChart(cpath)
+	c, err := NewChart(cpath, gosu.NewMods())
 	if err != nil {
 		return
 	}
 	mode := gosu.ModePiano4
-	if c.KeyCount > 4 {
+	if c.KeyCount&ScratchMask > 4 {
 		mode = gosu.ModePiano7
 	}
 	main, min, max := c.BPMs()
 	info = gosu.ChartInfo{
-		Path: cpath,
-		// Mods:       mods,
+		Path:        cpath,
+		MD5:         c.MD5,
 		ChartHeader: c.ChartHeader,
 		Mode:        mode,
-		SubMode:     c.KeyCount,
+		SubMode:     c.KeyCount & ScratchMask,
 		Level:       c.Level,
 		Duration:    c.Duration(),
 		NoteCounts:  c.NoteCounts(),
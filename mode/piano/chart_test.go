@@ -0,0 +1,51 @@
+package piano
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/hndada/gosu"
+)
+
+// Double play charts have scratch lanes at both ends:
+// player 1's scratch goes leftmost, and player 2's goes rightmost.
+func TestNewChartDoublePlay(t *testing.T) {
+	dir := t.TempDir()
+	for _, tc := range []struct {
+		name     string
+		channels []string
+		keyCount int
+		keys     []int
+	}{
+		{"10k.bms", []string{"16", "11", "15", "21", "25", "26"},
+			12 | LeftScratch | RightScratch, []int{0, 1, 5, 6, 10, 11}},
+		{"14k.bms", []string{"16", "11", "19", "21", "29", "26"},
+			16 | LeftScratch | RightScratch, []int{0, 1, 7, 8, 14, 15}},
+	} {
+		dat := "#BPM 120\n#WAV01 a.wav\n"
+		for _, ch := range tc.channels {
+			dat += "#001" + ch + ":01\n"
+		}
+		path := filepath.Join(dir, tc.name)
+		if err := os.WriteFile(path, []byte(dat), 0644); err != nil {
+			t.Fatal(err)
+		}
+		c, err := NewChart(path, gosu.NewMods())
+		if err != nil {
+			t.Errorf("%s: %s", tc.name, err)
+			continue
+		}
+		if c.KeyCount != tc.keyCount {
+			t.Errorf("%s: key count = %d; want %d", tc.name, c.KeyCount, tc.keyCount)
+		}
+		keys := make([]int, 0, len(c.Notes))
+		for _, n := range c.Notes {
+			keys = append(keys, n.Key)
+		}
+		if !reflect.DeepEqual(keys, tc.keys) {
+			t.Errorf("%s: keys = %v; want %v", tc.name, keys, tc.keys)
+		}
+	}
+}
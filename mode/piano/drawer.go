@@ -1,6 +1,9 @@
 package piano
 
 import (
+	"image/color"
+	"math"
+
 	"github.com/hajimehoshi/ebiten/v2"
 	"github.com/hndada/gosu"
 	"github.com/hndada/gosu/draws"
@@ -67,6 +70,8 @@ type NoteDrawer struct {
 	Farthest *Note
 	Nearest  *Note
 	Sprites  [4]draws.Sprite
+	Hidden   bool
+	FadeIn   bool
 }
 
 // Farthest and Nearest are borders of displaying notes.
@@ -114,10 +119,23 @@ func (d NoteDrawer) Draw(screen *ebiten.Image) {
 		if n.Marked {
 			op.ColorM.ChangeHSV(0, 0.3, 0.3)
 		}
+		op.ColorM.Scale(1, 1, 1, d.alpha(pos))
 		sprite.Draw(screen, op)
 	}
 }
 
+// alpha returns transparency of a note at the position by Hidden and FadeIn.
+func (d NoteDrawer) alpha(pos float64) float64 {
+	a := 1.0
+	if d.Hidden {
+		a *= gosu.Fade(pos, HitPosition*HiddenPositions[0], HitPosition*HiddenPositions[1])
+	}
+	if d.FadeIn {
+		a *= gosu.Fade(pos, HitPosition*FadeInPositions[0], HitPosition*FadeInPositions[1])
+	}
+	return a
+}
+
 // DrawLongBody draws scaled, corresponding sub-image of Body sprite.
 func (d NoteDrawer) DrawLongBody(screen *ebiten.Image, tail *Note) {
 	head := tail.Prev
@@ -134,11 +152,33 @@ func (d NoteDrawer) DrawLongBody(screen *ebiten.Image, tail *Note) {
 	if tail.Marked {
 		op.ColorM.ChangeHSV(0, 0.3, 0.3)
 	}
+	op.ColorM.Scale(1, 1, 1, math.Max(
+		d.alpha(head.Position-d.Cursor), d.alpha(tail.Position-d.Cursor)))
 	ty := head.Position - d.Cursor
 	body.Move(0, -ty)
 	body.Draw(screen, op)
 }
 
+// FlashlightDrawer covers the field except near the hit position.
+type FlashlightDrawer struct {
+	Sprite draws.Sprite
+}
+
+func NewFlashlightDrawer(field draws.Sprite) FlashlightDrawer {
+	h := HitPosition * (1 - FlashlightPosition)
+	src := ebiten.NewImage(int(field.W()), int(h))
+	src.Fill(color.Black)
+	s := draws.NewSpriteFromImage(src)
+	s.SetPosition(FieldPosition, 0, draws.OriginCenterTop)
+	return FlashlightDrawer{Sprite: s}
+}
+func (d FlashlightDrawer) Draw(screen *ebiten.Image) {
+	if !d.Sprite.IsValid() {
+		return
+	}
+	d.Sprite.Draw(screen, nil)
+}
+
 // KeyDrawer draws KeyDownSprite at least for 30ms, KeyUpSprite otherwise.
 // KeyDrawer uses MinCountdown instead of MaxCountdown.
 type KeyDrawer struct {
@@ -175,6 +215,45 @@ func (d KeyDrawer) Draw(screen *ebiten.Image) {
 	}
 }
 
+// LightingDrawer draws LightingSprite for a while after a hit,
+// and LightingLongSprite while holding a long note.
+type LightingDrawer struct {
+	MaxCountdown int
+	Countdowns   []int
+	Sprites      []draws.Sprite
+	LongSprites  []draws.Sprite
+	holding      []bool
+}
+
+func (d *LightingDrawer) Update(hits, holding []bool) {
+	d.holding = holding
+	for k, countdown := range d.Countdowns {
+		if countdown > 0 {
+			d.Countdowns[k]--
+		}
+		if hits[k] {
+			d.Countdowns[k] = d.MaxCountdown
+		}
+	}
+}
+
+// Lightings are added to the color beneath.
+func (d LightingDrawer) Draw(screen *ebiten.Image) {
+	op := &ebiten.DrawImageOptions{}
+	op.CompositeMode = ebiten.CompositeModeLighter
+	for k, countdown := range d.Countdowns {
+		if k < len(d.holding) && d.holding[k] && d.LongSprites[k].IsValid() {
+			d.LongSprites[k].Draw(screen, op)
+			continue
+		}
+		if countdown > 0 && d.Sprites[k].IsValid() {
+			op := *op
+			op.ColorM.Scale(1, 1, 1, float64(countdown)/float64(d.MaxCountdown))
+			d.Sprites[k].Draw(screen, &op)
+		}
+	}
+}
+
 type JudgmentDrawer struct {
 	draws.BaseDrawer
 	Sprites  []draws.Sprite
@@ -0,0 +1,50 @@
+package piano
+
+import (
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/hndada/gosu"
+	"github.com/hndada/gosu/audios"
+	"github.com/hndada/gosu/format/ojn"
+)
+
+// LoadKeysounds registers keysounds of the chart to the sound map.
+// Keysounds are registered with the same name as Sample's.
+// Default hit sounds are loaded from skin for samples not found.
+// Todo: load keysounds asynchronously
+func LoadKeysounds(sm audios.SoundMap, cpath string, c *Chart) error {
+	fpath, _ := gosu.SplitChartPath(cpath)
+	dir := filepath.Dir(fpath)
+	// O2Jam charts have samples in a separate file.
+	if name := c.SampleFilename; strings.ToLower(filepath.Ext(name)) == ".ojm" {
+		dat, err := os.ReadFile(filepath.Join(dir, name))
+		if err != nil {
+			return err
+		}
+		samples, err := ojn.ParseOJM(dat)
+		if err != nil {
+			return err
+		}
+		for _, s := range samples {
+			_ = sm.RegisterBytes(strconv.Itoa(s.ID), s.Data, s.Ext)
+		}
+		return nil
+	}
+
+	var samples []gosu.Sample
+	for _, n := range c.Notes {
+		samples = append(samples, n.Samples...)
+	}
+	for _, bgm := range c.BGMs {
+		samples = append(samples, bgm.Sample)
+	}
+	gosu.LoadSamples(sm, dir, samples, gosu.HitSoundDirs())
+	return nil
+}
+
+// bgmTime returns the current time of BGMs. BGMs go along with the music,
+// which starts at the offset.
+func (s ScenePlay) bgmTime() int64 { return s.Now - s.Offset }
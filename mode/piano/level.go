@@ -46,6 +46,11 @@ func init() {
 		FingerMap[k|LeftScratch] = append([]int{FingerMap[k-1][0] + 1}, FingerMap[k-1]...)
 		FingerMap[k|RightScratch] = append(FingerMap[k-1], FingerMap[k-1][k-2]+1)
 	}
+	for _, k := range []int{5, 7} { // Double play.
+		fs := append([]int{FingerMap[k][0] + 1}, FingerMap[k]...)
+		fs = append(fs, FingerMap[k]...)
+		FingerMap[(2*k+2)|LeftScratch|RightScratch] = append(fs, FingerMap[k][0]+1)
+	}
 }
 
 // Weight is for Tail's variadic weight based on its length.
@@ -0,0 +1,65 @@
+package piano
+
+import (
+	"math"
+
+	"github.com/hndada/gosu"
+)
+
+// Consecutive notes within the time at the same lane are regarded as jacks.
+const midiJackTime = 150
+
+// assignLanes folds pitches onto lanes: lower pitches go to left.
+// When the lane is taken, a note goes to the nearest free lane.
+// Lanes of weak fingers are avoided for jacks.
+// Notes exceeding key count in a chord are dropped.
+func assignLanes(hs []gosu.HitObject, keyCount int) []gosu.HitObject {
+	keys := keyCount & ScratchMask
+	fingers := FingerMap[keyCount]
+	lo, hi := 127, 0
+	for _, h := range hs {
+		if h.Pitch < lo {
+			lo = h.Pitch
+		}
+		if h.Pitch > hi {
+			hi = h.Pitch
+		}
+	}
+	if lo > hi || keys == 0 {
+		return nil
+	}
+	span := hi - lo + 1
+
+	var (
+		used  = make([]bool, keys)
+		ends  = make([]int64, keys) // Time when each lane gets free.
+		lasts = make([]int64, keys) // Time of the last note at each lane.
+	)
+	hs2 := make([]gosu.HitObject, 0, len(hs))
+	for _, h := range hs {
+		target := (h.Pitch - lo) * keys / span
+		key, min := -1, math.Inf(1)
+		for k := 0; k < keys; k++ {
+			if used[k] && ends[k] >= h.Time {
+				continue
+			}
+			cost := math.Abs(float64(k - target))
+			if used[k] && h.Time-lasts[k] < midiJackTime {
+				cost += float64(1 + fingers[k])
+			}
+			cost += 0.1 * float64(fingers[k]) // Strong fingers are preferred.
+			if cost < min {
+				key, min = k, cost
+			}
+		}
+		if key < 0 {
+			continue
+		}
+		h.Column = key
+		used[key] = true
+		lasts[key] = h.Time
+		ends[key] = h.Time + h.Duration
+		hs2 = append(hs2, h)
+	}
+	return hs2
+}
@@ -0,0 +1,146 @@
+package piano
+
+import (
+	"math/rand"
+
+	"github.com/hndada/gosu"
+)
+
+// Notes fade out between the positions with Hidden, and fade in with FadeIn.
+// Flashlight shows notes only below the position.
+// Positions are distances from the hit position, in ratio of HitPosition.
+var (
+	HiddenPositions    = [2]float64{0.3, 0.5}
+	FadeInPositions    = [2]float64{0.7, 0.5}
+	FlashlightPosition = 0.45
+)
+
+// AutoHoldTime is how long Auto holds a key for a normal note.
+const AutoHoldTime = 30
+
+// playKeys returns keys except scratch keys: mods do not move notes
+// from or to scratch lane.
+func playKeys(keyCount int) []int {
+	keys := make([]int, 0, keyCount&ScratchMask)
+	for k := 0; k < keyCount&ScratchMask; k++ {
+		if !isScratch(k, keyCount) {
+			keys = append(keys, k)
+		}
+	}
+	return keys
+}
+
+// applyColumnMods rearranges keys of notes by Mirror and Random.
+// Mirror applies after Random.
+func applyColumnMods(ns []*Note, keyCount int, mods gosu.Mods) []*Note {
+	if !mods.Mirror && mods.Random == gosu.RandomNone {
+		return ns
+	}
+	keys := playKeys(keyCount)
+	perm := make([]int, keyCount&ScratchMask)
+	for k := range perm {
+		perm[k] = k
+	}
+	r := rand.New(rand.NewSource(mods.Seed))
+	switch mods.Random {
+	case gosu.RandomColumn:
+		r.Shuffle(len(keys), func(i, j int) {
+			perm[keys[i]], perm[keys[j]] = perm[keys[j]], perm[keys[i]]
+		})
+	case gosu.RandomSuper:
+		superRandom(ns, keys, r)
+	}
+	if mods.Mirror {
+		mirror := make([]int, len(perm))
+		for k := range mirror {
+			mirror[k] = k
+		}
+		for i, k := range keys {
+			mirror[k] = keys[len(keys)-1-i]
+		}
+		for k := range perm {
+			perm[k] = mirror[perm[k]]
+		}
+	}
+	for _, n := range ns {
+		n.Key = perm[n.Key]
+	}
+	return linkNotes(ns, keyCount)
+}
+
+// superRandom puts each note at a random key which is not occupied
+// by a long note nor by a note at the same time.
+// Tail follows its Head. Notes should be linked in advance.
+func superRandom(ns []*Note, keys []int, r *rand.Rand) {
+	var (
+		busyUntil = make(map[int]int64) // End time of long note at the key.
+		lastTime  = make(map[int]int64)
+		used      = make(map[int]bool)
+	)
+	heads := make(map[*Note]int) // Head to new key.
+	for _, n := range ns {
+		if n.Type == Tail {
+			if k, ok := heads[n.Prev]; ok {
+				n.Key = k
+			}
+			continue
+		}
+		if !inKeys(n.Key, keys) {
+			continue
+		}
+		free := make([]int, 0, len(keys))
+		for _, k := range keys {
+			if used[k] && (busyUntil[k] >= n.Time || lastTime[k] == n.Time) {
+				continue
+			}
+			free = append(free, k)
+		}
+		if len(free) > 0 {
+			n.Key = free[r.Intn(len(free))]
+		}
+		used[n.Key] = true
+		lastTime[n.Key] = n.Time
+		busyUntil[n.Key] = n.Time + n.Duration
+		if n.Type == Head {
+			heads[n] = n.Key
+		}
+	}
+}
+
+func inKeys(k int, keys []int) bool {
+	for _, k2 := range keys {
+		if k == k2 {
+			return true
+		}
+	}
+	return false
+}
+
+// NewAutoListener presses keys at the exact time of notes unless humanized.
+// Key for a normal note is held for a while, but released
+// before the next note at the same key. Rolls are tapped repeatedly.
+// Backspin is done by the secondary key of scratch lane.
+func NewAutoListener(c *Chart, keyCount int, h *gosu.Humanizer, timer *gosu.Timer) func() []bool {
+	ivs := make(gosu.KeyIntervals, inputCount(keyCount))
+	for _, n := range c.Notes {
+		if n.Type == Tail || h.Miss() {
+			continue
+		}
+		start := n.Time + h.Error()
+		end := start + AutoHoldTime
+		if n.Type == Head && n.Roll {
+			for t := start; t < n.Time+n.Duration; t += RollWindow / 4 {
+				ivs.Add(n.Key, t, t+AutoHoldTime)
+			}
+			continue
+		}
+		if n.Type == Head {
+			end = n.Time + h.Release(n.Duration) + h.Error()
+		}
+		ivs.Add(n.Key, start, end)
+		if k2, ok := secondaryKey(n.Key, keyCount); ok && n.Type == Head {
+			ivs.Add(k2, end, end+AutoHoldTime)
+		}
+	}
+	return ivs.Listener(timer)
+}
@@ -4,7 +4,6 @@ import (
 	"sort"
 
 	"github.com/hndada/gosu"
-	"github.com/hndada/gosu/format/osu"
 )
 
 const (
@@ -20,47 +19,57 @@ type Note struct {
 	Type     int
 	Key      int
 	Position float64 // Scaled x or y value.
-	gosu.Sample
-	Marked bool
-	Next   *Note
-	Prev   *Note // For accessing to Head from Tail.
+	Samples  []gosu.Sample
+	Roll     bool // Head and Tail of a roll, which is kept by tapping instead of holding.
+	Marked   bool
+	Next     *Note
+	Prev     *Note // For accessing to Head from Tail.
 }
 
-func NewNote(f any, keyCount int) (ns []*Note) {
-	switch f := f.(type) {
-	case osu.HitObject:
-		n := Note{
-			Time:   int64(f.Time),
-			Type:   Normal,
-			Key:    f.Column(keyCount),
-			Sample: gosu.NewSample(f),
-		}
-		if f.NoteType&osu.ComboMask == osu.HitTypeHoldNote {
-			n.Type = Head
-			n.Duration = int64(f.EndTime) - n.Time
-			n2 := Note{
-				Time: n.Time + n.Duration,
-				Type: Tail,
-				Key:  n.Key,
-				// Tail has no sample sound.
-			}
-			ns = append(ns, &n, &n2)
-		} else {
-			ns = append(ns, &n)
+// NewNote returns a note, or a head and a tail for a long note or a roll.
+func NewNote(h gosu.HitObject) (ns []*Note) {
+	n := Note{
+		Time:    h.Time,
+		Type:    Normal,
+		Key:     h.Column,
+		Samples: h.Samples,
+	}
+	if h.Type == gosu.HitObjectLongNote || h.Type == gosu.HitObjectRoll {
+		n.Type = Head
+		n.Duration = h.Duration
+		n.Roll = h.Type == gosu.HitObjectRoll
+		n2 := Note{
+			Time: n.Time + n.Duration,
+			Type: Tail,
+			Key:  n.Key,
+			Roll: n.Roll,
+			// Tail has no sample sound.
 		}
+		ns = append(ns, &n, &n2)
+	} else {
+		ns = append(ns, &n)
 	}
 	return ns
 }
 
 // Brilliant idea: Make SpeedScale scaled by MainBPM.
-func NewNotes(f any, keyCount int) (ns []*Note) {
-	switch f := f.(type) {
-	case *osu.Format:
-		ns = make([]*Note, 0, len(f.HitObjects)*2)
-		for _, ho := range f.HitObjects {
-			ns = append(ns, NewNote(ho, keyCount)...)
+// Hit objects without lanes are assigned to lanes by their pitches.
+func NewNotes(hs []gosu.HitObject, keyCount int) (ns []*Note) {
+	if len(hs) > 0 && hs[0].Column < 0 {
+		hs = assignLanes(hs, keyCount)
+	}
+	ns = make([]*Note, 0, len(hs)*2)
+	for _, h := range hs {
+		if h.Column < 0 || h.Column >= keyCount&ScratchMask {
+			continue
 		}
+		ns = append(ns, NewNote(h)...)
 	}
+	return linkNotes(ns, keyCount)
+}
+
+// linkNotes sorts notes and links notes at the same key.
+func linkNotes(ns []*Note, keyCount int) []*Note {
 	sort.Slice(ns, func(i, j int) bool {
 		if ns[i].Time == ns[j].Time {
 			return ns[i].Key < ns[j].Key
@@ -71,10 +80,11 @@ func NewNotes(f any, keyCount int) (ns []*Note) {
 	for _, n := range ns {
 		prev := prevs[n.Key]
 		n.Prev = prev
+		n.Next = nil
 		if prev != nil {
 			prev.Next = n
 		}
 		prevs[n.Key] = n
 	}
-	return
+	return ns
 }
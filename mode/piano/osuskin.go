@@ -0,0 +1,242 @@
+package piano
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"math"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hndada/gosu"
+	"github.com/hndada/gosu/draws"
+	"github.com/hndada/gosu/format/osuskin"
+)
+
+// Names of note kinds in osu!mania skin, e.g., mania-note1, mania-noteS.
+var osuNoteKindNames = map[NoteKind]string{One: "1", Two: "2", Mid: "S", Scratch: "S"}
+
+// Judgment images are in order of Judgments: Kool, Cool, Good, Bad, Miss.
+var osuJudgmentNames = [][2]string{
+	{"Hit300g", "mania-hit300g"}, {"Hit300", "mania-hit300"},
+	{"Hit200", "mania-hit200"}, {"Hit100", "mania-hit100"}, {"Hit0", "mania-hit0"},
+}
+
+// skinImages are default images, used when osu! skin lacks an element.
+type skinImages struct {
+	keyUp   *ebiten.Image
+	keyDown *ebiten.Image
+	note    [4]*ebiten.Image
+	head    [4]*ebiten.Image
+	tail    [4]*ebiten.Image
+	body    [4]*ebiten.Image
+}
+
+// osuManiaSection returns the section of 4 keys, or the first section.
+// The section has values shared among key counts.
+func osuManiaSection(f *osuskin.Format) (osuskin.Mania, bool) {
+	if m, ok := f.Mania(4); ok {
+		return m, true
+	}
+	if len(f.Manias) == 0 {
+		return osuskin.Mania{}, false
+	}
+	return f.Manias[0], true
+}
+
+// setOsuSkinSettings should be called before loading sprites,
+// since positions of sprites are based on settings.
+func setOsuSkinSettings(f *osuskin.Format) {
+	const scale = gosu.OsuSkinScale
+	ComboDigitGap = -f.ComboOverlap * scale
+	m, ok := osuManiaSection(f)
+	if !ok {
+		return
+	}
+	HitPosition = m.HitPosition * scale
+	maxPosition = HitPosition + positionMargin
+	minPosition = HitPosition - screenSizeY - positionMargin
+	if m.ScorePosition > 0 {
+		JudgmentPosition = m.ScorePosition * scale
+	}
+	if m.ComboPosition > 0 {
+		ComboPosition = m.ComboPosition * scale
+	}
+}
+
+// loadOsuGeneralSkin replaces combo and judgment sprites which are shared among key counts.
+func loadOsuGeneralSkin(f *osuskin.Format) {
+	if ss, ok := gosu.NewOsuSkinNumberSprites(f.ComboPrefix); ok {
+		for i, s := range ss {
+			s.SetPosition(FieldPosition, ComboPosition, draws.OriginCenterMiddle)
+			GeneralSkin.ComboSprites[i] = s
+		}
+	}
+	m, _ := osuManiaSection(f)
+	for i, names := range osuJudgmentNames {
+		s := gosu.NewOsuSkinSprite(m.Value(names[0], names[1]))
+		if !s.IsValid() {
+			continue
+		}
+		s.SetPosition(FieldPosition, JudgmentPosition, draws.OriginCenterMiddle)
+		GeneralSkin.JudgmentSprites[i] = s
+	}
+}
+
+// loadOsuSkin replaces skins of key counts which have [Mania] section.
+// Widths of notes follow ColumnWidth, while heights keep the ratio of images.
+// Field is centered regardless of ColumnStart.
+// Sprites of default skin are kept when neither image is found.
+func loadOsuSkin(f *osuskin.Format, defaults skinImages) {
+	const scale = gosu.OsuSkinScale
+	for _, m := range f.Manias {
+		skin, ok := Skins[m.Keys]
+		if !ok {
+			continue
+		}
+		var (
+			keyCount = m.Keys
+			ws       = make([]float64, keyCount) // Widths of columns.
+			xs       = make([]float64, keyCount) // Left x-values of columns.
+			wsum     float64
+			noteW    = m.WidthForNoteHeightScale * scale // Smallest width is used when not set.
+		)
+		for k := range ws {
+			ws[k] = math.Ceil(m.ColumnWidth[k] * scale)
+			xs[k] = wsum
+			wsum += ws[k]
+			if k < keyCount-1 {
+				wsum += m.ColumnSpacing[k] * scale
+			}
+			if m.WidthForNoteHeightScale == 0 && (k == 0 || noteW > ws[k]) {
+				noteW = ws[k]
+			}
+		}
+		x0 := FieldPosition - wsum/2
+		for k, kind := range NoteKindsMap[keyCount] {
+			name := osuNoteKindNames[kind]
+			x, w := x0+xs[k], ws[k]
+			if s, ok := newOsuColumnSprite(osuSkinImage(m.Value(fmt.Sprintf("KeyImage%d", k), "mania-key"+name), defaults.keyUp), w); ok {
+				s.SetPosition(x, screenSizeY, draws.OriginLeftBottom)
+				skin.KeyUpSprites[k] = s
+			}
+			if s, ok := newOsuColumnSprite(osuSkinImage(m.Value(fmt.Sprintf("KeyImage%dD", k), "mania-key"+name+"D"), defaults.keyDown), w); ok {
+				s.SetPosition(x, screenSizeY, draws.OriginLeftBottom)
+				skin.KeyDownSprites[k] = s
+			}
+			note := osuSkinImage(m.Value(fmt.Sprintf("NoteImage%d", k), "mania-note"+name), defaults.note[kind])
+			head := osuSkinImage(m.Value(fmt.Sprintf("NoteImage%dH", k), "mania-note"+name+"H"), defaults.head[kind])
+			tail := osuSkinImage(m.Value(fmt.Sprintf("NoteImage%dT", k), "mania-note"+name+"T"), head)
+			body := osuSkinImage(m.Value(fmt.Sprintf("NoteImage%dL", k), "mania-note"+name+"L"), defaults.body[kind])
+			for _, v := range []struct {
+				i      *ebiten.Image
+				sprite *draws.Sprite
+			}{{note, &skin.NoteSprites[k]}, {head, &skin.HeadSprites[k]}, {tail, &skin.TailSprites[k]}} {
+				s, ok := newOsuColumnSprite(v.i, w)
+				if !ok {
+					continue
+				}
+				s.SetScaleXY(1, noteW/w, ebiten.FilterLinear)
+				s.SetPosition(x, HitPosition, draws.OriginLeftBottom)
+				*v.sprite = s
+			}
+			if s, ok := newOsuColumnSprite(body, w); ok {
+				s.SetPosition(x, HitPosition, draws.OriginLeftBottom)
+				skin.BodySprites[k] = s
+			}
+
+			// Lightings are centered at the column. Widths are of columns when not set.
+			for _, v := range []struct {
+				name   string
+				w      float64
+				sprite *draws.Sprite
+			}{
+				{m.Value("LightingN", "lightingN"), m.LightingNWidth[k], &skin.LightingSprites[k]},
+				{m.Value("LightingL", "lightingL"), m.LightingLWidth[k], &skin.LightingLongSprites[k]},
+			} {
+				lw := v.w * scale
+				if lw <= 0 {
+					lw = w
+				}
+				s, ok := newOsuColumnSprite(osuSkinImage(v.name, nil), lw)
+				if !ok {
+					continue
+				}
+				s.SetPosition(x+w/2, m.LightPosition*scale, draws.OriginCenterMiddle)
+				*v.sprite = s
+			}
+		}
+		skin.UpsideDown = m.UpsideDown
+		skin.FieldSprite = newOsuFieldSprite(m, xs, ws, wsum)
+		if i, px := gosu.NewOsuSkinImage(m.Value("StageHint", "mania-stage-hint")); i != nil {
+			s := draws.NewSpriteFromImage(i)
+			s.SetScaleXY(wsum/s.W(), px*scale, ebiten.FilterLinear)
+			s.SetPosition(FieldPosition, HitPosition, draws.OriginCenterMiddle)
+			skin.HintSprite = s
+		} else if m.JudgementLine {
+			src := ebiten.NewImage(int(wsum), int(math.Max(1, scale)))
+			src.Fill(m.Colour("ColourJudgementLine", color.NRGBA{255, 255, 255, 255}))
+			s := draws.NewSpriteFromImage(src)
+			s.SetPosition(FieldPosition, HitPosition, draws.OriginCenterMiddle)
+			skin.HintSprite = s
+		}
+		{
+			src := ebiten.NewImage(int(wsum), int(math.Max(1, m.BarlineHeight*scale)))
+			src.Fill(m.Colour("ColourBarline", color.NRGBA{255, 255, 255, 255}))
+			s := draws.NewSpriteFromImage(src)
+			s.SetPosition(FieldPosition, HitPosition, draws.OriginCenterBottom)
+			skin.BarSprite = s
+		}
+		Skins[keyCount] = skin
+	}
+}
+
+// newOsuColumnSprite returns a sprite scaled to the width.
+// It returns false when there is no image or no width to scale to,
+// since the scale would be NaN or infinite.
+func newOsuColumnSprite(i *ebiten.Image, w float64) (draws.Sprite, bool) {
+	s := draws.NewSpriteFromImage(i)
+	if !s.IsValid() || s.W() == 0 || w <= 0 {
+		return s, false
+	}
+	s.SetScale(w / s.W())
+	return s, true
+}
+
+// osuSkinImage returns the default image when the element is not found.
+func osuSkinImage(name string, def *ebiten.Image) *ebiten.Image {
+	if i, _ := gosu.NewOsuSkinImage(name); i != nil {
+		return i
+	}
+	return def
+}
+
+// Columns are filled with ColourN, and borders are drawn with ColourColumnLine.
+// Alpha values of columns do not exceed FieldDarkness.
+func newOsuFieldSprite(m osuskin.Mania, xs, ws []float64, wsum float64) draws.Sprite {
+	const scale = gosu.OsuSkinScale
+	src := ebiten.NewImage(int(math.Ceil(wsum)), screenSizeY)
+	for k := range ws {
+		c := m.Colour(fmt.Sprintf("Colour%d", k+1), color.NRGBA{0, 0, 0, 255})
+		if max := uint8(255 * FieldDarkness); c.A > max {
+			c.A = max
+		}
+		rect := image.Rect(int(xs[k]), 0, int(xs[k]+ws[k]), screenSizeY)
+		src.SubImage(rect).(*ebiten.Image).Fill(c)
+	}
+	lineColor := m.Colour("ColourColumnLine", color.NRGBA{255, 255, 255, 255})
+	for i, lw := range m.ColumnLineWidth {
+		if lw <= 0 {
+			continue
+		}
+		x := wsum // The right border of the last column.
+		if i < len(xs) {
+			x = xs[i]
+		}
+		w := math.Max(1, lw*scale)
+		rect := image.Rect(int(x-w/2), 0, int(x+w/2+0.5), screenSizeY)
+		src.SubImage(rect).(*ebiten.Image).Fill(lineColor)
+	}
+	s := draws.NewSpriteFromImage(src)
+	s.SetPosition(FieldPosition, 0, draws.OriginCenterTop)
+	return s
+}
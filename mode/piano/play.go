@@ -2,69 +2,103 @@ package piano
 
 import (
 	"fmt"
+	"time"
 
 	"github.com/hajimehoshi/ebiten/v2"
 	"github.com/hajimehoshi/ebiten/v2/ebitenutil"
 	"github.com/hndada/gosu"
+	"github.com/hndada/gosu/audios"
 	"github.com/hndada/gosu/draws"
 	"github.com/hndada/gosu/format/osr"
+	"github.com/hndada/gosu/input"
 )
 
 // ScenePlay: struct, PlayScene: function
 type ScenePlay struct {
 	Chart *Chart
+	Mods  gosu.Mods
 	gosu.Timer
 	gosu.MusicPlayer
 	// gosu.EffectPlayer
+	Keysounds audios.SoundMap
+	BGMCursor int
+	Breaks    []gosu.Break // Intro is included.
 	gosu.KeyLogger
 
 	*gosu.TransPoint
 	SpeedScale float64
 	Cursor     float64
 	Staged     []*Note
+	LastHits   []int64 // Time of the last tap at each key, for rolls.
 	gosu.Scorer
 
 	Skin             // The skin may be applied some custom settings: on/off some sprites
 	BackgroundDrawer gosu.BackgroundDrawer
+	StoryboardDrawer gosu.StoryboardDrawer
+	BreakDrawer      gosu.BreakDrawer
 	StageDrawer      StageDrawer
 	BarDrawer        BarDrawer
 
-	NoteDrawers    []NoteDrawer
-	KeyDrawer      KeyDrawer
-	JudgmentDrawer JudgmentDrawer
+	NoteDrawers      []NoteDrawer
+	FlashlightDrawer FlashlightDrawer
+	KeyDrawer        KeyDrawer
+	LightingDrawer   LightingDrawer
+	JudgmentDrawer   JudgmentDrawer
+	stageImage       *ebiten.Image // Stage is drawn to it first when upside down.
 
 	ScoreDrawer gosu.ScoreDrawer
 	ComboDrawer gosu.NumberDrawer
 	MeterDrawer gosu.MeterDrawer
 }
 
-// Todo: add Mods
-func NewScenePlay(cpath string, rf *osr.Format) (scene gosu.Scene, err error) {
+func NewScenePlay(cpath string, mods gosu.Mods, rf *osr.Format) (scene gosu.Scene, err error) {
 	s := new(ScenePlay)
-	s.Chart, err = NewChart(cpath)
+	if (mods.Random != gosu.RandomNone || mods.Auto) && mods.Seed == 0 {
+		mods.Seed = time.Now().UnixNano()
+	}
+	s.Mods = mods
+	s.Chart, err = NewChart(cpath, mods)
 	if err != nil {
 		return
 	}
 	c := s.Chart
 	gosu.SetTitle(c.ChartHeader)
 	keyCount := c.KeyCount & ScratchMask
-	s.Timer = gosu.NewTimer(c.Duration())
+	s.Timer = gosu.NewTimer(c.Duration(), mods.Rate)
 	// s.SetTicks(c.Duration())
-	if path, ok := c.MusicPath(cpath); ok {
-		s.MusicPlayer, err = gosu.NewMusicPlayer(path, &s.Timer) //(gosu.MusicVolumeHandler, path)
+	path, hasMusic := c.MusicPath(cpath)
+	if hasMusic {
+		s.MusicPlayer, err = gosu.NewMusicPlayer(path, &s.Timer, mods.Nightcore) //(gosu.MusicVolumeHandler, path)
 		if err != nil {
 			return
 		}
 	}
-	// s.EffectPlayer = gosu.NewEffectPlayer(gosu.EffectVolumeHandler)
-	// for _, n := range c.Notes {
-	// 	if path, ok := n.Sample.Path(cpath); ok {
-	// 		_ = s.Effects.Register(path)
-	// 	}
-	// }
-	s.KeyLogger = gosu.NewKeyLogger(KeySettings[keyCount])
-	if rf != nil {
-		s.KeyLogger.FetchPressed = NewReplayListener(rf, keyCount, &s.Timer)
+	s.Keysounds = audios.NewSoundMap(&gosu.EffectVolume)
+	switch {
+	case !hasMusic: // Keysounds are the music itself.
+		s.Keysounds = s.Keysounds.WithRate(mods.Rate, mods.Nightcore)
+	case c.SamplesMatchPlaybackRate:
+		s.Keysounds = s.Keysounds.WithRate(mods.Rate, true)
+	}
+	if err := LoadKeysounds(s.Keysounds, cpath, c); err != nil {
+		fmt.Printf("error at loading keysounds: %s\n", err)
+	}
+	keys, ok := KeySettings[c.KeyCount]
+	if !ok || len(keys) != inputCount(c.KeyCount) {
+		err = fmt.Errorf("no key settings for key count: %d", c.KeyCount)
+		return
+	}
+	s.KeyLogger = gosu.NewKeyLogger(keys)
+	switch {
+	case rf != nil:
+		s.KeyLogger.FetchPressed = NewReplayListener(rf, mods, c.KeyCount, &s.Timer)
+		s.KeyLogger.Actions = nil
+	case mods.Auto:
+		h := gosu.NewHumanizer(mods.Seed)
+		s.KeyLogger.FetchPressed = NewAutoListener(c, c.KeyCount, h, &s.Timer)
+		if !gosu.ExportAutoReplay {
+			s.KeyLogger.Actions = nil
+		}
 	}
 
 	s.TransPoint = c.TransPoints[0]
@@ -73,6 +107,7 @@ func NewScenePlay(cpath string, rf *osr.Format) (scene gosu.Scene, err error) {
 	s.SetSpeed()
 	s.Scorer = gosu.NewScorer(c.ScoreFactors)
 	s.JudgmentCounts = make([]int, len(Judgments))
+	s.Gauge = gosu.NewGauge(mods.Gauge, GaugeTables, mods.NoFail)
 	// s.Result.FlowMarks = make([]float64, 0, c.Duration()/1000)
 	var maxWeight float64
 	for _, n := range c.Notes {
@@ -81,7 +116,9 @@ func NewScenePlay(cpath string, rf *osr.Format) (scene gosu.Scene, err error) {
 	for i := range s.MaxWeights {
 		s.MaxWeights[i] = maxWeight
 	}
+	s.Breaks = gosu.PlayBreaks(c.Breaks, c.FirstTime())
 	s.Staged = make([]*Note, keyCount)
+	s.LastHits = make([]int64, keyCount)
 	for k := range s.Staged {
 		for _, n := range c.Notes {
 			if k == n.Key {
@@ -91,7 +128,7 @@ func NewScenePlay(cpath string, rf *osr.Format) (scene gosu.Scene, err error) {
 		}
 	}
 
-	s.Skin = Skins[keyCount]
+	s.Skin = Skins[c.KeyCount]
 	s.BackgroundDrawer = gosu.BackgroundDrawer{
 		Brightness: &gosu.BackgroundBrightness,
 		Sprite:     gosu.DefaultBackground,
@@ -99,6 +136,8 @@ func NewScenePlay(cpath string, rf *osr.Format) (scene gosu.Scene, err error) {
 	if bg := gosu.NewBackground(c.BackgroundPath(cpath)); bg.IsValid() {
 		s.BackgroundDrawer.Sprite = bg
 	}
+	s.StoryboardDrawer = gosu.NewStoryboardDrawer(cpath)
+	s.BreakDrawer = gosu.NewBreakDrawer(s.Breaks, c.LetterboxInBreaks)
 	s.StageDrawer = StageDrawer{
 		FieldSprite: s.FieldSprite,
 		HintSprite:  s.HintSprite,
@@ -113,8 +152,13 @@ func NewScenePlay(cpath string, rf *osr.Format) (scene gosu.Scene, err error) {
 				s.NoteSprites[k], s.HeadSprites[k],
 				s.TailSprites[k], s.BodySprites[k],
 			},
+			Hidden: mods.Hidden,
+			FadeIn: mods.FadeIn,
 		}
 	}
+	if mods.Flashlight {
+		s.FlashlightDrawer = NewFlashlightDrawer(s.FieldSprite)
+	}
 	s.BarDrawer = BarDrawer{
 		Cursor:   s.Cursor,
 		Farthest: c.Bars[0],
@@ -127,7 +171,16 @@ func NewScenePlay(cpath string, rf *osr.Format) (scene gosu.Scene, err error) {
 		KeyUpSprites:   s.KeyUpSprites,
 		KeyDownSprites: s.KeyDownSprites,
 	}
+	s.LightingDrawer = LightingDrawer{
+		MaxCountdown: gosu.TimeToTick(150),
+		Countdowns:   make([]int, keyCount),
+		Sprites:      s.LightingSprites,
+		LongSprites:  s.LightingLongSprites,
+	}
 	s.JudgmentDrawer = NewJudgmentDrawer()
+	if s.UpsideDown {
+		s.stageImage = ebiten.NewImage(screenSizeX, screenSizeY)
+	}
 	s.ScoreDrawer = gosu.NewScoreDrawer()
 	s.ComboDrawer = gosu.NumberDrawer{
 		BaseDrawer: draws.BaseDrawer{
@@ -142,6 +195,12 @@ func NewScenePlay(cpath string, rf *osr.Format) (scene gosu.Scene, err error) {
 	return s, nil
 }
 
+// PlayKeysounds plays samples with the volume of TransPoint
+// when the sample has no volume of its own.
+func (s ScenePlay) PlayKeysounds(samples ...gosu.Sample) {
+	gosu.PlaySamples(s.Keysounds, samples, s.TransPoint.Volume)
+}
+
 // Farther note has larger position. Tail's Position is always larger than Head's.
 // Need to re-calculate positions when Speed has changed.
 func (s *ScenePlay) SetSpeed() {
@@ -167,7 +226,12 @@ func (s *ScenePlay) Update() any {
 	defer s.Ticker()
 	if s.IsDone() {
 		s.MusicPlayer.Close()
-		return gosu.PlayToResultArgs{Result: s.NewResult(s.Chart.MD5)}
+		if s.Actions != nil && !s.Quit { // Quit plays are not exported.
+			if err := gosu.ExportReplay(s.NewReplay(), s.Chart.ChartHeader); err != nil {
+				fmt.Printf("error at exporting replay: %s\n", err)
+			}
+		}
+		return gosu.PlayToResultArgs{Result: s.NewResult(s.Chart.MD5, s.Mods)}
 	}
 	// if s.Now == 0 {
 	// 	s.MusicPlayer.Play()
@@ -175,27 +239,39 @@ func (s *ScenePlay) Update() any {
 	// if s.Now == 150 {
 	// 	s.MusicPlayer.Player.Seek(time.Duration(s.Now) * time.Millisecond)
 	// }
+	if gosu.Skip(s.Breaks, &s.Timer, &s.MusicPlayer) {
+		s.seekBGMs()
+	}
 	s.MusicPlayer.Update()
 	// fmt.Printf("game: %dms music: %s\n", s.Now, s.MusicPlayer.Player.Current())
+	for ; s.BGMCursor < len(s.Chart.BGMs); s.BGMCursor++ {
+		bgm := s.Chart.BGMs[s.BGMCursor]
+		if bgm.Time > s.bgmTime() {
+			break
+		}
+		s.PlayKeysounds(bgm.Sample)
+	}
 
 	s.LastPressed = s.Pressed
 	s.Pressed = s.FetchPressed()
+	s.Record(s.Now, NewReplayAction(s.Pressed))
 	var worst gosu.Judgment
+	hits := make([]bool, len(s.Staged))
 	for _, n := range s.Staged {
 		if n == nil {
 			continue
 		}
-		// if n.Type != Tail && s.KeyAction(k) == input.Hit {
-		// 	if name := n.Sample.Name; name != "" {
-		// 		vol := n.Sample.Volume
-		// 		if vol == 0 {
-		// 			vol = s.TransPoint.Volume
-		// 		}
-		// 		// Todo: apply effect volume change
-		// 		s.Effects.PlayWithVolume(name, vol)
-		// 	}
-		// }
-		td := n.Time - s.Now // Time difference. A negative value infers late hit
+		a := s.LaneAction(n.Key)
+		if a == input.Hit {
+			s.LastHits[n.Key] = s.Now
+		}
+		if n.Type != Tail && a == input.Hit {
+			s.PlayKeysounds(n.Samples...)
+			s.StoryboardDrawer.TriggerHitSound(n.Samples, s.Mods.MusicTime(s.Now))
+		}
+		// Time difference. A negative value infers late hit.
+		// Judgment windows are of music, hence are scaled by rate.
+		td := s.Mods.MusicTime(n.Time - s.Now)
 		if n.Marked {
 			if n.Type != Tail {
 				return fmt.Errorf("non-Tail note has not flushed")
@@ -205,11 +281,21 @@ func (s *ScenePlay) Update() any {
 			}
 			continue
 		}
-		if j := Verdict(n.Type, s.KeyAction(n.Key), td); j.Window != 0 {
+		j := Verdict(n.Type, a, td)
+		switch {
+		case n.Type == Tail && n.Roll:
+			j = VerdictRoll(s.Mods.MusicTime(s.Now-s.LastHits[n.Key]), td)
+		case n.Type == Tail && isScratch(n.Key, s.Chart.KeyCount):
+			j = VerdictBackspin(a, td)
+		}
+		if j.Window != 0 {
 			s.MarkNote(n, j)
 			if worst.Window < j.Window {
 				worst = j
 			}
+			if j != Miss {
+				hits[n.Key] = true
+			}
 			var kind int = 0
 			if n.Type == Tail {
 				kind = 1
@@ -218,15 +304,24 @@ func (s *ScenePlay) Update() any {
 		}
 	}
 
+	s.StoryboardDrawer.SetFailing(!s.Gauge.Cleared(), s.Mods.MusicTime(s.Now))
+	s.StoryboardDrawer.Update(s.Mods.MusicTime(s.Now))
 	s.BarDrawer.Update(s.Cursor)
 	for i := range s.NoteDrawers {
 		s.NoteDrawers[i].Update(s.Cursor)
 	}
-	s.KeyDrawer.Update(s.LastPressed, s.Pressed)
+	pressed := foldPressed(s.Pressed, s.Chart.KeyCount)
+	s.KeyDrawer.Update(foldPressed(s.LastPressed, s.Chart.KeyCount), pressed)
+	holding := make([]bool, len(s.Staged))
+	for k, n := range s.Staged {
+		holding[k] = pressed[k] && n != nil && n.Type == Tail && !n.Marked
+	}
+	s.LightingDrawer.Update(hits, holding)
 	s.JudgmentDrawer.Update(worst)
 	s.ScoreDrawer.Update(s.Scores[3])
 	s.ComboDrawer.Update(s.Combo)
 	s.MeterDrawer.Update()
+	s.BreakDrawer.Update(s.Now)
 
 	// Changed speed should be applied after positions are calculated.
 	s.UpdateTransPoint()
@@ -238,30 +333,46 @@ func (s *ScenePlay) Update() any {
 }
 func (s ScenePlay) Draw(screen *ebiten.Image) {
 	s.BackgroundDrawer.Draw(screen)
-	s.StageDrawer.Draw(screen)
-	s.BarDrawer.Draw(screen)
+	s.StoryboardDrawer.Draw(screen)
+	stage := screen
+	if s.stageImage != nil {
+		s.stageImage.Clear()
+		stage = s.stageImage
+	}
+	s.StageDrawer.Draw(stage)
+	s.BarDrawer.Draw(stage)
 	for _, d := range s.NoteDrawers {
-		d.Draw(screen)
+		d.Draw(stage)
+	}
+	s.FlashlightDrawer.Draw(stage)
+	s.KeyDrawer.Draw(stage)
+	s.LightingDrawer.Draw(stage)
+	if s.stageImage != nil { // Upside down.
+		op := &ebiten.DrawImageOptions{}
+		op.GeoM.Scale(1, -1)
+		op.GeoM.Translate(0, screenSizeY)
+		screen.DrawImage(s.stageImage, op)
 	}
-	s.KeyDrawer.Draw(screen)
 	s.JudgmentDrawer.Draw(screen)
 	s.ScoreDrawer.Draw(screen)
 	s.ComboDrawer.Draw(screen)
 	s.MeterDrawer.Draw(screen)
+	s.BreakDrawer.Draw(screen)
 	s.DebugPrint(screen)
 }
 
 func (s ScenePlay) DebugPrint(screen *ebiten.Image) {
 	ebitenutil.DebugPrint(screen, fmt.Sprintf(
 		"FPS: %.2f\nTPS: %.2f\nTime: %.3fs/%.0fs\n\n"+
-			"Score: %.0f | %.0f \nFlow: %.0f/100\nCombo: %d\n\n"+
+			"Score: %.0f | %.0f \nFlow: %.0f/100\nCombo: %d\nGauge (%s): %.0f%%\n\n"+
 			"Flow rate: %.2f%%\nAccuracy: %.2f%%\nExtra: %.2f%%\nJudgment counts: %v\n\n"+
 			"Speed scale (Z/X): %.0f (x%.2f)\n(Exposure time: %.fms)\n\n"+
 			"Music volume (Alt+ Left/Right): %.0f%%\nEffect volume (Ctrl+ Left/Right): %.0f%%\n\n"+
-			"Press ESC to select a song.\nPress TAB to pause.\n\n"+
+			"Press ESC or TAB to pause.\nPress Enter to skip intro and breaks.\n\n"+
 			"Offset (Shift+ Left/Right): %dms\n",
 		ebiten.ActualFPS(), ebiten.ActualTPS(), float64(s.Now)/1000, float64(s.Chart.Duration())/1000,
 		s.Scores[gosu.Total], s.ScoreBounds[gosu.Total], s.Flow*100, s.Combo,
+		gosu.GaugeNames[s.Gauge.Type], s.Gauge.Value*100,
 		s.Ratios[0]*100, s.Ratios[1]*100, s.Ratios[2]*100, s.JudgmentCounts,
 		s.SpeedScale*100, s.TransPoint.Speed, ExposureTime(s.CurrentSpeed()),
 		gosu.MusicVolume*100, gosu.EffectVolume*100,
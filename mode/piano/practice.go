@@ -0,0 +1,60 @@
+package piano
+
+import "github.com/hndada/gosu"
+
+// Reset unmarks notes from the time. A long note is judged again
+// only when its head is after the time.
+func (s *ScenePlay) Reset(from int64) {
+	c := s.Chart
+	for _, n := range c.Notes {
+		t := n.Time
+		if n.Type == Tail {
+			t = n.Prev.Time
+		}
+		n.Marked = t < from
+	}
+	for k := range s.Staged {
+		s.Staged[k] = nil
+		for _, n := range c.Notes {
+			if n.Key == k && !n.Marked {
+				s.Staged[k] = n
+				break
+			}
+		}
+	}
+	s.TransPoint = c.TransPoints[0].FetchByTime(s.Now)
+	s.seekBGMs()
+	s.UpdateCursor()
+}
+
+// seekBGMs stops keysounds playing, then moves BGM cursor to now.
+// BGMs still sounding at now are played from the middle,
+// since keysound-only charts have the music in BGMs.
+func (s *ScenePlay) seekBGMs() {
+	s.Keysounds.Stop()
+	bgms := s.Chart.BGMs
+	now := s.bgmTime()
+	for s.BGMCursor = 0; s.BGMCursor < len(bgms) && bgms[s.BGMCursor].Time < now; s.BGMCursor++ {
+		bgm := bgms[s.BGMCursor]
+		gosu.PlaySampleFrom(s.Keysounds, bgm.Sample, s.TransPoint.Volume, now-bgm.Time)
+	}
+}
+
+// Mute closes the music and silences keysounds.
+func (s *ScenePlay) Mute() {
+	s.MusicPlayer.Close()
+	s.MusicPlayer.Player = nil
+	s.Keysounds = s.Keysounds.WithVolume(new(float64))
+}
+
+func (s *ScenePlay) PracticeFields() gosu.PracticeFields {
+	return gosu.PracticeFields{
+		Timer:       &s.Timer,
+		MusicPlayer: &s.MusicPlayer,
+		Scorer:      &s.Scorer,
+		KeyLogger:   &s.KeyLogger,
+		ChartHeader: s.Chart.ChartHeader,
+		TransPoints: s.Chart.TransPoints,
+		Breaks:      s.Breaks,
+	}
+}
@@ -3,13 +3,14 @@ package piano
 import (
 	"github.com/hndada/gosu"
 	"github.com/hndada/gosu/format/osr"
+	"github.com/hndada/gosu/format/osu"
 )
 
 // ReplayListener supposes closure function is called every 1 ms.
 // ReplayListener supposes the first the time of replay data is 0ms and no any inputs.
 // Todo: Make sure to ReplayListener time is independent of Game's update tick
-func NewReplayListener(f *osr.Format, keyCount int, timer *gosu.Timer) func() []bool {
-	actions := append(f.ReplayData, osr.Action{W: 2e9})
+func NewReplayListener(f *osr.Format, mods gosu.Mods, keyCount int, timer *gosu.Timer) func() []bool {
+	actions := append(gosu.ReplayActions(f, mods), osr.Action{W: 2e9})
 	for i := 0; i < 2; i++ {
 		if i < len(actions) {
 			break
@@ -21,12 +22,17 @@ func NewReplayListener(f *osr.Format, keyCount int, timer *gosu.Timer) func() []
 
 	var i int                                    // Index of current replay action
 	var next int64 = actions[0].W + actions[1].W // +1
+	last := timer.Now
 	return func() []bool {
+		if timer.Now < last { // Replay starts over when the timer has moved backward.
+			i, next = 0, actions[0].W+actions[1].W
+		}
+		last = timer.Now
 		for timer.Now >= next { // There might be negative values on actions in a row.
 			i++
 			next += actions[i+1].W
 		}
-		pressed := make([]bool, keyCount)
+		pressed := make([]bool, inputCount(keyCount))
 		var k int
 		for x := int(actions[i].X); x > 0; x /= 2 {
 			if x%2 == 1 {
@@ -37,3 +43,27 @@ func NewReplayListener(f *osr.Format, keyCount int, timer *gosu.Timer) func() []
 		return pressed
 	}
 }
+
+// NewReplayAction is an inverse of ReplayListener.
+func NewReplayAction(pressed []bool) osr.Action {
+	var x int
+	for k, p := range pressed {
+		if p {
+			x |= 1 << k
+		}
+	}
+	return osr.Action{X: float64(x)}
+}
+
+// Kool, Cool, Good, Bad are counted as 320, 300, 200, 100 respectively.
+func (s ScenePlay) NewReplay() *osr.Format {
+	f := gosu.NewReplay(osu.ModeMania, s.NewResult(s.Chart.MD5, s.Mods), s.Actions)
+	counts := s.JudgmentCounts
+	f.NumGeki = int16(counts[0])
+	f.Num300 = int16(counts[1])
+	f.NumKatu = int16(counts[2])
+	f.Num100 = int16(counts[3])
+	f.NumMiss = int16(counts[4])
+	f.FullCombo = counts[4] == 0
+	return f
+}
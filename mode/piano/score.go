@@ -16,6 +16,19 @@ var (
 )
 
 var Judgments = []gosu.Judgment{Kool, Cool, Good, Bad, Miss}
+
+// RollWindow is the longest time a roll is kept without a tap.
+const RollWindow = 500
+
+// Gauge changes by judgment for each gauge type.
+var GaugeTables = [][]float64{
+	{0.01, 0.01, 0.005, -0.02, -0.06},      // Normal
+	{0.012, 0.012, 0.006, -0.016, -0.048},  // Easy
+	{0.0016, 0.0016, 0.0008, -0.05, -0.09}, // Hard
+	{0, 0, 0, 0, -1},                       // Sudden death
+	{0, -1, -1, -1, -1},                    // Perfect only
+}
+
 var JudgmentColors = []color.NRGBA{
 	gosu.ColorKool, gosu.ColorCool, gosu.ColorGood, gosu.ColorBad, gosu.ColorMiss}
 
@@ -39,6 +52,38 @@ func Verdict(noteType int, a input.KeyAction, td int64) gosu.Judgment {
 	return gosu.Judgment{}
 }
 
+// VerdictBackspin judges Tail of a long note at scratch lane, which is a backspin:
+// scratch is held along the body, then spun the other way at the end.
+// Unlike Tail of other lanes, releasing is not judged in range.
+func VerdictBackspin(a input.KeyAction, td int64) gosu.Judgment {
+	switch {
+	case td > Miss.Window:
+		if a == input.Release {
+			return Miss
+		}
+	case td < -Miss.Window:
+		return Miss
+	default: // In range
+		if a == input.Hit {
+			return gosu.Judge(Judgments, td)
+		}
+	}
+	return gosu.Judgment{}
+}
+
+// VerdictRoll judges Tail of a roll, which does not need to be held:
+// the roll is kept by tapping the key again within RollWindow.
+// idle is the time since the last tap at the key.
+func VerdictRoll(idle, td int64) gosu.Judgment {
+	switch {
+	case idle > RollWindow:
+		return Miss
+	case td <= 0:
+		return Kool
+	}
+	return gosu.Judgment{}
+}
+
 // Extra primitive in Piano mode is a count of Kools.
 // Todo: no getting Flow when hands off the long note
 func (s *ScenePlay) MarkNote(n *Note, j gosu.Judgment) {
@@ -57,6 +102,7 @@ func (s *ScenePlay) MarkNote(n *Note, j gosu.Judgment) {
 	for i, j2 := range Judgments {
 		if j.Is(j2) {
 			s.JudgmentCounts[i]++
+			s.Gauge.Update(i)
 			break
 		}
 	}
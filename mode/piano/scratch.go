@@ -0,0 +1,71 @@
+package piano
+
+import "github.com/hndada/gosu/input"
+
+// Scratch lane stands for a turntable. Each scratch lane has two keys,
+// one for each way of spinning, and either key triggers the lane.
+// Secondary keys of scratch lanes follow keys of lanes in pressed.
+
+// scratchKeys returns keys of scratch lanes from the left.
+func scratchKeys(keyCount int) (ks []int) {
+	if keyCount&LeftScratch != 0 {
+		ks = append(ks, 0)
+	}
+	if keyCount&RightScratch != 0 {
+		ks = append(ks, keyCount&ScratchMask-1)
+	}
+	return
+}
+
+func isScratch(k, keyCount int) bool {
+	_, ok := secondaryKey(k, keyCount)
+	return ok
+}
+
+// inputCount returns the number of keys to listen:
+// keys of lanes and secondary keys of scratch lanes.
+func inputCount(keyCount int) int {
+	return keyCount&ScratchMask + len(scratchKeys(keyCount))
+}
+
+// secondaryKey returns an index of secondary key of the scratch lane.
+func secondaryKey(k, keyCount int) (int, bool) {
+	for i, k2 := range scratchKeys(keyCount) {
+		if k == k2 {
+			return keyCount&ScratchMask + i, true
+		}
+	}
+	return 0, false
+}
+
+// foldPressed merges secondary keys into their scratch lanes.
+func foldPressed(pressed []bool, keyCount int) []bool {
+	folded := make([]bool, keyCount&ScratchMask)
+	copy(folded, pressed)
+	for _, k := range scratchKeys(keyCount) {
+		if k2, _ := secondaryKey(k, keyCount); k2 < len(pressed) && pressed[k2] {
+			folded[k] = true
+		}
+	}
+	return folded
+}
+
+// LaneAction returns an action at the lane. Hitting either key of scratch lane
+// is a hit even when the other is held, since it spins the turntable the other way.
+func (s ScenePlay) LaneAction(k int) input.KeyAction {
+	a := s.KeyAction(k)
+	k2, ok := secondaryKey(k, s.Chart.KeyCount)
+	if !ok {
+		return a
+	}
+	a2 := s.KeyAction(k2)
+	switch {
+	case a == input.Hit || a2 == input.Hit:
+		return input.Hit
+	case a == input.Hold || a2 == input.Hold:
+		return input.Hold
+	case a == input.Release || a2 == input.Release:
+		return input.Release
+	}
+	return input.Idle
+}
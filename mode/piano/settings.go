@@ -13,24 +13,41 @@ const (
 
 var SpeedScale float64 = 1.0
 
+// Secondary keys of scratch lanes follow keys of lanes, from the left scratch.
+// Either key of a scratch lane triggers the lane.
 var KeySettings = map[int][]input.Key{
-	4:               {input.KeyD, input.KeyF, input.KeyJ, input.KeyK},
-	5:               {input.KeyD, input.KeyF, input.KeySpace, input.KeyJ, input.KeyK},
-	6:               {input.KeyS, input.KeyD, input.KeyF, input.KeyJ, input.KeyK, input.KeyL},
-	7:               {input.KeyS, input.KeyD, input.KeyF, input.KeySpace, input.KeyJ, input.KeyK, input.KeyL},
-	8 + LeftScratch: {input.KeyA, input.KeyS, input.KeyD, input.KeyF, input.KeySpace, input.KeyJ, input.KeyK, input.KeyL},
-	8:               {input.KeyA, input.KeyS, input.KeyD, input.KeyF, input.KeyJ, input.KeyK, input.KeyL, input.KeySemicolon},
-	9:               {input.KeyA, input.KeyS, input.KeyD, input.KeyF, input.KeySpace, input.KeyJ, input.KeyK, input.KeyL, input.KeySemicolon},
-	10:              {input.KeyA, input.KeyS, input.KeyD, input.KeyF, input.KeyV, input.KeyN, input.KeyJ, input.KeyK, input.KeyL, input.KeySemicolon},
+	4:                {input.KeyD, input.KeyF, input.KeyJ, input.KeyK},
+	5:                {input.KeyD, input.KeyF, input.KeySpace, input.KeyJ, input.KeyK},
+	6:                {input.KeyS, input.KeyD, input.KeyF, input.KeyJ, input.KeyK, input.KeyL},
+	7:                {input.KeyS, input.KeyD, input.KeyF, input.KeySpace, input.KeyJ, input.KeyK, input.KeyL},
+	8:                {input.KeyA, input.KeyS, input.KeyD, input.KeyF, input.KeyJ, input.KeyK, input.KeyL, input.KeySemicolon},
+	9:                {input.KeyA, input.KeyS, input.KeyD, input.KeyF, input.KeySpace, input.KeyJ, input.KeyK, input.KeyL, input.KeySemicolon},
+	10:               {input.KeyA, input.KeyS, input.KeyD, input.KeyF, input.KeyV, input.KeyN, input.KeyJ, input.KeyK, input.KeyL, input.KeySemicolon},
+	5 + RightScratch: {input.KeyD, input.KeyF, input.KeyJ, input.KeyK, input.KeyL, input.KeySemicolon},
+	6 + LeftScratch:  {input.KeyA, input.KeyD, input.KeyF, input.KeySpace, input.KeyJ, input.KeyK, input.KeyShiftLeft},
+	8 + LeftScratch:  {input.KeyA, input.KeyS, input.KeyD, input.KeyF, input.KeySpace, input.KeyJ, input.KeyK, input.KeyL, input.KeyShiftLeft},
+	8 + RightScratch: {input.KeyS, input.KeyD, input.KeyF, input.KeySpace, input.KeyJ, input.KeyK, input.KeyL, input.KeySemicolon, input.KeyQuote},
+	12 + LeftScratch + RightScratch: {input.KeyShiftLeft,
+		input.KeyZ, input.KeyS, input.KeyX, input.KeyD, input.KeyC,
+		input.KeyM, input.KeyK, input.KeyComma, input.KeyL, input.KeyPeriod,
+		input.KeyShiftRight, input.KeyControlLeft, input.KeyControlRight},
+	16 + LeftScratch + RightScratch: {input.KeyShiftLeft,
+		input.KeyZ, input.KeyS, input.KeyX, input.KeyD, input.KeyC, input.KeyF, input.KeyV,
+		input.KeyN, input.KeyJ, input.KeyM, input.KeyK, input.KeyComma, input.KeyL, input.KeyPeriod,
+		input.KeyShiftRight, input.KeyControlLeft, input.KeyControlRight},
 }
-var NoteWidthsMap = map[int][3]float64{
-	4:  {0.065, 0.065, 0.065},
-	5:  {0.065, 0.065, 0.065},
-	6:  {0.065, 0.065, 0.065},
-	7:  {0.06, 0.06, 0.06},
-	8:  {0.06, 0.06, 0.06},
-	9:  {0.06, 0.06, 0.06},
-	10: {0.06, 0.06, 0.06},
+
+// Widths are of One, Two, Mid and Scratch in order.
+var NoteWidthsMap = map[int][4]float64{
+	4:  {0.065, 0.065, 0.065, 0.09},
+	5:  {0.065, 0.065, 0.065, 0.09},
+	6:  {0.065, 0.065, 0.065, 0.09},
+	7:  {0.06, 0.06, 0.06, 0.09},
+	8:  {0.06, 0.06, 0.06, 0.09},
+	9:  {0.06, 0.06, 0.06, 0.09},
+	10: {0.06, 0.06, 0.06, 0.09},
+	12: {0.04, 0.04, 0.04, 0.06},
+	16: {0.035, 0.035, 0.035, 0.055},
 }
 
 // Todo: generalize setting loading function
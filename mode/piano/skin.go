@@ -19,7 +19,8 @@ const (
 	One NoteKind = iota
 	Two
 	Mid
-	Tip = Mid
+	Scratch // Scratch lane is wider than others.
+	Tip     = Mid
 )
 
 var NoteKindsMap = map[int][]NoteKind{
@@ -38,6 +39,7 @@ var NoteKindsMap = map[int][]NoteKind{
 
 // LeftScratch and RightScratch are bits for indicating scratch mode.
 // For example, when key count is 40 = 32 + 8, it is 8-key with left scratch.
+// Double play has both: 112 = 32 + 64 + 16 is 14-key with two scratches.
 const (
 	LeftScratch  = 32
 	RightScratch = 64
@@ -46,8 +48,13 @@ const (
 
 func init() { // I'm proud of the following code.
 	for k := 2; k <= 8; k++ {
-		NoteKindsMap[k|LeftScratch] = append([]NoteKind{Tip}, NoteKindsMap[k-1]...)
-		NoteKindsMap[k|RightScratch] = append(NoteKindsMap[k-1], Tip)
+		NoteKindsMap[k|LeftScratch] = append([]NoteKind{Scratch}, NoteKindsMap[k-1]...)
+		NoteKindsMap[k|RightScratch] = append(NoteKindsMap[k-1], Scratch)
+	}
+	for _, k := range []int{5, 7} { // Double play.
+		kinds := append([]NoteKind{Scratch}, NoteKindsMap[k]...)
+		kinds = append(kinds, NoteKindsMap[k]...)
+		NoteKindsMap[(2*k+2)|LeftScratch|RightScratch] = append(kinds, Scratch)
 	}
 }
 
@@ -76,11 +83,21 @@ type Skin struct {
 	FieldSprite draws.Sprite
 	HintSprite  draws.Sprite
 	BarSprite   draws.Sprite // Seperator of each bar (aka measure)
+
+	// Lightings are drawn at hit and while holding a long note.
+	// Default skin has no lightings.
+	LightingSprites     []draws.Sprite
+	LightingLongSprites []draws.Sprite
+	UpsideDown          bool // Stage is flipped vertically.
 }
 
 var Skins = make(map[int]Skin)
 
 func LoadSkin() {
+	osk, hasOsuSkin := gosu.LoadOsuSkin()
+	if hasOsuSkin {
+		setOsuSkinSettings(osk)
+	}
 	// Sprites that are independent of key count.
 	for i := 0; i < 10; i++ {
 		s := draws.NewSprite(fmt.Sprintf("skin/combo/%d.png", i))
@@ -95,6 +112,9 @@ func LoadSkin() {
 		s.SetPosition(FieldPosition, JudgmentPosition, draws.OriginCenterMiddle)
 		GeneralSkin.JudgmentSprites[i] = s
 	}
+	if hasOsuSkin {
+		loadOsuGeneralSkin(osk)
+	}
 
 	// Following sprites are dependent of key count.
 	// Todo: animated sprite support. Starting with [4][]*ebiten.Image will help.
@@ -111,6 +131,7 @@ func LoadSkin() {
 	keyUpImage = draws.NewImage("skin/piano/key/up.png") // Todo: combine with declaration?
 	keyDownImage = draws.NewImage("skin/piano/key/down.png")
 	hintImage = draws.NewImage("skin/piano/hint.png")
+	// Scratch uses 3rd note images, stretched wider.
 	// Todo: 4th note image. 1st note with custom color settings.
 	for i, kind := range []int{1, 2, 3, 3} {
 		noteImages[i] = draws.NewImage(fmt.Sprintf("skin/piano/note/note/%d.png", kind))
@@ -120,10 +141,12 @@ func LoadSkin() {
 		// bodyImages[i] = draws.NewImageSrc(fmt.Sprintf("skin/piano/note/body/%d.png", kind))
 	}
 
-	// Todo: Key count 1, 2, 3 and with scratch
-	for keyCount := 4; keyCount <= 10; keyCount++ {
-		noteKinds := NoteKindsMap[keyCount]
-		noteWidths := NoteWidthsMap[keyCount]
+	// Todo: Key count 1, 2, 3
+	for keyCount, noteKinds := range NoteKindsMap {
+		noteWidths, ok := NoteWidthsMap[keyCount&ScratchMask]
+		if !ok {
+			continue
+		}
 		skin := Skin{
 			ScoreSprites: gosu.ScoreSprites,
 			SignSprites:  gosu.SignSprites,
@@ -138,6 +161,9 @@ func LoadSkin() {
 			TailSprites:    make([]draws.Sprite, keyCount&ScratchMask),
 			BodySprites:    make([]draws.Sprite, keyCount&ScratchMask),
 			// BodySprites:    make([][]draws.Sprite, keyCount&ScratchMask),
+
+			LightingSprites:     make([]draws.Sprite, keyCount&ScratchMask),
+			LightingLongSprites: make([]draws.Sprite, keyCount&ScratchMask),
 		}
 		// KeyUp and KeyDown are drawn below Hint, which bottom is along with HitPosition.
 		// Each w should be integer, since it is a width of independent sprite.
@@ -221,6 +247,10 @@ func LoadSkin() {
 		}
 		Skins[keyCount] = skin
 	}
+	if hasOsuSkin {
+		loadOsuSkin(osk, skinImages{keyUpImage, keyDownImage,
+			noteImages, headImages, tailImages, bodyImages})
+	}
 }
 
 // // Draw max length of long note body sprite in advance.
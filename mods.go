@@ -0,0 +1,190 @@
+package gosu
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/hndada/gosu/format/osr"
+)
+
+const (
+	RandomNone   = iota
+	RandomColumn // Columns are shuffled.
+	RandomSuper  // Each note goes to a random column. aka S-Random.
+)
+
+// Mods are modifiers of a play. Mods are set at SceneSelect and passed
+// to NewScenePlay. Each mode reads mods of its own.
+// Rate scales every time of the chart: Duration, level and
+// judgment windows go along with the music.
+// Music keeps its pitch at the rate unless Nightcore is set.
+type Mods struct {
+	Rate       float64 // Half Time is 0.75, Double Time is 1.5.
+	Nightcore  bool    // Pitch changes along with the rate.
+	Mirror     bool    // Piano only.
+	Random     int     // Piano only.
+	Seed       int64   // For Random and humanized Auto. Set at the start of play.
+	NoFail     bool
+	Gauge      int
+	Auto       bool
+	Hidden     bool // Notes fade out as approaching the hit position.
+	FadeIn     bool // Piano only. Notes fade in near the hit position.
+	Flashlight bool // Piano only.
+}
+
+func NewMods() Mods { return Mods{Rate: 1} }
+
+// NewModsFromReplay retrieves mods from osu! replay.
+// Rate is either of Half Time and Double Time's,
+// unless the replay has the exact rate at the seed action.
+func NewModsFromReplay(f *osr.Format) Mods {
+	bits := f.ModsBits
+	m := NewMods()
+	switch {
+	case bits&(osr.ModDoubleTime|osr.ModNightcore) != 0:
+		m.Rate = 1.5
+	case bits&osr.ModHalfTime != 0:
+		m.Rate = 0.75
+	}
+	m.Nightcore = bits&osr.ModNightcore != 0
+	m.Mirror = bits&osr.ModMirror != 0
+	if bits&osr.ModRandom != 0 {
+		m.Random = RandomColumn
+	}
+	m.NoFail = bits&osr.ModNoFail != 0
+	switch {
+	case bits&osr.ModPerfect != 0:
+		m.Gauge = GaugePerfectOnly
+	case bits&osr.ModSuddenDeath != 0:
+		m.Gauge = GaugeSuddenDeath
+	case bits&osr.ModEasy != 0:
+		m.Gauge = GaugeEasy
+	}
+	m.Auto = bits&osr.ModAutoplay != 0
+	m.Hidden = bits&osr.ModHidden != 0
+	m.FadeIn = bits&osr.ModFadeIn != 0
+	m.Flashlight = bits&osr.ModFlashlight != 0
+	if a, ok := f.SeedAction(); ok {
+		m.Seed = a.Z
+		m.setSeedAction(a)
+	}
+	return m
+}
+
+// Mods which mods bits cannot hold are written at the seed action:
+// exact rate at X, and random kind and gauge type at Y.
+// Y is Random + seedActionGaugeUnit * Gauge.
+const seedActionGaugeUnit = 16
+
+func (m Mods) SeedAction() osr.Action {
+	return osr.Action{
+		W: osr.SeedActionTime,
+		X: m.Rate,
+		Y: float64(m.Random + seedActionGaugeUnit*m.Gauge),
+		Z: m.Seed,
+	}
+}
+
+// setSeedAction overrides mods read from bits.
+// Replays from osu! have zero at X, which leaves mods as they are.
+func (m *Mods) setSeedAction(a osr.Action) {
+	if a.X <= 0 {
+		return
+	}
+	m.Rate = a.X
+	y := int(a.Y)
+	if r := y % seedActionGaugeUnit; r >= RandomNone && r <= RandomSuper {
+		m.Random = r
+	}
+	if g := y / seedActionGaugeUnit; g >= GaugeNormal && g <= GaugePerfectOnly {
+		m.Gauge = g
+	}
+}
+
+// Bits returns mods bits of osu! replay.
+// Rates other than 1 are written as Half Time or Double Time,
+// and S-Random is written as Random. Hard gauge has no bit;
+// exact values are written at the seed action.
+func (m Mods) Bits() (bits int32) {
+	switch {
+	case m.Rate > 1:
+		bits |= osr.ModDoubleTime
+	case m.Rate < 1:
+		bits |= osr.ModHalfTime
+	}
+	for _, mod := range []struct {
+		on  bool
+		bit int32
+	}{
+		{m.Nightcore && m.Rate > 1, osr.ModNightcore},
+		{m.Mirror, osr.ModMirror},
+		{m.Random != RandomNone, osr.ModRandom},
+		{m.NoFail, osr.ModNoFail},
+		{m.Gauge == GaugeEasy, osr.ModEasy},
+		{m.Gauge == GaugeSuddenDeath, osr.ModSuddenDeath},
+		{m.Gauge == GaugePerfectOnly, osr.ModSuddenDeath | osr.ModPerfect},
+		{m.Auto, osr.ModAutoplay},
+		{m.Hidden, osr.ModHidden},
+		{m.FadeIn, osr.ModFadeIn},
+		{m.Flashlight, osr.ModFlashlight},
+	} {
+		if mod.on {
+			bits |= mod.bit
+		}
+	}
+	return
+}
+
+// MusicTime converts time of play to time of music.
+// Times in chart are of play; judgment windows are of music.
+func (m Mods) MusicTime(t int64) int64 {
+	if m.Rate == 0 {
+		return t
+	}
+	return int64(float64(t) * m.Rate)
+}
+
+func (m Mods) String() string {
+	var ss []string
+	if m.Rate != 0 && m.Rate != 1 {
+		ss = append(ss, fmt.Sprintf("x%.2f", m.Rate))
+		if m.Nightcore {
+			ss = append(ss, "Nightcore")
+		}
+	}
+	for _, mod := range []struct {
+		on   bool
+		name string
+	}{
+		{m.Mirror, "Mirror"},
+		{m.Random == RandomColumn, "Random"},
+		{m.Random == RandomSuper, "S-Random"},
+		{m.NoFail, "NoFail"},
+		{m.Gauge != GaugeNormal, GaugeNames[m.Gauge] + " gauge"},
+		{m.Auto, "Auto"},
+		{m.Hidden, "Hidden"},
+		{m.FadeIn, "FadeIn"},
+		{m.Flashlight, "Flashlight"},
+	} {
+		if mod.on {
+			ss = append(ss, mod.name)
+		}
+	}
+	if len(ss) == 0 {
+		return "None"
+	}
+	return strings.Join(ss, ", ")
+}
+
+// Fade returns an alpha value for Hidden and FadeIn.
+// Alpha goes 0 to 1 linearly while x goes from 'from' to 'to'.
+func Fade(x, from, to float64) float64 {
+	a := (x - from) / (to - from)
+	switch {
+	case a < 0:
+		return 0
+	case a > 1:
+		return 1
+	}
+	return a
+}
@@ -0,0 +1,77 @@
+package gosu
+
+import (
+	"fmt"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/ebitenutil"
+)
+
+// Opponent is a play which goes along with another play silently.
+type Opponent interface {
+	Practicable
+	Mute() // Closes the music and silences sounds.
+}
+
+// SceneOpponent runs Auto on the same chart along with the play,
+// as an opponent at practice. Auto is humanized with Auto settings.
+// The opponent shares the time with the play, and is reset along with it.
+type SceneOpponent struct {
+	play     Practicable
+	fields   PracticeFields
+	opponent Opponent
+	ofields  PracticeFields
+}
+
+func NewSceneOpponent(scene, opponent Scene) (Scene, error) {
+	play, ok := scene.(Practicable)
+	if !ok {
+		return nil, fmt.Errorf("opponent not supported: %T", scene)
+	}
+	o, ok := opponent.(Opponent)
+	if !ok {
+		return nil, fmt.Errorf("opponent not supported: %T", opponent)
+	}
+	o.Mute()
+	s := &SceneOpponent{
+		play:     play,
+		fields:   play.PracticeFields(),
+		opponent: o,
+		ofields:  o.PracticeFields(),
+	}
+	s.ofields.KeyLogger.Actions = nil
+	s.ofields.Scorer.Gauge.NoFail = true
+	return s, nil
+}
+
+// Update updates the opponent at the same time with the play.
+// Result of the opponent is discarded.
+func (s *SceneOpponent) Update() any {
+	if !s.fields.Timer.Pause {
+		s.syncTimer()
+		s.opponent.Update()
+	}
+	return s.play.Update()
+}
+
+func (s *SceneOpponent) syncTimer() {
+	t := s.ofields.Timer
+	t.Tick = s.fields.Timer.Tick
+	t.Now = s.fields.Timer.Now
+}
+
+// Reset resets both plays, since it is called at seeking and retrying.
+func (s *SceneOpponent) Reset(from int64) {
+	s.play.Reset(from)
+	s.syncTimer()
+	s.opponent.Reset(from)
+	s.ofields.Scorer.Reset()
+}
+func (s SceneOpponent) PracticeFields() PracticeFields { return s.fields }
+
+func (s SceneOpponent) Draw(screen *ebiten.Image) {
+	s.play.Draw(screen)
+	sc := s.ofields.Scorer
+	ebitenutil.DebugPrintAt(screen, fmt.Sprintf("Opponent\nScore: %.0f\nAccuracy: %.2f%%\nCombo: %d",
+		sc.Scores[Total], sc.Ratios[Acc]*100, sc.Combo), 0, screenSizeY-70)
+}
@@ -0,0 +1,92 @@
+package gosu
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hndada/gosu/draws"
+	"github.com/hndada/gosu/format/osuskin"
+)
+
+// OsuSkinScale converts osu! pixels, based on 640x480, to screen pixels.
+const OsuSkinScale = float64(screenSizeY) / 480
+
+// LoadOsuSkin parses skin.ini in OsuSkinRoot.
+// Default values are used when skin.ini is missing.
+func LoadOsuSkin() (*osuskin.Format, bool) {
+	if OsuSkinRoot == "" {
+		return nil, false
+	}
+	if _, err := os.Stat(OsuSkinRoot); err != nil {
+		fmt.Println(err)
+		return nil, false
+	}
+	dat, _ := os.ReadFile(filepath.Join(OsuSkinRoot, "skin.ini"))
+	f, err := osuskin.Parse(dat)
+	if err != nil {
+		fmt.Printf("error at parsing skin.ini: %s\n", err)
+	}
+	return f, true
+}
+
+// NewOsuSkinImage returns an image of the element in OsuSkinRoot,
+// such as "mania-note1". Name may contain a folder.
+// High resolution image (@2x) is preferred, and its scale is 0.5.
+// It returns nil when the image is not found.
+func NewOsuSkinImage(name string) (*ebiten.Image, float64) {
+	name = filepath.FromSlash(strings.ReplaceAll(name, `\`, "/"))
+	base := filepath.Join(OsuSkinRoot, strings.TrimSuffix(name, ".png"))
+	if i := draws.NewImage(base + "@2x.png"); i != nil {
+		return i, 0.5
+	}
+	if i := draws.NewImage(base + ".png"); i != nil {
+		return i, 1
+	}
+	return nil, 0
+}
+
+// NewOsuSkinSprite returns a sprite which has the same size as in osu!.
+func NewOsuSkinSprite(name string) draws.Sprite {
+	i, scale := NewOsuSkinImage(name)
+	s := draws.NewSpriteFromImage(i)
+	if i != nil {
+		s.SetScale(scale * OsuSkinScale)
+	}
+	return s
+}
+
+// NewOsuSkinNumberSprites returns sprites of digits, e.g., "score-0" to "score-9".
+// It returns false when any of digits is missing.
+func NewOsuSkinNumberSprites(prefix string) (ss [10]draws.Sprite, ok bool) {
+	for i := range ss {
+		ss[i] = NewOsuSkinSprite(fmt.Sprintf("%s-%d", prefix, i))
+		if !ss[i].IsValid() {
+			return ss, false
+		}
+	}
+	return ss, true
+}
+
+// Score digits keep heights of default sprites.
+func loadOsuScoreSprites(prefix string) {
+	ss, ok := NewOsuSkinNumberSprites(prefix)
+	if !ok {
+		return
+	}
+	h := ScoreSprites[0].H()
+	if h == 0 { // Default sprites are missing.
+		h = ss[0].H()
+	}
+	for i, s := range ss {
+		s.SetScale(h / ss[0].H())
+		if i == 0 {
+			s.SetPosition(screenSizeX, 0, draws.OriginRightTop)
+		} else {
+			s.SetPosition(screenSizeX, h-s.H(), draws.OriginRightTop)
+		}
+		ScoreSprites[i] = s
+	}
+}
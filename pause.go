@@ -0,0 +1,212 @@
+package gosu
+
+import (
+	"fmt"
+	"image/color"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/inpututil"
+	"github.com/hajimehoshi/ebiten/v2/text"
+	"github.com/hndada/gosu/ctrl"
+)
+
+const (
+	PauseContinue = iota
+	PauseRetry
+	PauseQuit
+)
+
+var pauseMenuNames = []string{"Continue", "Retry", "Quit"}
+
+// ScenePause wraps a play scene with a pause menu.
+// Retry starts the chart over without loading it again.
+// The play also stops here when the gauge has failed.
+type ScenePause struct {
+	play   Practicable
+	fields PracticeFields
+
+	Paused           bool
+	PausedTime       int64
+	Cursor           int
+	CursorKeyHandler ctrl.KeyHandler
+	Countdown        int // Remaining ticks until resuming.
+	Pauses           []int64
+	PauseAbuses      int
+	FailCountdown    int // Remaining ticks of fail animation.
+
+	dim *ebiten.Image
+}
+
+func NewScenePause(scene Scene) (Scene, error) {
+	play, ok := scene.(Practicable)
+	if !ok {
+		return nil, fmt.Errorf("pause not supported: %T", scene)
+	}
+	s := &ScenePause{
+		play:   play,
+		fields: play.PracticeFields(),
+	}
+	s.CursorKeyHandler = NewCursorKeyHandler(&s.Cursor, len(pauseMenuNames))
+	s.dim = ebiten.NewImage(screenSizeX, screenSizeY)
+	s.dim.Fill(color.NRGBA{0, 0, 0, 128})
+	return s, nil
+}
+
+func (s *ScenePause) Update() any {
+	switch {
+	case s.FailCountdown > 0:
+		return s.updateFail()
+	case s.Countdown > 0:
+		s.Countdown--
+		if s.Countdown == 0 {
+			s.fields.Timer.Pause = false
+		}
+		return nil
+	case s.Paused:
+		return s.updateMenu()
+	}
+	if inpututil.IsKeyJustPressed(ebiten.KeyEscape) || inpututil.IsKeyJustPressed(ebiten.KeyTab) {
+		s.Pause()
+		return nil
+	}
+	args := s.play.Update()
+	if s.fields.Scorer.Gauge.Stopped() && args == nil {
+		s.fields.Timer.Pause = true
+		s.FailCountdown = TimeToTick(FailDuration)
+	}
+	return s.result(args)
+}
+
+// updateFail fades out the music, then quits the play.
+func (s *ScenePause) updateFail() any {
+	s.FailCountdown--
+	if p := s.fields.MusicPlayer.Player; p != nil {
+		p.SetVolume(MusicVolume * s.failRate())
+	}
+	if s.FailCountdown > 0 {
+		return nil
+	}
+	s.fields.Timer.Quit = true
+	return s.result(s.play.Update())
+}
+
+// failRate goes from 1 to 0 along with fail animation.
+func (s ScenePause) failRate() float64 {
+	max := TimeToTick(FailDuration)
+	if max == 0 {
+		return 0
+	}
+	return float64(s.FailCountdown) / float64(max)
+}
+
+func (s *ScenePause) updateMenu() any {
+	s.CursorKeyHandler.Update()
+	if inpututil.IsKeyJustPressed(ebiten.KeyEscape) || inpututil.IsKeyJustPressed(ebiten.KeyTab) {
+		s.Resume()
+		return nil
+	}
+	if !inpututil.IsKeyJustPressed(ebiten.KeyEnter) && !inpututil.IsKeyJustPressed(ebiten.KeyNumpadEnter) {
+		return nil
+	}
+	switch s.Cursor {
+	case PauseContinue:
+		s.Resume()
+	case PauseRetry:
+		s.Retry()
+	case PauseQuit:
+		s.fields.Timer.Quit = true
+		return s.result(s.play.Update())
+	}
+	return nil
+}
+
+// result records pauses to the result of the play.
+func (s ScenePause) result(args any) any {
+	if args, ok := args.(PlayToResultArgs); ok {
+		args.Pauses = s.Pauses
+		args.PauseAbuses = s.PauseAbuses
+		return args
+	}
+	return args
+}
+
+// Pause stops the timer and the music.
+// Pauses out of intro and breaks are counted as abuses.
+func (s *ScenePause) Pause() {
+	now := s.fields.Timer.Now
+	s.Paused = true
+	s.PausedTime = now
+	s.Cursor = PauseContinue
+	s.Pauses = append(s.Pauses, now)
+	abuse := true
+	for _, b := range s.fields.Breaks {
+		if now >= b.StartTime && now < b.EndTime {
+			abuse = false
+			break
+		}
+	}
+	if abuse {
+		s.PauseAbuses++
+	}
+	s.fields.Timer.Pause = true
+	s.fields.MusicPlayer.Update()
+}
+
+// Resume rewinds the lead-in and counts down before playing.
+// Judged notes in the lead-in are not judged again.
+func (s *ScenePause) Resume() {
+	s.Paused = false
+	t := s.PausedTime - ResumeLeadIn
+	if t < -Wait {
+		t = -Wait
+	}
+	s.fields.Timer.SeekTo(t)
+	s.fields.MusicPlayer.SeekTo(t)
+	s.Countdown = TimeToTick(ResumeCountdown)
+	if s.Countdown == 0 {
+		s.fields.Timer.Pause = false
+	}
+}
+
+// Retry starts the play over with the chart already loaded.
+func (s *ScenePause) Retry() {
+	s.Paused = false
+	s.Pauses = nil
+	s.PauseAbuses = 0
+	s.fields.Timer.SeekTo(-Wait)
+	s.fields.MusicPlayer.SeekTo(-Wait)
+	s.fields.Timer.Pause = false
+	s.play.Reset(-Wait)
+	s.fields.Scorer.Reset()
+	s.fields.KeyLogger.Reset()
+}
+
+// Reset and PracticeFields pass through to the play,
+// so that practice can wrap the pause menu.
+func (s *ScenePause) Reset(from int64)              { s.play.Reset(from) }
+func (s ScenePause) PracticeFields() PracticeFields { return s.fields }
+
+func (s ScenePause) Draw(screen *ebiten.Image) {
+	s.play.Draw(screen)
+	switch {
+	case s.FailCountdown > 0:
+		op := &ebiten.DrawImageOptions{}
+		op.ColorM.Scale(1, 1, 1, 2*(1-s.failRate()))
+		screen.DrawImage(s.dim, op)
+		text.Draw(screen, "FAILED", Face24, screenSizeX/2-50, screenSizeY/2, color.NRGBA{235, 69, 44, 255})
+	case s.Countdown > 0:
+		screen.DrawImage(s.dim, nil)
+		sec := (TickToTime(s.Countdown) + 999) / 1000
+		text.Draw(screen, fmt.Sprint(sec), Face24, screenSizeX/2, screenSizeY/2, color.White)
+	case s.Paused:
+		screen.DrawImage(s.dim, nil)
+		for i, name := range pauseMenuNames {
+			clr := color.NRGBA{128, 128, 128, 255}
+			if i == s.Cursor {
+				clr = color.NRGBA{255, 255, 255, 255}
+			}
+			y := screenSizeY/2 + (i-1)*40
+			text.Draw(screen, name, Face24, screenSizeX/2-50, y, clr)
+		}
+	}
+}
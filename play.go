@@ -2,12 +2,13 @@ package gosu
 
 import (
 	"fmt"
+	"math"
 	"time"
 
 	"github.com/hajimehoshi/ebiten/v2"
 	"github.com/hajimehoshi/ebiten/v2/audio"
-	"github.com/hajimehoshi/ebiten/v2/inpututil"
 	"github.com/hndada/gosu/audios"
+	"github.com/hndada/gosu/format/osr"
 	"github.com/hndada/gosu/input"
 )
 
@@ -30,9 +31,11 @@ type Timer struct {
 	MaxTick int // A tick corresponding to EndTime = Duration + WaitAfter
 	Now     int64
 	Pause   bool
+	Quit    bool    // Set when quitting at pause menu, or failing.
+	Rate    float64 // Playback rate of music. Now goes in real time regardless.
 }
 
-func NewTimer(duration int64) Timer {
+func NewTimer(duration int64, rate float64) Timer {
 	return Timer{
 		StartTime: time.Now().Add(Wait * time.Millisecond),
 		Offset:    int64(Offset),
@@ -40,19 +43,19 @@ func NewTimer(duration int64) Timer {
 		Tick:    TimeToTick(-Wait),
 		MaxTick: TimeToTick(duration + Wait),
 		Now:     -Wait,
+		Rate:    rate,
 	}
 }
 
 //	func (t Timer) IsDone() bool {
 //		return ebiten.IsKeyPressed(ebiten.KeyEscape) || t.Tick >= t.MaxTick // time.Since(t.StartTime) >= t.Duration
 //	}
-func (t Timer) IsDone() bool { return ebiten.IsKeyPressed(ebiten.KeyEscape) }
 
-// func (t *Timer) SwitchPause() {}
+// IsDone reports whether the play has ended: played to the end, or quit.
+func (t Timer) IsDone() bool { return t.Quit || t.Tick >= t.MaxTick }
+
+// Timer is paused by ScenePause.
 func (t *Timer) Ticker() {
-	if inpututil.IsKeyJustPressed(ebiten.KeyTab) {
-		t.Pause = !t.Pause
-	}
 	if t.Pause {
 		return
 	}
@@ -94,8 +97,10 @@ type MusicPlayer struct {
 	pause  bool
 }
 
-func NewMusicPlayer(path string, timer *Timer) (MusicPlayer, error) {
-	player, closer, err := audios.NewPlayer(path)
+// Music is played at the rate of timer, so that position of the player
+// goes along with Now. Pitch changes along with the rate with nightcore.
+func NewMusicPlayer(path string, timer *Timer, nightcore bool) (MusicPlayer, error) {
+	player, closer, err := audios.NewPlayerWithRate(path, timer.Rate, nightcore)
 	if err != nil {
 		return MusicPlayer{}, err
 	}
@@ -131,7 +136,9 @@ func (p *MusicPlayer) Update() {
 		}
 	} else {
 		if p.pause {
-			p.Player.Play()
+			if p.Now >= p.Offset { // Music waits for its start after seeking.
+				p.Player.Play()
+			}
 			p.pause = false
 		}
 	}
@@ -172,19 +179,59 @@ func (p MusicPlayer) Close() {
 // 	}
 // }
 
+// KeyLogger records key states as replay actions.
+// Actions is nil when not recording, e.g., playing a replay.
 type KeyLogger struct {
 	FetchPressed func() []bool
 	LastPressed  []bool
 	Pressed      []bool
+	Actions      []osr.Action
+	actionTime   int64 // Time of the last action.
+	recordTime   int64 // The latest time of recording.
 }
 
+// Replay starts with a blank action at 0ms.
 func NewKeyLogger(keySettings []input.Key) (k KeyLogger) {
 	keyCount := len(keySettings)
 	k.FetchPressed = input.NewListener(keySettings)
 	k.LastPressed = make([]bool, keyCount)
 	k.Pressed = make([]bool, keyCount)
+	k.Actions = []osr.Action{{}}
+	k.recordTime = math.MinInt64
 	return
 }
+
+// Record appends an action when it differs from the last one.
+// W of given action is ignored; it is set with elapsed time.
+func (l *KeyLogger) Record(now int64, a osr.Action) {
+	if l.Actions == nil {
+		return
+	}
+	// Time goes backward when resuming from pause with lead-in.
+	// Recording waits until the time gets back, so that W is never negative.
+	if now < l.recordTime {
+		return
+	}
+	l.recordTime = now
+	last := l.Actions[len(l.Actions)-1]
+	if a.X == last.X && a.Y == last.Y && a.Z == last.Z {
+		return
+	}
+	a.W = now - l.actionTime
+	l.actionTime = now
+	l.Actions = append(l.Actions, a)
+}
+
+// Reset clears pressed states and recorded actions for retrying.
+func (l *KeyLogger) Reset() {
+	l.LastPressed = make([]bool, len(l.LastPressed))
+	l.Pressed = make([]bool, len(l.Pressed))
+	if l.Actions != nil {
+		l.Actions = []osr.Action{{}}
+	}
+	l.actionTime = 0
+	l.recordTime = math.MinInt64
+}
 func (l KeyLogger) KeyAction(k int) input.KeyAction {
 	return input.CurrentKeyAction(l.LastPressed[k], l.Pressed[k])
 }
@@ -0,0 +1,183 @@
+package gosu
+
+import (
+	"fmt"
+	"math"
+	"strings"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/ebitenutil"
+	"github.com/hajimehoshi/ebiten/v2/inpututil"
+)
+
+// Practicable is a play scene which can be started over from any time.
+type Practicable interface {
+	Scene
+	// Reset makes notes from the time ready to be judged again.
+	// It is called after the timer has moved.
+	Reset(from int64)
+	PracticeFields() PracticeFields
+}
+
+// PracticeFields are the parts of a play scene that practice controls.
+type PracticeFields struct {
+	Timer       *Timer
+	MusicPlayer *MusicPlayer
+	Scorer      *Scorer
+	KeyLogger   *KeyLogger
+	ChartHeader ChartHeader
+	TransPoints []*TransPoint
+	Breaks      []Break // Intro is included.
+}
+
+// ScenePractice wraps a play scene with seeking and looping.
+// Replays are not exported and the play does not fail at practice.
+type ScenePractice struct {
+	play   Practicable
+	fields PracticeFields
+
+	Start    int64
+	Bookmark int // Index of the next bookmark.
+	LoopA    int64
+	LoopB    int64
+	Looping  bool
+	Sections []float64 // Accuracy of each looped section.
+	// Whether the play has gone from the loop start without seeking.
+	// Only such passes are recorded as sections.
+	inPass bool
+}
+
+func NewScenePractice(scene Scene) (Scene, error) {
+	play, ok := scene.(Practicable)
+	if !ok {
+		return nil, fmt.Errorf("practice not supported: %T", scene)
+	}
+	s := &ScenePractice{
+		play:   play,
+		fields: play.PracticeFields(),
+		Start:  -Wait,
+	}
+	s.fields.KeyLogger.Actions = nil
+	s.fields.Scorer.Gauge.NoFail = true
+	return s, nil
+}
+
+func (s *ScenePractice) Update() any {
+	now := s.fields.Timer.Now
+	switch {
+	case inpututil.IsKeyJustPressed(ebiten.KeyF1): // Restart.
+		s.SeekTo(s.Start, PracticeLeadIn)
+	case inpututil.IsKeyJustPressed(ebiten.KeyF2):
+		s.SeekTo(s.rewind(now, RewindMeasures), PracticeLeadIn)
+	case inpututil.IsKeyJustPressed(ebiten.KeyF3):
+		bookmarks := s.fields.ChartHeader.Bookmarks
+		if len(bookmarks) > 0 {
+			s.Start = bookmarks[s.Bookmark%len(bookmarks)]
+			s.Bookmark = (s.Bookmark + 1) % len(bookmarks)
+			s.SeekTo(s.Start, PracticeLeadIn)
+		}
+	case inpututil.IsKeyJustPressed(ebiten.KeyF4):
+		s.Start = now
+	case inpututil.IsKeyJustPressed(ebiten.KeyF6):
+		s.LoopA = now
+		s.Looping = s.LoopA < s.LoopB
+		s.inPass = false
+	case inpututil.IsKeyJustPressed(ebiten.KeyF7):
+		s.LoopB = now
+		s.Looping = s.LoopA < s.LoopB
+		if s.Looping {
+			s.seekLoop()
+		}
+	case inpututil.IsKeyJustPressed(ebiten.KeyF8):
+		s.Looping = false
+		s.Sections = s.Sections[:0]
+	case inpututil.IsKeyJustPressed(ebiten.KeyF9):
+		s.SeekTo(now-PracticeSeekDuration, 0)
+	case inpututil.IsKeyJustPressed(ebiten.KeyF10):
+		s.SeekTo(now+PracticeSeekDuration, 0)
+	}
+	// Retrying at pause menu goes back further than resuming does.
+	if s.fields.Timer.Now < s.LoopA-PracticeLeadIn-ResumeLeadIn {
+		s.inPass = false
+	}
+	if s.Looping && s.fields.Timer.Now >= s.LoopB {
+		// Ratios are 1 when no notes have been judged.
+		if sc := s.fields.Scorer; s.inPass && sc.Weights[Acc] > 0 {
+			s.Sections = append(s.Sections, sc.Ratios[Acc])
+		}
+		s.seekLoop()
+	}
+	return s.play.Update()
+}
+
+// seekLoop starts a pass of the loop.
+func (s *ScenePractice) seekLoop() {
+	s.SeekTo(s.LoopA, PracticeLeadIn)
+	s.inPass = true
+}
+
+// SeekTo moves the play to given time, with lead-in before it.
+// Notes from the time are judged again, and the score restarts.
+// The current pass of the loop is not recorded after seeking.
+func (s *ScenePractice) SeekTo(t, leadIn int64) {
+	s.inPass = false
+	end := TickToTime(s.fields.Timer.MaxTick)
+	if end > 0 && t > end {
+		t = end
+	}
+	now := t - leadIn
+	if now < -Wait {
+		now = -Wait
+	}
+	s.fields.Timer.SeekTo(now)
+	s.fields.MusicPlayer.SeekTo(now)
+	s.play.Reset(t)
+	s.fields.Scorer.Reset()
+}
+
+// rewind returns the start of the measure which is given measures before
+// the measure including the time. Measures begin at TransPoints with NewBeat.
+func (s ScenePractice) rewind(t int64, measures int) int64 {
+	tps := s.fields.TransPoints
+	if len(tps) == 0 {
+		return t
+	}
+	t = measureStart(tps[0].FetchByTime(t), t)
+	for i := 0; i < measures; i++ {
+		t = measureStart(tps[0].FetchByTime(t-1), t-1)
+	}
+	return t
+}
+func measureStart(tp *TransPoint, t int64) int64 {
+	for !tp.NewBeat && tp.Prev != nil {
+		tp = tp.Prev
+	}
+	d := tp.BeatDuration()
+	if d <= 0 || math.IsInf(d, 0) { // BPM is not positive.
+		return tp.Time
+	}
+	k := math.Floor(float64(t-tp.Time) / d)
+	return tp.Time + int64(k*d)
+}
+
+func (s ScenePractice) Draw(screen *ebiten.Image) {
+	s.play.Draw(screen)
+	var b strings.Builder
+	fmt.Fprintf(&b, "Practice\n"+
+		"Restart (F1): %.3fs\nRewind %d measures (F2)\n"+
+		"Next bookmark (F3): %d\nSet start (F4)\n\n"+
+		"Loop start (F6): %.3fs\nLoop end (F7): %.3fs\nClear loop (F8)\nLooping: %v\n\n"+
+		"Seek (F9/F10): %.0fs\n\n",
+		float64(s.Start)/1000, RewindMeasures,
+		len(s.fields.ChartHeader.Bookmarks),
+		float64(s.LoopA)/1000, float64(s.LoopB)/1000, s.Looping,
+		float64(PracticeSeekDuration)/1000)
+	sections := s.Sections
+	if len(sections) > 10 {
+		sections = sections[len(sections)-10:]
+	}
+	for i, acc := range sections {
+		fmt.Fprintf(&b, "Section %d: %.2f%%\n", len(s.Sections)-len(sections)+i+1, acc*100)
+	}
+	ebitenutil.DebugPrintAt(screen, b.String(), screenSizeX-240, 0)
+}
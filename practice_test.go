@@ -0,0 +1,67 @@
+package gosu
+
+import "testing"
+
+// 120 BPM in 4/4 from 0ms, then 3/4 from 8000ms. A point without
+// NewBeat at 5000ms changes only speed, hence does not begin a measure.
+func testTransPoints() []*TransPoint {
+	return NewTransPoints([]TimingPoint{
+		{Time: 0, BPM: 120, Meter: 4, NewBeat: true},
+		{Time: 5000, BPM: 120, Meter: 4, Speed: 2},
+		{Time: 8000, BPM: 120, Meter: 3, NewBeat: true},
+	})
+}
+
+func TestMeasureStart(t *testing.T) {
+	tps := testTransPoints()
+	for _, tc := range []struct {
+		t, want int64
+	}{
+		{0, 0},
+		{1999, 0},
+		{2000, 2000},
+		{5500, 4000},
+		{7999, 6000},
+		{8000, 8000},
+		{9499, 8000},
+		{9500, 9500},
+		{-1, -2000}, // Before the first point.
+	} {
+		tp := tps[0].FetchByTime(tc.t)
+		if got := measureStart(tp, tc.t); got != tc.want {
+			t.Errorf("measureStart(%d) = %d, want %d", tc.t, got, tc.want)
+		}
+	}
+}
+
+func TestMeasureStartZeroBPM(t *testing.T) {
+	tps := NewTransPoints([]TimingPoint{{Time: 1000, BPM: 0, Meter: 4, NewBeat: true}})
+	if got := measureStart(tps[0], 5000); got != 1000 {
+		t.Errorf("got %d, want 1000", got)
+	}
+}
+
+func TestRewind(t *testing.T) {
+	s := ScenePractice{fields: PracticeFields{TransPoints: testTransPoints()}}
+	for _, tc := range []struct {
+		t        int64
+		measures int
+		want     int64
+	}{
+		{5500, 0, 4000},
+		{5500, 1, 2000},
+		{5500, 2, 0},
+		{9600, 1, 8000},
+		{9600, 2, 6000}, // Across a meter change.
+		{9600, 3, 4000},
+		{8000, 1, 6000},
+	} {
+		if got := s.rewind(tc.t, tc.measures); got != tc.want {
+			t.Errorf("rewind(%d, %d) = %d, want %d", tc.t, tc.measures, got, tc.want)
+		}
+	}
+	empty := ScenePractice{}
+	if got := empty.rewind(1234, 2); got != 1234 {
+		t.Errorf("rewind without points = %d, want 1234", got)
+	}
+}
@@ -1,11 +1,14 @@
 package gosu
 
 import (
+	"crypto/md5"
+	"encoding/hex"
 	"fmt"
 	"os"
 	"path/filepath"
 
 	"github.com/hndada/gosu/format/osr"
+	"github.com/hndada/gosu/format/osu"
 )
 
 // Todo: Make own ScenePlay for calculating score from input replay file
@@ -35,3 +38,77 @@ func LoadReplays(replayRoot string) ([]*osr.Format, error) {
 	}
 	return rfs, nil
 }
+
+// GameVersion is written to exported replays.
+const GameVersion = 20230101
+
+// NewReplay makes an osu! replay with common values of a play.
+// Judgment counts and FullCombo should be set by each mode.
+// Times of actions are converted to times of music as osu! does,
+// and the seed action with mods is appended at the end.
+// Times are converted cumulatively so that rounding errors do not add up.
+func NewReplay(gameMode int, r Result, actions []osr.Action) *osr.Format {
+	actions2 := make([]osr.Action, len(actions), len(actions)+1)
+	var playTime, musicTime int64
+	for i, a := range actions {
+		playTime += a.W
+		t := r.Mods.MusicTime(playTime)
+		a.W = t - musicTime
+		musicTime = t
+		actions2[i] = a
+	}
+	actions2 = append(actions2, r.Mods.SeedAction())
+	return &osr.Format{
+		GameMode:    int8(gameMode),
+		GameVersion: GameVersion,
+		BeatmapMD5:  hex.EncodeToString(r.MD5[:]),
+		PlayerName:  Username,
+		Score:       int32(r.Scores[Total]),
+		Combo:       int16(r.MaxCombo),
+		ModsBits:    r.Mods.Bits(),
+		TimeStamp:   osr.NewTimeStamp(r.PlayedTime),
+		ReplayData:  actions2,
+	}
+}
+
+// ReplayActions returns actions of the replay in times of play.
+// Seed action at the end is excluded.
+func ReplayActions(f *osr.Format, mods Mods) []osr.Action {
+	actions := make([]osr.Action, 0, len(f.ReplayData))
+	var musicTime, playTime int64
+	for _, a := range f.ReplayData {
+		if a.W == osr.SeedActionTime {
+			continue
+		}
+		musicTime += a.W
+		t := musicTime
+		if mods.Rate != 0 {
+			t = int64(float64(musicTime) / mods.Rate)
+		}
+		a.W = t - playTime
+		playTime = t
+		actions = append(actions, a)
+	}
+	return actions
+}
+
+// ExportReplay writes the replay to ReplayRoot.
+// File name follows osu!'s: "Player - Artist - Title [Chart] (Date_Time) Mode.osr".
+// A number is added to the name when a replay of the same name exists.
+func ExportReplay(f *osr.Format, c ChartHeader) error {
+	modeNames := map[int8]string{osu.ModeTaiko: "Taiko", osu.ModeCatch: "CatchTheBeat", osu.ModeMania: "OsuMania"}
+	date := f.Time().Format("2006-01-02_15-04-05")
+	name := fmt.Sprintf("%s - %s - %s [%s] (%s) %s",
+		f.PlayerName, c.Artist, c.MusicName, c.ChartName, date, modeNames[f.GameMode])
+	name = sanitizeFilename(name)
+	sum := md5.Sum([]byte(fmt.Sprintf("%d%s%s%d", f.Combo, f.BeatmapMD5, f.PlayerName, f.TimeStamp)))
+	f.ReplayMD5 = hex.EncodeToString(sum[:])
+	dat, err := f.Encode()
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(ReplayRoot, os.ModePerm); err != nil {
+		return err
+	}
+	return os.WriteFile(uniquePath(filepath.Join(ReplayRoot, name), ".osr"), dat, 0644)
+}
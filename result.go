@@ -7,22 +7,32 @@ import "time"
 type Result struct {
 	MD5        [16]byte  // MD5 for raw chart file. md5.Size = 16
 	PlayedTime time.Time // Finish time of playing.
+	Mods       Mods
 
 	ScoreFactors   [3]float64 // Retrieved from the chart.
 	Scores         [4]float64
 	JudgmentCounts []int
 	MaxCombo       int
+	Gauge          int
+	Cleared        bool
+	GaugeHistory   []float64
+	Pauses         []int64 // Times of pausing.
+	PauseAbuses    int     // The number of pauses out of intro and breaks.
 	// FlowMarks      []float64 // Length is around 100 ~ 200.
 	// KeyLogs []KeyLog // Entire timed-log key strokes.
 }
 
-func (s Scorer) NewResult(md5 [16]byte) Result {
+func (s Scorer) NewResult(md5 [16]byte, mods Mods) Result {
 	return Result{
 		MD5:            md5,
 		PlayedTime:     time.Now(),
+		Mods:           mods,
 		ScoreFactors:   s.ScoreFactors,
 		Scores:         s.Scores,
 		JudgmentCounts: s.JudgmentCounts,
 		MaxCombo:       s.MaxCombo,
+		Gauge:          s.Gauge.Type,
+		Cleared:        s.Gauge.Cleared() && s.Finished(),
+		GaugeHistory:   s.Gauge.History,
 	}
 }
@@ -1,25 +1,34 @@
 package gosu
 
 import (
+	"os"
 	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/hndada/gosu/audios"
 
 	"github.com/hndada/gosu/format/osu"
 )
 
 type Sample struct {
-	Name   string // aka SampleFilename.
-	Volume float64
+	Name    string // aka SampleFilename.
+	Default string // Name of sample in skin, played when Name is not found.
+	Volume  float64
 }
 
-func NewSample(f any) (s Sample) {
-	switch f := f.(type) {
-	case osu.HitObject:
-		return Sample{
-			Name:   f.HitSample.Filename,
-			Volume: float64(f.HitSample.Volume) / 100,
-		}
+// newOsuSamples resolves hit sounds of the hit object.
+func newOsuSamples(f *osu.Format, ho osu.HitObject) []Sample {
+	hss := f.HitSoundSamples(ho)
+	ss := make([]Sample, 0, len(hss))
+	for _, hs := range hss {
+		ss = append(ss, Sample{
+			Name:    hs.Filename,
+			Default: hs.Default,
+			Volume:  float64(hs.Volume) / 100,
+		})
 	}
-	return Sample{}
+	return ss
 }
 
 func (n Sample) Path(cpath string) (string, bool) {
@@ -28,3 +37,88 @@ func (n Sample) Path(cpath string) (string, bool) {
 	}
 	return filepath.Join(filepath.Dir(cpath), n.Name), true
 }
+
+// HitSoundDirs returns folders of default samples in order.
+// Samples of osu! skin go first when OsuSkinRoot is set.
+func HitSoundDirs() []string {
+	dirs := make([]string, 0, 2)
+	if OsuSkinRoot != "" {
+		dirs = append(dirs, OsuSkinRoot)
+	}
+	return append(dirs, DefaultHitSoundRoot)
+}
+
+// LoadSamples registers samples to the sound map.
+// A sample is looked up at the folder by its name first,
+// then at skin folders by its default name.
+// Pass no skin folders when the mode has its own default sounds.
+func LoadSamples(sm audios.SoundMap, dir string, samples []Sample, skinDirs []string) {
+	for _, s := range samples {
+		if s.Name != "" && !sm.Has(s.Name) {
+			if registerSample(sm, s.Name, []string{dir}) {
+				continue
+			}
+		}
+		if s.Default != "" && !sm.Has(s.Default) {
+			registerSample(sm, s.Default, skinDirs)
+		}
+	}
+}
+
+// registerSample registers the first sample file found at the folders.
+// Extension of sample file is often different from the written one.
+func registerSample(sm audios.SoundMap, name string, dirs []string) bool {
+	base := strings.TrimSuffix(name, filepath.Ext(name))
+	for _, dir := range dirs {
+		for _, ext := range []string{filepath.Ext(name), ".ogg", ".wav", ".mp3"} {
+			b, err := os.ReadFile(filepath.Join(dir, base+ext))
+			if err != nil {
+				continue
+			}
+			if err := sm.RegisterBytes(name, b, ext); err == nil {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// PlaySample plays the sample, or its default when the sample is not loaded.
+// Volume of the sample goes first; vol is for samples with no volume.
+// It returns false when neither is loaded.
+func PlaySample(sm audios.SoundMap, s Sample, vol float64) bool {
+	return PlaySampleFrom(sm, s, vol, 0)
+}
+
+// PlaySampleFrom plays the sample from the offset in milliseconds.
+// It returns false when the sample has ended at the offset.
+func PlaySampleFrom(sm audios.SoundMap, s Sample, vol float64, offset int64) bool {
+	if s.Volume != 0 {
+		vol = s.Volume
+	}
+	for _, name := range []string{s.Name, s.Default} {
+		if name != "" && sm.Has(name) {
+			return sm.PlayFrom(name, vol, time.Duration(offset)*time.Millisecond)
+		}
+	}
+	return false
+}
+
+// PlaySamples plays all samples at once. Hit sounds of osu! are
+// made of a normal sound and additions.
+func PlaySamples(sm audios.SoundMap, samples []Sample, vol float64) (played bool) {
+	for _, s := range samples {
+		if PlaySample(sm, s, vol) {
+			played = true
+		}
+	}
+	return
+}
+
+// BGM is a sample played automatically regardless of input.
+// BMS and O2Jam charts are made of keysounds and BGMs instead of a music file.
+// Quaver calls them sound effects.
+type BGM struct {
+	Time int64
+	Sample
+}
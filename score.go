@@ -83,6 +83,7 @@ type Scorer struct {
 	MaxScores      [4]float64
 	JudgmentCounts []int
 	MaxCombo       int
+	Gauge          Gauge
 }
 
 func NewScorer(scoreFactors [3]float64) Scorer {
@@ -100,6 +101,28 @@ func (s *Scorer) SetMaxScores(maxScores [4]float64) {
 	s.ScoreBounds = maxScores
 	s.MaxScores = maxScores
 }
+
+// Reset clears the progress while keeping the bounds of the chart.
+func (s *Scorer) Reset() {
+	g := s.Gauge
+	g.Reset()
+	*s = Scorer{
+		Flow:           1,
+		Ratios:         [3]float64{1, 1, 1},
+		MaxWeights:     s.MaxWeights,
+		ScoreFactors:   s.ScoreFactors,
+		ScoreBounds:    s.MaxScores,
+		MaxScores:      s.MaxScores,
+		JudgmentCounts: make([]int, len(s.JudgmentCounts)),
+		Gauge:          g,
+	}
+}
+
+// Finished reports whether all notes have been judged.
+// Weights are compared with a margin for float errors.
+func (s Scorer) Finished() bool {
+	return s.Weights[Flow] >= s.MaxWeights[Flow]-1e-6
+}
 func (s *Scorer) AddCombo() {
 	s.Combo++
 	if s.MaxCombo < s.Combo {
@@ -4,11 +4,13 @@ import (
 	"fmt"
 	"image/color"
 	"io"
+	"math"
 	"sort"
 
 	"github.com/hajimehoshi/ebiten/v2"
 	"github.com/hajimehoshi/ebiten/v2/audio"
 	"github.com/hajimehoshi/ebiten/v2/ebitenutil"
+	"github.com/hajimehoshi/ebiten/v2/inpututil"
 	"github.com/hajimehoshi/ebiten/v2/text"
 	"github.com/hndada/gosu/audios"
 	"github.com/hndada/gosu/ctrl"
@@ -43,7 +45,34 @@ func (s *SceneSelect) Update() any {
 	if set := s.CursorKeyHandler.Update() || BrightKeyHandler.Update(); set {
 		s.UpdateBackground()
 	}
-	if ebiten.IsKeyPressed(ebiten.KeyEnter) || ebiten.IsKeyPressed(ebiten.KeyNumpadEnter) {
+	for i := range ModKeyHandlers {
+		ModKeyHandlers[i].Update()
+	}
+	PracticeKeyHandler.Update()
+	if inpututil.IsKeyJustPressed(ebiten.KeyF5) { // Rescan music root.
+		Rescan()
+		s.UpdateMode()
+	}
+	if inpututil.IsKeyJustPressed(ebiten.KeyF6) && len(s.View) > 0 { // Save as native chart file.
+		fpath, err := ConvertChartFile(s.View[s.Cursor].Path)
+		if err != nil {
+			fmt.Printf("error at converting %s: %s\n", s.View[s.Cursor].Path, err)
+		} else {
+			fmt.Printf("converted: %s\n", fpath)
+		}
+	}
+	if inpututil.IsKeyJustPressed(ebiten.KeyD) && len(s.View) > 0 { // Auto plays the chart.
+		mods := currentMods
+		mods.Rate = math.Round(mods.Rate*100) / 100
+		mods.Auto = true
+		return SelectToPlayArgs{
+			Path: s.View[s.Cursor].Path,
+			Mods: mods,
+			Demo: true,
+		}
+	}
+	// Enter held from pause menu does not start the song again.
+	if inpututil.IsKeyJustPressed(ebiten.KeyEnter) || inpututil.IsKeyJustPressed(ebiten.KeyNumpadEnter) {
 		audios.PlayEffect(SelectSound, EffectVolume)
 		prop := modeProps[currentMode]
 		info := prop.ChartInfos[s.Cursor]
@@ -56,9 +85,16 @@ func (s *SceneSelect) Update() any {
 		// if err != nil {
 		// 	panic(err)
 		// }
+		mods := currentMods
+		mods.Rate = math.Round(mods.Rate*100) / 100 // Rate handler accumulates float errors.
+		if replay != nil {
+			mods = NewModsFromReplay(replay)
+		}
 		return SelectToPlayArgs{
-			Path:   info.Path,
-			Replay: replay,
+			Path:     info.Path,
+			Mods:     mods,
+			Replay:   replay,
+			Practice: isPractice && replay == nil,
 		}
 	}
 	return nil
@@ -180,20 +216,31 @@ func (s SceneSelect) DebugPrint(screen *ebiten.Image) {
 		fmt.Sprintf(
 			"Mode (F1): %s\n"+
 				"Sort (F2): %s\n"+
+				"Practice (F3): %v\n"+
+				"Demo (D)\n"+
+				"Save as %s (F6)\n"+
 				"\n"+
 				"Music volume (Alt+ Left/Right): %.0f%%\n"+
 				"Effect volume (Ctrl+ Left/Right): %.0f%%\n"+
 				"Brightness (Ctrl+ O/P): %.0f%%\n"+
 				"\n"+
 				"Speed (PageUp/Down): %.0f (Exposure time: %.0fms)\n"+
-				"Offset (Shift+ Left/Right): %dms\n",
+				"Offset (Shift+ Left/Right): %dms\n"+
+				"\n"+
+				"Mods: %s\n"+
+				"Rate (-/=), Random (R), Mirror (M), NoFail (N), Auto (A), Gauge (G)\n"+
+				"Hidden (H), FadeIn (I), Flashlight (L), Nightcore (C)\n",
 			prop.Name,
 			[]string{"by name", "by level"}[currentSort],
+			isPractice,
+			ChartFileExt,
 
 			MusicVolume*100,
 			EffectVolume*100,
 			BackgroundBrightness*100,
 
 			speed*100, prop.ExposureTime(speed),
-			Offset))
+			Offset,
+
+			currentMods))
 }
@@ -12,8 +12,39 @@ import (
 
 var (
 	MusicRoot   = "music"
+	ReplayRoot  = "replay" // Plays are exported to as osu! replays.
+	Username    = "gosu"
+	OsuRoot     = "" // Scores and collections are imported from osu! when set.
+	OsuSkinRoot = "" // Skins are overridden with osu! skin in the folder when set.
 	WindowSizeX = 1600
 	WindowSizeY = 900
+
+	// Default hit sounds, such as normal-hitnormal.wav, are in the folder.
+	DefaultHitSoundRoot = "skin/sound/hit"
+
+	// Charts with these key counts are generated from a MIDI file.
+	MIDIKeyCounts = []int{4, 7}
+	// MIDI notes longer than the beats are converted to long notes.
+	MIDILongNoteBeats = 1.0
+
+	// Auto plays like a human with these. Auto is perfect when all are zero.
+	AutoDeviation   = 0.0 // Standard deviation of timing error in milliseconds.
+	AutoMissRate    = 0.0
+	AutoReleaseRate = 0.0 // Probability of releasing a long note early.
+	// Plays with Auto are exported as replays when set.
+	ExportAutoReplay = false
+	// Auto plays the same chart along silently at practice when set.
+	PracticeOpponent = false
+
+	// Play resumes after the countdown, from the lead-in before the paused time.
+	ResumeCountdown int64 = 1500
+	ResumeLeadIn    int64 = 1000
+	FailDuration    int64 = 2000 // Music fades out while failing.
+
+	// Practice starts a bit before the point to give time to get ready.
+	PracticeLeadIn       int64 = 1500
+	PracticeSeekDuration int64 = 5000
+	RewindMeasures             = 2
 )
 var (
 	// TPS supposed to be multiple of 1000, since only one speed value
@@ -76,6 +107,13 @@ func SetKeySettings(props []ModeProp) {
 					break
 				}
 			}
+		case "Karaoke", "karaoke":
+			for _, prop := range props {
+				if strings.Contains(strings.ToLower(prop.Name), "karaoke") {
+					prop.KeySettings[2] = keys
+					break
+				}
+			}
 		default:
 			subMode, err := strconv.Atoi(mode)
 			if err != nil {
@@ -72,6 +72,9 @@ func LoadGeneralSkin() {
 		}
 		ScoreSprites[i] = s
 	}
+	if f, ok := LoadOsuSkin(); ok {
+		loadOsuScoreSprites(f.ScorePrefix)
+	}
 	for i, name := range []string{"dot", "comma", "percent"} {
 		s := draws.NewSprite(fmt.Sprintf("skin/score/%s.png", name))
 		s.SetScale(ScoreScale)
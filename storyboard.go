@@ -0,0 +1,402 @@
+package gosu
+
+import (
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hndada/gosu/draws"
+	"github.com/hndada/gosu/format/osu"
+)
+
+// Storyboard is drawn in 640x480 at the center of screen.
+const (
+	storyboardSizeX = 640
+	storyboardSizeY = 480
+)
+
+// StoryboardDrawer draws osu! storyboard behind the playfield.
+// Fail layer is drawn while failing, and Pass layer otherwise.
+type StoryboardDrawer struct {
+	Time    int64
+	Objects []*StoryboardObject
+	Failing bool
+}
+
+type StoryboardObject struct {
+	osu.Object
+	StartTime int
+	EndTime   int
+	Commands  map[string][]osu.Command // Key is command type. Loops are unrolled.
+	Triggers  []osu.Command
+	Triggered map[int][]osu.Command // Key is trigger group.
+	Sprites   []draws.Sprite        // Animation has more than one sprite.
+}
+
+// NewStoryboardDrawer loads storyboard of the chart's .osb first,
+// then of the chart itself. Only .osu chart has storyboard.
+func NewStoryboardDrawer(cpath string) (d StoryboardDrawer) {
+	if strings.ToLower(filepath.Ext(cpath)) != ".osu" {
+		return
+	}
+	dir := filepath.Dir(cpath)
+	var paths []string
+	if path, ok := osbPath(cpath); ok {
+		paths = append(paths, path)
+	}
+	paths = append(paths, cpath)
+	images := make(map[string]*ebiten.Image)
+	for _, path := range paths {
+		dat, err := os.ReadFile(path)
+		if err != nil {
+			fmt.Println(err)
+			continue
+		}
+		sb, err := osu.ParseStoryboard(dat)
+		if err != nil {
+			fmt.Printf("error at parsing storyboard %s: %s\n", filepath.Base(path), err)
+		}
+		for _, o := range sb.Objects {
+			if so := newStoryboardObject(dir, o, images); so != nil {
+				d.Objects = append(d.Objects, so)
+			}
+		}
+	}
+	sort.SliceStable(d.Objects, func(i, j int) bool {
+		return layerIndex(d.Objects[i].Layer) < layerIndex(d.Objects[j].Layer)
+	})
+	return
+}
+
+// osbPath returns the .osb shared by charts of the set: "Artist - Title (Creator).osb"
+// for "Artist - Title (Creator) [Version].osu". Other .osb files in the folder are of other sets.
+func osbPath(cpath string) (string, bool) {
+	name := strings.TrimSuffix(filepath.Base(cpath), filepath.Ext(cpath))
+	if i := strings.LastIndex(name, " ["); i >= 0 {
+		name = name[:i]
+	}
+	paths, _ := filepath.Glob(filepath.Join(filepath.Dir(cpath), "*.osb"))
+	for _, path := range paths {
+		if strings.EqualFold(strings.TrimSuffix(filepath.Base(path), filepath.Ext(path)), name) {
+			return path, true
+		}
+	}
+	return "", false
+}
+
+func layerIndex(layer string) int {
+	for i, l := range osu.Layers {
+		if l == layer {
+			return i
+		}
+	}
+	return len(osu.Layers)
+}
+
+// Images are shared among objects.
+func newStoryboardObject(dir string, o osu.Object, images map[string]*ebiten.Image) *StoryboardObject {
+	so := &StoryboardObject{
+		Object:    o,
+		StartTime: math.MaxInt32,
+		EndTime:   math.MinInt32,
+		Commands:  make(map[string][]osu.Command),
+		Triggered: make(map[int][]osu.Command),
+	}
+	for _, c := range o.Commands {
+		switch c.Type {
+		case "L":
+			for _, c2 := range c.Unroll() {
+				so.addCommand(c2)
+			}
+		case "T":
+			so.Triggers = append(so.Triggers, c)
+		default:
+			so.addCommand(c)
+		}
+	}
+	if len(so.Commands) == 0 && len(so.Triggers) == 0 {
+		return nil
+	}
+	for _, cs := range so.Commands {
+		sort.SliceStable(cs, func(i, j int) bool { return cs[i].StartTime < cs[j].StartTime })
+	}
+
+	path := filepath.Join(dir, filepath.FromSlash(strings.ReplaceAll(o.Filepath, `\`, "/")))
+	paths := []string{path}
+	if o.Type == "Animation" {
+		paths = make([]string, o.FrameCount)
+		ext := filepath.Ext(path)
+		for i := range paths {
+			paths[i] = fmt.Sprintf("%s%d%s", strings.TrimSuffix(path, ext), i, ext)
+		}
+	}
+	for _, path := range paths {
+		i, ok := images[path]
+		if !ok {
+			i = draws.NewImage(path)
+			images[path] = i
+		}
+		if i == nil {
+			return nil
+		}
+		so.Sprites = append(so.Sprites, draws.NewSpriteFromImage(i))
+	}
+	return so
+}
+
+// Move is split into MX and MY, so that each property has its own type.
+func (so *StoryboardObject) addCommand(c osu.Command) {
+	if c.Type == "M" {
+		cx, cy := c, c
+		cx.Type, cx.StartValues, cx.EndValues = "MX", c.StartValues[:1], c.EndValues[:1]
+		cy.Type, cy.StartValues, cy.EndValues = "MY", c.StartValues[1:], c.EndValues[1:]
+		so.addCommand(cx)
+		so.addCommand(cy)
+		return
+	}
+	so.Commands[c.Type] = append(so.Commands[c.Type], c)
+	if so.StartTime > c.StartTime {
+		so.StartTime = c.StartTime
+	}
+	if so.EndTime < c.EndTime {
+		so.EndTime = c.EndTime
+	}
+}
+
+// Trigger activates trigger commands which listen to the event,
+// such as "Passing", at the time. Newly triggered commands
+// replace previous ones in the same group.
+// HitSound event fires triggers whose conditions meet the hit sound.
+func (d StoryboardDrawer) Trigger(event string, time int64) {
+	t := int(time)
+	played, isHitSound := osu.ParseHitSoundTrigger(event)
+	for _, so := range d.Objects {
+		for _, c := range so.Triggers {
+			if t < c.StartTime || t > c.EndTime {
+				continue
+			}
+			if isHitSound {
+				if cond, ok := osu.ParseHitSoundTrigger(c.TriggerName); !ok || !cond.Match(played) {
+					continue
+				}
+			} else if !strings.HasPrefix(c.TriggerName, event) {
+				continue
+			}
+			so.Triggered[c.Group] = c.Shift(t)
+		}
+	}
+}
+
+// TriggerHitSound triggers events of the samples played at once.
+func (d StoryboardDrawer) TriggerHitSound(samples []Sample, time int64) {
+	for _, event := range hitSoundEvents(samples) {
+		d.Trigger(event, time)
+	}
+}
+
+// hitSoundEvents returns an event for each osu! hit sound,
+// such as "HitSoundSoftDrumClap2": sample set, addition set, addition and index.
+// Samples not of osu! hit sounds make a plain "HitSound" event.
+func hitSoundEvents(samples []Sample) []string {
+	type hitSound struct {
+		set, addition string
+		index         int
+	}
+	var (
+		hss              []hitSound
+		set, additionSet string
+	)
+	for _, s := range samples {
+		// Default name is like "soft-hitclap.wav".
+		base := strings.TrimSuffix(s.Default, filepath.Ext(s.Default))
+		kv := strings.SplitN(base, "-hit", 2)
+		if len(kv) < 2 || kv[0] == "" || kv[1] == "" {
+			continue
+		}
+		hs := hitSound{set: title(kv[0]), addition: title(kv[1])}
+		// Name is like "soft-hitclap2.wav", or empty when it is skin only.
+		if s.Name != "" {
+			hs.index = 1
+			suffix := strings.TrimPrefix(strings.TrimSuffix(s.Name, filepath.Ext(s.Name)), base)
+			if i, err := strconv.Atoi(suffix); err == nil {
+				hs.index = i
+			}
+		}
+		if hs.addition == "Normal" {
+			set = hs.set
+		} else if additionSet == "" {
+			additionSet = hs.set
+		}
+		hss = append(hss, hs)
+	}
+	if len(hss) == 0 {
+		return []string{"HitSound"}
+	}
+	if set == "" {
+		set = additionSet
+	}
+	if additionSet == "" {
+		additionSet = set
+	}
+	events := make([]string, len(hss))
+	for i, hs := range hss {
+		events[i] = fmt.Sprintf("HitSound%s%s%s%d", set, additionSet, hs.addition, hs.index)
+	}
+	return events
+}
+
+func title(s string) string { return strings.ToUpper(s[:1]) + s[1:] }
+
+// SetFailing triggers Failing or Passing when the state has changed.
+func (d *StoryboardDrawer) SetFailing(failing bool, time int64) {
+	if d.Failing == failing {
+		return
+	}
+	d.Failing = failing
+	event := "Passing"
+	if failing {
+		event = "Failing"
+	}
+	d.Trigger(event, time)
+}
+
+func (d *StoryboardDrawer) Update(time int64) { d.Time = time }
+
+// value returns values of the property at the time.
+// Before the first command, the start values of it are used.
+func (so StoryboardObject) value(kind string, time int, values []float64) []float64 {
+	cs := so.Commands[kind]
+	if len(cs) > 0 {
+		values = cs[0].StartValues
+		for _, c := range cs {
+			if c.StartTime > time {
+				break
+			}
+			values = c.Values(time)
+		}
+	}
+	for _, cs := range so.Triggered {
+		for _, c := range cs {
+			if c.Type == kind && c.StartTime <= time && time <= c.EndTime {
+				values = c.Values(time)
+			}
+		}
+	}
+	return values
+}
+
+// Object with triggered commands is drawn even if it has no other commands.
+func (so StoryboardObject) isActive(time int) bool {
+	if so.StartTime <= time && time <= so.EndTime {
+		return true
+	}
+	for _, cs := range so.Triggered {
+		for _, c := range cs {
+			if c.StartTime <= time && time <= c.EndTime {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// parameter returns whether the parameter is on at the time.
+// Parameter with the same start and end time lasts permanently.
+func (so StoryboardObject) parameter(p string, time int) bool {
+	for _, c := range so.Commands["P"] {
+		if c.Parameter != p || c.StartTime > time {
+			continue
+		}
+		if time < c.EndTime || c.StartTime == c.EndTime {
+			return true
+		}
+	}
+	return false
+}
+
+func (so StoryboardObject) frame(time int) int {
+	if len(so.Sprites) == 1 || so.FrameDelay <= 0 {
+		return 0
+	}
+	i := int(float64(time-so.StartTime) / so.FrameDelay)
+	if i < 0 {
+		return 0
+	}
+	if so.LoopType == osu.LoopOnce && i >= len(so.Sprites) {
+		return len(so.Sprites) - 1
+	}
+	return i % len(so.Sprites)
+}
+
+func (d StoryboardDrawer) Draw(screen *ebiten.Image) {
+	t := int(d.Time)
+	scale := float64(screenSizeY) / storyboardSizeY
+	offsetX := (screenSizeX - storyboardSizeX*scale) / 2
+	for _, so := range d.Objects {
+		if so.Layer == osu.LayerFail && !d.Failing || so.Layer == osu.LayerPass && d.Failing {
+			continue
+		}
+		if !so.isActive(t) {
+			continue
+		}
+		alpha := so.value("F", t, []float64{1})[0]
+		if alpha <= 0 {
+			continue
+		}
+		var (
+			x   = so.value("MX", t, []float64{so.X})[0]
+			y   = so.value("MY", t, []float64{so.Y})[0]
+			s   = so.value("S", t, []float64{1})[0]
+			v   = so.value("V", t, []float64{1, 1})
+			r   = so.value("R", t, []float64{0})[0]
+			clr = so.value("C", t, []float64{255, 255, 255})
+		)
+		sx, sy := s*v[0], s*v[1]
+		if sx == 0 || sy == 0 {
+			continue
+		}
+		if so.parameter("H", t) {
+			sx *= -1
+		}
+		if so.parameter("V", t) {
+			sy *= -1
+		}
+
+		sprite := so.Sprites[so.frame(t)]
+		ox, oy := storyboardOrigin(so.Origin, sprite.W(), sprite.H())
+		op := &ebiten.DrawImageOptions{}
+		op.GeoM.Translate(-ox, -oy)
+		op.GeoM.Scale(sx, sy)
+		op.GeoM.Rotate(r)
+		op.GeoM.Scale(scale, scale)
+		op.GeoM.Translate(offsetX+x*scale, y*scale)
+		op.ColorM.Scale(clr[0]/255, clr[1]/255, clr[2]/255, alpha)
+		if so.parameter("A", t) {
+			op.CompositeMode = ebiten.CompositeModeLighter
+		}
+		sprite.Draw(screen, op)
+	}
+}
+
+// Custom origin is regarded as TopLeft.
+func storyboardOrigin(origin string, w, h float64) (float64, float64) {
+	var ox, oy float64
+	switch {
+	case strings.HasSuffix(origin, "Centre"):
+		ox = w / 2
+	case strings.HasSuffix(origin, "Right"):
+		ox = w
+	}
+	switch {
+	case strings.HasPrefix(origin, "Centre"):
+		oy = h / 2
+	case strings.HasPrefix(origin, "Bottom"):
+		oy = h
+	}
+	return ox, oy
+}
@@ -0,0 +1,46 @@
+package gosu
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestHitSoundEvents(t *testing.T) {
+	for _, tc := range []struct {
+		name    string
+		samples []Sample
+		want    []string
+	}{
+		{"additions", []Sample{
+			{Name: "soft-hitnormal.wav", Default: "soft-hitnormal.wav"},
+			{Name: "drum-hitclap2.wav", Default: "drum-hitclap.wav"},
+		}, []string{"HitSoundSoftDrumNormal1", "HitSoundSoftDrumClap2"}},
+		{"skin only", []Sample{
+			{Default: "normal-hitnormal.wav"},
+		}, []string{"HitSoundNormalNormalNormal0"}},
+		{"custom filename", []Sample{{Name: "kick.wav"}}, []string{"HitSound"}},
+		{"no samples", nil, []string{"HitSound"}},
+	} {
+		if got := hitSoundEvents(tc.samples); !reflect.DeepEqual(got, tc.want) {
+			t.Errorf("%s: got %v, want %v", tc.name, got, tc.want)
+		}
+	}
+}
+
+func TestOsbPath(t *testing.T) {
+	dir := t.TempDir()
+	for _, name := range []string{"A - B (C).osb", "A - B (D).osb"} {
+		if err := os.WriteFile(filepath.Join(dir, name), nil, 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	path, ok := osbPath(filepath.Join(dir, "A - B (D) [Hard].osu"))
+	if !ok || filepath.Base(path) != "A - B (D).osb" {
+		t.Errorf("got %s, %v", path, ok)
+	}
+	if _, ok := osbPath(filepath.Join(dir, "A - B (E) [Hard].osu")); ok {
+		t.Error("found .osb of another set")
+	}
+}
@@ -3,11 +3,10 @@ package gosu
 import (
 	"math"
 	"sort"
-
-	"github.com/hndada/gosu/format/osu"
 )
 
-type TransPoint struct {
+// TimingPoint is a point where BPM, speed, meter or volume changes.
+type TimingPoint struct {
 	Time      int64
 	BPM       float64
 	Speed     float64
@@ -15,70 +14,102 @@ type TransPoint struct {
 	NewBeat   bool    // NewBeat draws a bar.
 	Volume    float64 // Range is [0, 1].
 	Highlight bool
+}
 
+// TransPoint is a TimingPoint linked to its neighbors.
+type TransPoint struct {
+	TimingPoint
 	Position float64
 	Next     *TransPoint
 	Prev     *TransPoint
 }
 
-// First BPM is used as temporary main BPM.
-// No two TransPoints have same Time.
-func NewTransPoints(f any) []*TransPoint {
-	var transPoints []*TransPoint
-	switch f := f.(type) {
-	case *osu.Format:
-		sort.SliceStable(f.TimingPoints, func(i int, j int) bool {
-			if f.TimingPoints[i].Time == f.TimingPoints[j].Time {
-				return f.TimingPoints[i].Uninherited
-			}
-			return f.TimingPoints[i].Time < f.TimingPoints[j].Time
-		})
-		// Inherited points without Uninherited points will go dropped.
-		for len(f.TimingPoints) > 0 && !f.TimingPoints[0].Uninherited {
-			f.TimingPoints = f.TimingPoints[1:]
+// NewTransPoints links copies of timing points.
+func NewTransPoints(tps []TimingPoint) []*TransPoint {
+	transPoints := make([]*TransPoint, len(tps))
+	var prev *TransPoint
+	for i, tp := range tps {
+		transPoints[i] = &TransPoint{TimingPoint: tp, Prev: prev}
+		if prev != nil {
+			prev.Next = transPoints[i]
 		}
-		if len(f.TimingPoints) == 0 {
-			return transPoints
+		prev = transPoints[i]
+	}
+	return transPoints
+}
+
+// Points at the same time are processed in the order of kinds.
+const (
+	kindStopEnd = iota
+	kindBPM
+	kindScroll
+	kindMeasure
+	kindStopStart
+)
+
+// measurePoint is for formats which place objects at fractions of measures.
+// Value is BPM at kindBPM, speed multiplier at kindScroll,
+// and length of measure at kindMeasure.
+type measurePoint struct {
+	time  float64
+	kind  int
+	value float64
+}
+
+func newMeasureTimingPoints(tempMainBPM float64, points []measurePoint) []TimingPoint {
+	sort.SliceStable(points, func(i, j int) bool {
+		if points[i].time == points[j].time {
+			return points[i].kind < points[j].kind
 		}
-		tempMainBPM := f.TimingPoints[0].BPM()
-		transPoints = make([]*TransPoint, 0, len(f.TimingPoints))
-		prevBPM := tempMainBPM
-		for _, timingPoint := range f.TimingPoints {
-			tp := &TransPoint{
-				Time:      int64(timingPoint.Time),
-				BPM:       prevBPM,
-				Speed:     prevBPM / tempMainBPM,
-				Meter:     timingPoint.Meter,
-				NewBeat:   timingPoint.Uninherited,
-				Volume:    float64(timingPoint.Volume) / 100,
-				Highlight: timingPoint.IsKiai(),
+		return points[i].time < points[j].time
+	})
+	var (
+		bpm      = tempMainBPM
+		scroll   = 1.0
+		meter    = 4
+		stopping bool
+	)
+	tps := make([]TimingPoint, 0, len(points))
+	for _, p := range points {
+		var newBeat bool
+		switch p.kind {
+		case kindStopEnd:
+			stopping = false
+		case kindBPM:
+			bpm = p.value
+		case kindScroll:
+			scroll = p.value
+		case kindMeasure:
+			newBeat = true
+			// Todo: bars are not exact when a measure is not in multiple of beats.
+			meter = int(math.Ceil(4 * p.value))
+			if meter < 1 {
+				meter = 1
 			}
-			if timingPoint.Uninherited {
-				tp.BPM = timingPoint.BPM()
-				tp.Speed = tp.BPM / tempMainBPM
-			} else {
-				tp.Speed *= timingPoint.BeatLengthScale()
-			}
-			if len(transPoints) > 0 && transPoints[len(transPoints)-1].Time == tp.Time { // Drop a TransPoint with a same time
-				tp.NewBeat = transPoints[len(transPoints)-1].NewBeat || tp.NewBeat
-				transPoints = transPoints[:len(transPoints)-1]
-			}
-			transPoints = append(transPoints, tp)
-			prevBPM = tp.BPM
+		case kindStopStart:
+			stopping = true
 		}
-	}
-	var prev *TransPoint
-	for _, tp := range transPoints {
-		tp.Prev = prev
-		if prev != nil {
-			prev.Next = tp
+		tp := TimingPoint{
+			Time:    int64(p.time),
+			BPM:     bpm,
+			Speed:   bpm / tempMainBPM * scroll,
+			Meter:   meter,
+			NewBeat: newBeat,
+			Volume:  1,
+		}
+		if stopping {
+			tp.Speed = 0
 		}
-		prev = tp
+		if len(tps) > 0 && tps[len(tps)-1].Time == tp.Time { // Drop a TimingPoint with a same time
+			tp.NewBeat = tps[len(tps)-1].NewBeat || tp.NewBeat
+			tps = tps[:len(tps)-1]
+		}
+		tps = append(tps, tp)
 	}
-	return transPoints
+	return tps
 }
 
-func (tp TransPoint) BeatDuration() float64 {
+func (tp TimingPoint) BeatDuration() float64 {
 	return float64(tp.Meter) * (60000 / tp.BPM)
 }
 func (tp *TransPoint) FetchByTime(time int64) *TransPoint {